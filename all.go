@@ -5,9 +5,15 @@ import (
 	_ "github.com/artela-network/galxe-integration/notifier/slack"
 
 	// indexers
+	_ "github.com/artela-network/galxe-integration/indexer/aggregation"
+	_ "github.com/artela-network/galxe-integration/indexer/archive"
+	_ "github.com/artela-network/galxe-integration/indexer/aspect"
 	_ "github.com/artela-network/galxe-integration/indexer/fail"
 	_ "github.com/artela-network/galxe-integration/indexer/generic_rule_based"
+	_ "github.com/artela-network/galxe-integration/indexer/native"
+	_ "github.com/artela-network/galxe-integration/indexer/nft"
 	_ "github.com/artela-network/galxe-integration/indexer/noop"
+	_ "github.com/artela-network/galxe-integration/indexer/quest"
 	_ "github.com/artela-network/galxe-integration/indexer/scored_event"
 
 	// db
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+// buildAirdrop snapshots the rewarded task completions of a campaign and stores the merkle tree
+func (s *Server) buildAirdrop(c *gin.Context) {
+	query := &biz.AirdropQuery{}
+	if err := c.ShouldBindBodyWith(query, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid airdrop " + err.Error(),
+		})
+		return
+	}
+	existing, err := biz.GetAirdrop(s.db, query.Name)
+	if err != nil {
+		log.Errorf("Failed to get airdrop %s: %v", query.Name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to build airdrop",
+		})
+		return
+	}
+	if existing != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Airdrop " + query.Name + " already exists",
+		})
+		return
+	}
+
+	airdrop, err := biz.BuildAirdrop(s.db, query)
+	if err != nil {
+		log.Errorf("Failed to build airdrop %s: %v", query.Name, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to build airdrop " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    airdrop,
+	})
+}
+
+// publishAirdrop queues the root of the airdrop for the distributor handler
+func (s *Server) publishAirdrop(c *gin.Context) {
+	err := biz.QueueAirdropPublish(s.db, c.Param("name"))
+	if errors.Is(err, biz.ErrAirdropNotQueueable) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to queue airdrop %s: %v", c.Param("name"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to publish airdrop",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+func (s *Server) airdrops(c *gin.Context) {
+	airdrops, err := biz.GetAirdrops(s.db)
+	if err != nil {
+		log.Errorf("Failed to get airdrops: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get airdrops",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    airdrops,
+	})
+}
+
+func (s *Server) airdrop(c *gin.Context) {
+	airdrop, err := biz.GetAirdrop(s.db, c.Param("name"))
+	if err != nil {
+		log.Errorf("Failed to get airdrop %s: %v", c.Param("name"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get airdrop",
+		})
+		return
+	}
+	if airdrop == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Airdrop " + c.Param("name") + " not found",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    airdrop,
+	})
+}
+
+// airdropProof returns the amount and the merkle proof an address claims the airdrop with
+func (s *Server) airdropProof(c *gin.Context) {
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	proof, err := biz.GetAirdropProof(s.db, c.Param("name"), address)
+	if err != nil {
+		log.Errorf("Failed to get airdrop proof of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get airdrop proof",
+		})
+		return
+	}
+	if proof == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Address " + address + " is not eligible to airdrop " + c.Param("name"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proof,
+	})
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type aspectDeployment struct {
+	AspectID    string `json:"aspectId"`
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+type aspectBinding struct {
+	AspectID    string `json:"aspectId"`
+	Contract    string `json:"contract"`
+	Version     uint64 `json:"version"`
+	Bound       bool   `json:"bound"`
+	OwnAspect   bool   `json:"ownAspect"`
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+// aspectActivity returns the aspects deployed and bound by an address, recorded by the aspect indexer
+func (s *Server) aspectActivity(c *gin.Context) {
+	ethAddress := c.Param("address")
+	if !common.IsHexAddress(ethAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid Ethereum address",
+		})
+		return
+	}
+	address := common.HexToAddress(ethAddress).Hex()
+
+	deployments, err := s.aspectDeployments(address)
+	if err != nil {
+		log.Errorf("Failed to query aspect deployments: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query aspect deployments",
+		})
+		return
+	}
+
+	bindings, err := s.aspectBindings(address)
+	if err != nil {
+		log.Errorf("Failed to query aspect bindings: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query aspect bindings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"deployments": deployments,
+			"bindings":    bindings,
+		},
+	})
+}
+
+func (s *Server) aspectDeployments(address string) ([]aspectDeployment, error) {
+	rows, err := s.db.Query("SELECT aspect_id, tx_hash, block_number FROM aspect_deployments WHERE deployer = $1 ORDER BY block_number", address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deployments := make([]aspectDeployment, 0)
+	for rows.Next() {
+		var d aspectDeployment
+		if err := rows.Scan(&d.AspectID, &d.TxHash, &d.BlockNumber); err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, d)
+	}
+	return deployments, rows.Err()
+}
+
+func (s *Server) aspectBindings(address string) ([]aspectBinding, error) {
+	rows, err := s.db.Query("SELECT b.aspect_id, b.contract, b.version, b.bound, d.deployer IS NOT NULL, b.tx_hash, b.block_number "+
+		"FROM aspect_bindings b LEFT JOIN aspect_deployments d ON b.aspect_id = d.aspect_id AND d.deployer = b.binder "+
+		"WHERE b.binder = $1 ORDER BY b.block_number", address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bindings := make([]aspectBinding, 0)
+	for rows.Next() {
+		var b aspectBinding
+		if err := rows.Scan(&b.AspectID, &b.Contract, &b.Version, &b.Bound, &b.OwnAspect, &b.TxHash, &b.BlockNumber); err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, b)
+	}
+	return bindings, rows.Err()
+}
@@ -0,0 +1,352 @@
+package biz
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/merkle"
+)
+
+type AirdropStatus string
+
+const (
+	AirdropStatusBuilt      AirdropStatus = "built"
+	AirdropStatusQueued     AirdropStatus = "queued"
+	AirdropStatusPublishing AirdropStatus = "publishing"
+	AirdropStatusPublished  AirdropStatus = "published"
+	AirdropStatusFailed     AirdropStatus = "failed"
+)
+
+// airdropBatchSize is the number of leaves inserted per statement
+const airdropBatchSize = 500
+
+var (
+	ErrNoEligibleAddress   = errors.New("no address is eligible to the airdrop")
+	ErrAirdropNotQueueable = errors.New("airdrop not found, or already published or being published")
+)
+
+// AirdropQuery describes the snapshot of an airdrop, each address gets the sum of the rewards of the tasks
+// it succeeded before the snapshot time. Rewards maps task names to amounts in wei.
+type AirdropQuery struct {
+	Name         string            `json:"name"`
+	Campaign     string            `json:"campaign"`
+	Rewards      map[string]string `json:"rewards"`
+	SnapshotTime *time.Time        `json:"snapshotTime"`
+}
+
+// Airdrop is the merkle tree of a snapshot, published to the distributor contract
+type Airdrop struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Campaign     string            `json:"campaign"`
+	Rewards      map[string]string `json:"rewards"`
+	SnapshotTime time.Time         `json:"snapshotTime"`
+	MerkleRoot   string            `json:"merkleRoot"`
+	TotalAmount  string            `json:"totalAmount"`
+	Leaves       int               `json:"leaves"`
+	Status       AirdropStatus     `json:"status"`
+	Txs          string            `json:"txs"`
+	GMTCreate    time.Time         `json:"gmtCreate"`
+	GMTModify    time.Time         `json:"gmtModify"`
+}
+
+// AirdropProof is what an address needs to claim its airdrop from the distributor contract
+type AirdropProof struct {
+	Airdrop    string   `json:"airdrop"`
+	MerkleRoot string   `json:"merkleRoot"`
+	Address    string   `json:"address"`
+	Amount     string   `json:"amount"`
+	Index      int      `json:"index"`
+	Proof      []string `json:"proof"`
+}
+
+// BuildAirdrop takes the snapshot of the query, builds its merkle tree and stores the tree with the proof
+// of every address
+func BuildAirdrop(db *sql.DB, query *AirdropQuery) (*Airdrop, error) {
+	if query.Name == "" {
+		return nil, fmt.Errorf("airdrop must have a name")
+	}
+	campaign, err := GetCatalog().Campaign(query.Campaign)
+	if err != nil {
+		return nil, err
+	}
+	if len(query.Rewards) == 0 {
+		return nil, fmt.Errorf("airdrop must reward at least one task")
+	}
+	rewards := make(map[string]*big.Int, len(query.Rewards))
+	for taskName, raw := range query.Rewards {
+		if _, ok := campaign.Task(taskName); !ok {
+			return nil, fmt.Errorf("campaign %s has no task %s", campaign.Name, taskName)
+		}
+		amount, ok := new(big.Int).SetString(raw, 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid reward %q of task %s", raw, taskName)
+		}
+		rewards[taskName] = amount
+	}
+	snapshotTime := time.Now().UTC()
+	if query.SnapshotTime != nil {
+		snapshotTime = query.SnapshotTime.UTC()
+	}
+
+	leaves, err := snapshotLeaves(db, campaign.Name, rewards, snapshotTime)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) == 0 {
+		return nil, ErrNoEligibleAddress
+	}
+	tree, err := merkle.NewTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(big.Int)
+	for _, leaf := range leaves {
+		total.Add(total, leaf.Amount)
+	}
+	rawRewards, _ := json.Marshal(query.Rewards)
+	airdrop := &Airdrop{
+		Name:         query.Name,
+		Campaign:     campaign.Name,
+		Rewards:      query.Rewards,
+		SnapshotTime: snapshotTime,
+		MerkleRoot:   tree.Root().Hex(),
+		TotalAmount:  total.String(),
+		Leaves:       len(leaves),
+		Status:       AirdropStatusBuilt,
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("INSERT INTO airdrops (name, campaign, rewards, snapshot_time, merkle_root, total_amount, leaves, status) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, gmt_create, gmt_modify",
+		airdrop.Name, airdrop.Campaign, string(rawRewards), airdrop.SnapshotTime, airdrop.MerkleRoot, airdrop.TotalAmount,
+		airdrop.Leaves, string(airdrop.Status)).Scan(&airdrop.ID, &airdrop.GMTCreate, &airdrop.GMTModify)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(tree.Leaves()); start += airdropBatchSize {
+		end := start + airdropBatchSize
+		if end > len(tree.Leaves()) {
+			end = len(tree.Leaves())
+		}
+		var queryBuilder strings.Builder
+		args := []interface{}{airdrop.ID}
+		queryBuilder.WriteString("INSERT INTO airdrop_leaves (airdrop_id, leaf_index, account_address, amount, proof) VALUES ")
+		for i := start; i < end; i++ {
+			if i > start {
+				queryBuilder.WriteString(",")
+			}
+			proof, _ := json.Marshal(hexProof(tree.Proof(i)))
+			leaf := tree.Leaves()[i]
+			queryBuilder.WriteString(fmt.Sprintf("($1, $%d, $%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3, len(args)+4))
+			args = append(args, i, leaf.Account.Hex(), leaf.Amount.String(), string(proof))
+		}
+		if _, err := tx.Exec(queryBuilder.String(), args...); err != nil {
+			return nil, err
+		}
+	}
+	return airdrop, tx.Commit()
+}
+
+// snapshotLeaves sums the rewards of the tasks each address last succeeded before the snapshot time
+func snapshotLeaves(db *sql.DB, campaign string, rewards map[string]*big.Int, snapshotTime time.Time) ([]*merkle.Leaf, error) {
+	taskNames := make([]string, 0, len(rewards))
+	for taskName := range rewards {
+		taskNames = append(taskNames, taskName)
+	}
+	sort.Strings(taskNames)
+
+	args := []interface{}{campaign, string(types.TaskStatusSuccess), snapshotTime}
+	placeholders := make([]string, len(taskNames))
+	for i, taskName := range taskNames {
+		args = append(args, taskName)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	rows, err := db.Query("SELECT account_address, task_name FROM address_tasks WHERE campaign = $1 AND task_status = $2 "+
+		"AND "+completedAt("address_tasks")+" < $3 AND task_name IN ("+strings.Join(placeholders, ",")+") ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	amounts := make(map[string]*merkle.Leaf)
+	var order []string
+	for rows.Next() {
+		var address, taskName string
+		if err := rows.Scan(&address, &taskName); err != nil {
+			return nil, err
+		}
+		canonical, err := common.CanonicalAddress(address)
+		if err != nil {
+			log.Warnf("airdrop: skipping invalid address %s", address)
+			continue
+		}
+		leaf, ok := amounts[canonical]
+		if !ok {
+			leaf = &merkle.Leaf{Amount: new(big.Int)}
+			leaf.Account = ethcommon.HexToAddress(canonical)
+			amounts[canonical] = leaf
+			order = append(order, canonical)
+		}
+		leaf.Amount.Add(leaf.Amount, rewards[taskName])
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	leaves := make([]*merkle.Leaf, len(order))
+	for i, address := range order {
+		leaves[i] = amounts[address]
+	}
+	return leaves, nil
+}
+
+func hexProof(proof []ethcommon.Hash) []string {
+	hexes := make([]string, len(proof))
+	for i, node := range proof {
+		hexes[i] = node.Hex()
+	}
+	return hexes
+}
+
+const airdropColumns = "id, name, campaign, rewards, snapshot_time, merkle_root, total_amount::TEXT, leaves, status, txs, " +
+	"gmt_create, gmt_modify"
+
+const selectAirdrop = "SELECT " + airdropColumns + " FROM airdrops "
+
+// GetAirdrop returns the airdrop with the name, nil if there is none
+func GetAirdrop(db *sql.DB, name string) (*Airdrop, error) {
+	rows, err := db.Query(selectAirdrop+"WHERE name = $1", name)
+	if err != nil {
+		return nil, err
+	}
+	airdrops, err := scanAirdrops(rows)
+	if err != nil || len(airdrops) == 0 {
+		return nil, err
+	}
+	return airdrops[0], nil
+}
+
+// GetAirdrops returns the airdrops, latest first
+func GetAirdrops(db *sql.DB) ([]*Airdrop, error) {
+	rows, err := db.Query(selectAirdrop + "ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	return scanAirdrops(rows)
+}
+
+func scanAirdrops(rows *sql.Rows) ([]*Airdrop, error) {
+	defer rows.Close()
+
+	var airdrops []*Airdrop
+	for rows.Next() {
+		airdrop := &Airdrop{}
+		var rewards, status string
+		if err := rows.Scan(&airdrop.ID, &airdrop.Name, &airdrop.Campaign, &rewards, &airdrop.SnapshotTime, &airdrop.MerkleRoot,
+			&airdrop.TotalAmount, &airdrop.Leaves, &status, &airdrop.Txs, &airdrop.GMTCreate, &airdrop.GMTModify); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(rewards), &airdrop.Rewards); err != nil {
+			return nil, err
+		}
+		airdrop.Status = AirdropStatus(status)
+		airdrops = append(airdrops, airdrop)
+	}
+	return airdrops, rows.Err()
+}
+
+// GetAirdropProof returns the proof of the address, nil if the address is not in the airdrop
+func GetAirdropProof(db *sql.DB, name string, address string) (*AirdropProof, error) {
+	address, err := common.CanonicalAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AirdropProof{Airdrop: name}
+	var proof string
+	err = db.QueryRow("SELECT airdrops.merkle_root, airdrop_leaves.account_address, airdrop_leaves.amount::TEXT, "+
+		"airdrop_leaves.leaf_index, airdrop_leaves.proof FROM airdrop_leaves JOIN airdrops ON airdrops.id = airdrop_leaves.airdrop_id "+
+		"WHERE airdrops.name = $1 AND LOWER(airdrop_leaves.account_address) = LOWER($2)", name, address).
+		Scan(&result.MerkleRoot, &result.Address, &result.Amount, &result.Index, &proof)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, json.Unmarshal([]byte(proof), &result.Proof)
+}
+
+// QueueAirdropPublish queues the root of a built airdrop, or of one which failed to publish, for the
+// distributor handler
+func QueueAirdropPublish(db *sql.DB, name string) error {
+	res, err := db.Exec("UPDATE airdrops SET status = $1, gmt_modify = CURRENT_TIMESTAMP WHERE name = $2 AND status IN ($3, $4)",
+		string(AirdropStatusQueued), name, string(AirdropStatusBuilt), string(AirdropStatusFailed))
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = ErrAirdropNotQueueable
+		}
+		return err
+	}
+	return nil
+}
+
+// LockAirdropsToPublish moves up to limit queued airdrops to publishing and returns them
+func LockAirdropsToPublish(db *sql.DB, limit int) ([]*Airdrop, error) {
+	rows, err := db.Query("UPDATE airdrops SET status = $1, gmt_modify = CURRENT_TIMESTAMP WHERE id IN "+
+		"(SELECT id FROM airdrops WHERE status = $2 ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED) RETURNING "+airdropColumns,
+		string(AirdropStatusPublishing), string(AirdropStatusQueued), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAirdrops(rows)
+}
+
+// FailStaleAirdrops fails the airdrops publishing for longer than the timeout, such as the ones whose
+// publisher died or whose txs never got a receipt, and returns their number. They can be queued again.
+func FailStaleAirdrops(db *sql.DB, timeout time.Duration) (int64, error) {
+	res, err := db.Exec("UPDATE airdrops SET status = $1, gmt_modify = CURRENT_TIMESTAMP "+
+		"WHERE status = $2 AND gmt_modify < CURRENT_TIMESTAMP - $3 * interval '1 second'",
+		string(AirdropStatusFailed), string(AirdropStatusPublishing), int64(timeout.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// UpdateAirdropPublication records the txs publishing the root of the airdrop, and once their receipts
+// are in, whether it got published
+func UpdateAirdropPublication(db *sql.DB, id int64, txs string, published *bool) error {
+	status := AirdropStatusPublishing
+	if published != nil {
+		status = AirdropStatusFailed
+		if *published {
+			status = AirdropStatusPublished
+		}
+	}
+	_, err := db.Exec("UPDATE airdrops SET status = $1, txs = $2, gmt_modify = CURRENT_TIMESTAMP WHERE id = $3",
+		string(status), txs, id)
+	return err
+}
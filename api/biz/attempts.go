@@ -0,0 +1,90 @@
+package biz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// kinds of failures of a task, recorded with the reason of its last failure
+const (
+	// FailCodeReceipt is a transaction of the task which did not end as expected
+	FailCodeReceipt = "receipt"
+	// FailCodeSend is a task whose transaction could not be sent
+	FailCodeSend = "send"
+	// FailCodeTimeout is a task held by a handler for longer than its timeout
+	FailCodeTimeout = "timeout"
+)
+
+// taskMaxAttempts returns how many times the task is tried before it is abandoned
+func taskMaxAttempts(task AddressTask) int {
+	if task.Campaign == nil || task.TaskName == nil {
+		return DefaultTaskMaxAttempts
+	}
+	campaign, err := GetCatalog().Campaign(*task.Campaign)
+	if err != nil || *task.Campaign == "" {
+		return DefaultTaskMaxAttempts
+	}
+	catalogTask, ok := campaign.Task(*task.TaskName)
+	if !ok {
+		return DefaultTaskMaxAttempts
+	}
+	return catalogTask.MaxAttempts
+}
+
+// abandonTasks moves the tasks which failed their last attempt from fail to abandoned
+func abandonTasks(exec common.Executor, tasks []AddressTask, transitions []types.TaskTransition) error {
+	args := []interface{}{string(types.TaskStatusAbandoned)}
+	var placeholders []string
+	for i, task := range tasks {
+		if transitions[i] == types.TransitionAbandon {
+			args = append(args, task.ID)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+	}
+	if len(placeholders) == 0 {
+		return nil
+	}
+	_, err := exec.Exec("UPDATE address_tasks SET task_status = $1 WHERE id IN ("+strings.Join(placeholders, ",")+")", args...)
+	return err
+}
+
+// failReason explains the last failure of a failed or abandoned task, empty for the other tasks
+func failReason(task AddressTask, maxAttempts int) string {
+	if task.TaskStatus == nil || (task.FailCode == "" && task.FailReason == "") {
+		return ""
+	}
+	detail := task.FailReason
+	if task.FailCode != "" {
+		detail = strings.TrimSuffix(task.FailCode+": "+task.FailReason, ": ")
+	}
+	switch types.TaskStatus(*task.TaskStatus) {
+	case types.TaskStatusFail:
+		return fmt.Sprintf("attempt %d of %d failed, %s", task.Attempts, maxAttempts, detail)
+	case types.TaskStatusAbandoned:
+		return fmt.Sprintf("abandoned after %d attempts, %s", task.Attempts, detail)
+	default:
+		return ""
+	}
+}
+
+// FailTask fails a task held by a handler, it is abandoned if it was its last attempt
+func FailTask(db *sql.DB, id int64, actor, code, reason string) error {
+	status := string(types.TaskStatusFail)
+	return UpdateTask(db, &UpdateTaskQuery{
+		ID:         id,
+		TaskStatus: &status,
+		Actor:      actor,
+		FailCode:   &code,
+		Reason:     &reason,
+	})
+}
+
+// ResetTask hands an abandoned task back to the handlers with a fresh budget of attempts
+func ResetTask(db *sql.DB, id int64, actor string) (bool, error) {
+	moved, err := moveTasks(db, types.TransitionReset, actor, "", "attempts = 0, job_batch_id = null", "id = $1", id)
+	return moved > 0, err
+}
@@ -0,0 +1,44 @@
+package biz
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/api/types"
+)
+
+func TestFailReason(t *testing.T) {
+	require.NoError(t, LoadCatalog(nil))
+
+	task := func(status types.TaskStatus, attempts int, code, reason string) AddressTask {
+		campaign, name, s := types.Task_Topic_Goplus, types.Task_Name_GetFaucet, string(status)
+		return AddressTask{Campaign: &campaign, TaskName: &name, TaskStatus: &s, Attempts: attempts, FailCode: code, FailReason: reason}
+	}
+
+	failed := task(types.TaskStatusFail, 2, FailCodeReceipt, "receipt status 0")
+	require.Equal(t, DefaultTaskMaxAttempts, taskMaxAttempts(failed))
+	require.Equal(t, "attempt 2 of 5 failed, receipt: receipt status 0", failReason(failed, taskMaxAttempts(failed)))
+
+	abandoned := task(types.TaskStatusAbandoned, 5, FailCodeTimeout, "")
+	require.Equal(t, "abandoned after 5 attempts, timeout", failReason(abandoned, 5))
+
+	// the last failure is kept once the task is retried, but it no longer explains the task
+	require.Empty(t, failReason(task(types.TaskStatusPending, 3, FailCodeSend, "connection refused"), 5))
+	require.Empty(t, failReason(task(types.TaskStatusFail, 1, "", ""), 5))
+}
+
+func TestTimedOutTasks(t *testing.T) {
+	require.NoError(t, LoadCatalog(nil))
+	tasks := len(GetCatalog().Campaigns()[0].Tasks)
+
+	where, args := timedOutTasks(true, []interface{}{FailCodeTimeout})
+	require.Len(t, args, 1+4*tasks+2)
+	require.Equal(t, FailCodeTimeout, args[0])
+	// the tasks missing from the catalog fall back to the defaults
+	require.Contains(t, where, "(COALESCE(campaign, ''), task_name) NOT IN (($2, $3), ")
+	require.Contains(t, where, "attempts >= $"+strconv.Itoa(len(args)-1)+" AND gmt_modify < CURRENT_TIMESTAMP - $"+strconv.Itoa(len(args)))
+	require.Equal(t, DefaultTaskMaxAttempts, args[len(args)-2])
+	require.Equal(t, int64(DefaultTaskTimeout.Seconds()), args[len(args)-1])
+}
@@ -0,0 +1,217 @@
+package biz
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type CampaignStatus string
+
+const (
+	CampaignStatusDraft CampaignStatus = "draft"
+	CampaignStatusLive  CampaignStatus = "live"
+	CampaignStatusEnded CampaignStatus = "ended"
+)
+
+var (
+	ErrCampaignNotLive    = errors.New("campaign is not live")
+	ErrCampaignNotStarted = errors.New("campaign has not started")
+	ErrCampaignEnded      = errors.New("campaign has ended")
+	ErrCampaignFull       = errors.New("campaign has reached its maximum participants")
+)
+
+// CampaignRecord holds the lifecycle of a catalog campaign. Campaigns of the catalog without a record
+// have no window or quota.
+type CampaignRecord struct {
+	Name            string         `json:"name"`
+	StartTime       *time.Time     `json:"startTime"`
+	EndTime         *time.Time     `json:"endTime"`
+	MaxParticipants int64          `json:"maxParticipants"`
+	Status          CampaignStatus `json:"status"`
+	PartnerChannel  string         `json:"partnerChannel"`
+	GMTCreate       time.Time      `json:"gmtCreate"`
+	GMTModify       time.Time      `json:"gmtModify"`
+}
+
+// Open tells why the campaign does not accept registrations or completions at the time, nil if it does
+func (r *CampaignRecord) Open(now time.Time) error {
+	switch {
+	case r.Status == CampaignStatusEnded:
+		return ErrCampaignEnded
+	case r.Status != CampaignStatusLive:
+		return ErrCampaignNotLive
+	case r.StartTime != nil && now.Before(*r.StartTime):
+		return ErrCampaignNotStarted
+	case r.EndTime != nil && !now.Before(*r.EndTime):
+		return ErrCampaignEnded
+	}
+	return nil
+}
+
+func (r *CampaignRecord) validate() error {
+	if _, err := GetCatalog().Campaign(r.Name); err != nil || r.Name == "" {
+		return fmt.Errorf("campaign %q is not in the catalog", r.Name)
+	}
+	switch r.Status {
+	case CampaignStatusDraft, CampaignStatusLive, CampaignStatusEnded:
+	default:
+		return fmt.Errorf("invalid campaign status %q", r.Status)
+	}
+	if r.MaxParticipants < 0 {
+		return fmt.Errorf("invalid max participants %d", r.MaxParticipants)
+	}
+	if r.StartTime != nil && r.EndTime != nil && !r.StartTime.Before(*r.EndTime) {
+		return fmt.Errorf("campaign must start before it ends")
+	}
+	return nil
+}
+
+// TagDefaultCampaign tags the tasks registered before campaigns existed with the default campaign
+func TagDefaultCampaign(db *sql.DB) error {
+	defaultCampaign, err := GetCatalog().Campaign("")
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE address_tasks SET campaign = $1 WHERE campaign = ''", defaultCampaign.Name)
+	return err
+}
+
+// SaveCampaign creates or replaces the record of a catalog campaign
+func SaveCampaign(db *sql.DB, record *CampaignRecord) error {
+	if record.Status == "" {
+		record.Status = CampaignStatusDraft
+	}
+	if err := record.validate(); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`INSERT INTO campaigns (name, start_time, end_time, max_participants, status, partner_channel)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (name) DO UPDATE SET start_time = $2, end_time = $3, max_participants = $4, status = $5,
+        partner_channel = $6, gmt_modify = CURRENT_TIMESTAMP`,
+		record.Name, record.StartTime, record.EndTime, record.MaxParticipants, string(record.Status), record.PartnerChannel)
+	return err
+}
+
+// SetCampaignStatus moves the campaign along its lifecycle draft -> live -> ended
+func SetCampaignStatus(db *sql.DB, name string, status CampaignStatus) error {
+	record, err := GetCampaign(db, name)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("campaign %s not found", name)
+	}
+
+	switch {
+	case record.Status == CampaignStatusDraft && status == CampaignStatusLive:
+	case record.Status == CampaignStatusLive && status == CampaignStatusEnded:
+	case record.Status == CampaignStatusDraft && status == CampaignStatusEnded:
+	default:
+		return fmt.Errorf("campaign %s cannot change from %s to %s", name, record.Status, status)
+	}
+
+	_, err = db.Exec("UPDATE campaigns SET status = $1, gmt_modify = CURRENT_TIMESTAMP WHERE name = $2 AND status = $3",
+		string(status), name, string(record.Status))
+	return err
+}
+
+const selectCampaign = "SELECT name, start_time, end_time, max_participants, status, partner_channel, gmt_create, gmt_modify FROM campaigns "
+
+// GetCampaign returns the record of the campaign, or nil if it has none
+func GetCampaign(db common.Executor, name string) (*CampaignRecord, error) {
+	rows, err := db.Query(selectCampaign+"WHERE name = $1", name)
+	if err != nil {
+		return nil, err
+	}
+	records, err := scanCampaigns(rows)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// GetCampaigns returns the records of all campaigns
+func GetCampaigns(db *sql.DB) ([]*CampaignRecord, error) {
+	rows, err := db.Query(selectCampaign + "ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	return scanCampaigns(rows)
+}
+
+func scanCampaigns(rows *sql.Rows) ([]*CampaignRecord, error) {
+	defer rows.Close()
+
+	var records []*CampaignRecord
+	for rows.Next() {
+		record := &CampaignRecord{}
+		var status string
+		var startTime, endTime sql.NullTime
+		if err := rows.Scan(&record.Name, &startTime, &endTime, &record.MaxParticipants, &status,
+			&record.PartnerChannel, &record.GMTCreate, &record.GMTModify); err != nil {
+			return nil, err
+		}
+		record.Status = CampaignStatus(status)
+		if startTime.Valid {
+			record.StartTime = &startTime.Time
+		}
+		if endTime.Valid {
+			record.EndTime = &endTime.Time
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// CountParticipants returns the number of addresses registered to the campaign
+func CountParticipants(db common.Executor, campaign string) (int64, error) {
+	var count int64
+	err := db.QueryRow("SELECT COUNT(DISTINCT LOWER(account_address)) FROM address_tasks WHERE campaign = $1", campaign).Scan(&count)
+	return count, err
+}
+
+// checkRegistration locks the campaign record in the tx, and refuses the registration if the campaign is
+// not open or full. The lock serializes concurrent registrations so the quota holds, addresses already
+// registered do not count against it.
+func checkRegistration(tx *sql.Tx, campaign string, address string) error {
+	rows, err := tx.Query(selectCampaign+"WHERE name = $1 FOR UPDATE", campaign)
+	if err != nil {
+		return err
+	}
+	records, err := scanCampaigns(rows)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	record := records[0]
+	if err := record.Open(time.Now()); err != nil {
+		return err
+	}
+	if record.MaxParticipants == 0 {
+		return nil
+	}
+	var registered bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM address_tasks WHERE campaign = $1 AND LOWER(account_address) = LOWER($2))",
+		campaign, address).Scan(&registered)
+	if err != nil || registered {
+		return err
+	}
+	count, err := CountParticipants(tx, campaign)
+	if err != nil {
+		return err
+	}
+	if count >= record.MaxParticipants {
+		return ErrCampaignFull
+	}
+	return nil
+}
+
+// campaignOpenCondition is the sql condition on address_tasks which holds while the campaign of the
+// task accepts completions
+const campaignOpenCondition = "NOT EXISTS (SELECT 1 FROM campaigns WHERE campaigns.name = address_tasks.campaign " +
+	"AND (campaigns.status = 'ended' OR campaigns.end_time <= CURRENT_TIMESTAMP))"
@@ -0,0 +1,58 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/api/types"
+)
+
+func TestCampaignOpen(t *testing.T) {
+	now := time.Now()
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		record CampaignRecord
+		err    error
+	}{
+		{name: "live", record: CampaignRecord{Status: CampaignStatusLive}},
+		{name: "in window", record: CampaignRecord{Status: CampaignStatusLive, StartTime: &before, EndTime: &after}},
+		{name: "draft", record: CampaignRecord{Status: CampaignStatusDraft}, err: ErrCampaignNotLive},
+		{name: "ended", record: CampaignRecord{Status: CampaignStatusEnded}, err: ErrCampaignEnded},
+		{name: "not started", record: CampaignRecord{Status: CampaignStatusLive, StartTime: &after}, err: ErrCampaignNotStarted},
+		{name: "past end", record: CampaignRecord{Status: CampaignStatusLive, EndTime: &before}, err: ErrCampaignEnded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.err, tt.record.Open(now))
+		})
+	}
+}
+
+func TestCampaignValidate(t *testing.T) {
+	require.NoError(t, LoadCatalog(nil))
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	valid := CampaignRecord{Name: types.Task_Topic_Goplus, Status: CampaignStatusDraft, StartTime: &now, EndTime: &later}
+	require.NoError(t, valid.validate())
+
+	unknown := valid
+	unknown.Name = "unknown"
+	require.Error(t, unknown.validate())
+
+	reversed := valid
+	reversed.StartTime, reversed.EndTime = &later, &now
+	require.Error(t, reversed.validate())
+
+	badStatus := valid
+	badStatus.Status = "paused"
+	require.Error(t, badStatus.validate())
+
+	negative := valid
+	negative.MaxParticipants = -1
+	require.Error(t, negative.validate())
+}
@@ -0,0 +1,359 @@
+package biz
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/config"
+)
+
+// handlers running the tasks of the catalog, tasks verified by an indexer name the indexer instead
+const (
+	HandlerFaucet  = "faucet"
+	HandlerRug     = "rug"
+	HandlerUpdater = "updater"
+	HandlerSync    = "sync"
+	HandlerManual  = "manual"
+)
+
+// defaults of the tasks which do not set how long they are held and how many times they are tried
+const (
+	DefaultTaskTimeout     = 10 * time.Minute
+	DefaultTaskMaxAttempts = 5
+)
+
+// defaultCampaigns is the catalog used when the config declares no campaign, it holds the goplus tasks
+var defaultCampaigns = []*config.CampaignConfig{
+	{
+		Name: types.Task_Topic_Goplus,
+		Tasks: []*config.CampaignTaskConfig{
+			{Name: types.Task_Name_GetFaucet, Title: "Get Faucet", Order: 1, Topic: types.Task_Topic_Goplus, Required: true, Handler: HandlerFaucet, Points: 10},
+			{Name: types.Task_Name_AddLiquidity, Title: "Add Liquidity", Order: 2, Topic: types.Task_Topic_Goplus, Required: true, Handler: HandlerUpdater, Points: 20,
+				DependsOn: []string{types.Task_Name_GetFaucet}},
+			{Name: types.Task_Name_RugPull, Title: "Rug Pull", Order: 3, Topic: types.Task_Topic_Goplus, Required: true, Handler: HandlerManual, Points: 20,
+				DependsOn: []string{types.Task_Name_GetFaucet}},
+			{Name: types.Task_Name_AspectPull, Title: "Aspect Work", Order: 4, Topic: types.Task_Topic_Goplus, Required: true, Handler: HandlerRug, Points: 50},
+			{Name: types.Task_Name_Sync, Title: "Sync", Order: 5, Topic: types.Task_Topic_Sys, Handler: HandlerSync},
+		},
+	},
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = mustCatalog(defaultCampaigns)
+)
+
+// CampaignTask is a task of a campaign
+type CampaignTask struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Order       int      `json:"order"`
+	Topic       string   `json:"topic"`
+	Required    bool     `json:"required"`
+	Handler     string   `json:"handler"`
+	DependsOn   []string `json:"dependsOn"`
+	Points      int64    `json:"points"`
+	MaxAttempts int      `json:"maxAttempts"`
+
+	timeout time.Duration
+}
+
+// Timeout is how long a handler may hold the task before it is released or abandoned
+func (t *CampaignTask) Timeout() time.Duration {
+	return t.timeout
+}
+
+// Campaign is a set of tasks, completed once all its required tasks succeed, the completions are reported
+// to its partners
+type Campaign struct {
+	Name     string          `json:"name"`
+	Tasks    []*CampaignTask `json:"tasks"`
+	Partners []string        `json:"partners"`
+	// ReferralPoints are credited to the referrer of an address once the address completes the campaign
+	ReferralPoints int64 `json:"referralPoints"`
+
+	tasks map[string]*CampaignTask
+}
+
+// Task returns the task of the campaign with the name
+func (c *Campaign) Task(name string) (*CampaignTask, bool) {
+	task, ok := c.tasks[name]
+	return task, ok
+}
+
+// TaskNames returns the names of all tasks in order
+func (c *Campaign) TaskNames() []string {
+	names := make([]string, len(c.Tasks))
+	for i, task := range c.Tasks {
+		names[i] = task.Name
+	}
+	return names
+}
+
+// RequiredTasks returns the tasks which must succeed for the campaign to be completed
+func (c *Campaign) RequiredTasks() []*CampaignTask {
+	var required []*CampaignTask
+	for _, task := range c.Tasks {
+		if task.Required {
+			required = append(required, task)
+		}
+	}
+	return required
+}
+
+// HandlerTask returns the first task of the campaign run by the handler
+func (c *Campaign) HandlerTask(handler string) (*CampaignTask, bool) {
+	for _, task := range c.Tasks {
+		if task.Handler == handler {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
+// Prerequisites returns the tasks the task depends on
+func (c *Campaign) Prerequisites(task *CampaignTask) []*CampaignTask {
+	prerequisites := make([]*CampaignTask, 0, len(task.DependsOn))
+	for _, name := range task.DependsOn {
+		prerequisites = append(prerequisites, c.tasks[name])
+	}
+	return prerequisites
+}
+
+// checkDependencies makes sure the tasks only depend on tasks of the campaign, without cycles
+func (c *Campaign) checkDependencies() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(c.Tasks))
+	var visit func(task *CampaignTask) error
+	visit = func(task *CampaignTask) error {
+		switch state[task.Name] {
+		case visiting:
+			return fmt.Errorf("task %s of campaign %s depends on itself", task.Name, c.Name)
+		case visited:
+			return nil
+		}
+		state[task.Name] = visiting
+		for _, name := range task.DependsOn {
+			prerequisite, ok := c.tasks[name]
+			if !ok {
+				return fmt.Errorf("task %s of campaign %s depends on unknown task %s", task.Name, c.Name, name)
+			}
+			if err := visit(prerequisite); err != nil {
+				return err
+			}
+		}
+		state[task.Name] = visited
+		return nil
+	}
+
+	for _, task := range c.Tasks {
+		if err := visit(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Catalog holds the campaigns and their tasks, so a new campaign is only a change of config
+type Catalog struct {
+	campaigns []*Campaign
+	byName    map[string]*Campaign
+}
+
+// NewCatalog builds a catalog from the campaign configs, the first campaign is the default one.
+func NewCatalog(confs []*config.CampaignConfig) (*Catalog, error) {
+	if len(confs) == 0 {
+		return nil, fmt.Errorf("catalog must have at least one campaign")
+	}
+
+	c := &Catalog{byName: make(map[string]*Campaign, len(confs))}
+	for _, conf := range confs {
+		if conf.Name == "" {
+			return nil, fmt.Errorf("campaign must have a name")
+		}
+		if _, ok := c.byName[conf.Name]; ok {
+			return nil, fmt.Errorf("duplicated campaign %s", conf.Name)
+		}
+		if len(conf.Tasks) == 0 {
+			return nil, fmt.Errorf("campaign %s has no task", conf.Name)
+		}
+
+		campaign := &Campaign{
+			Name:     conf.Name,
+			Tasks:    make([]*CampaignTask, 0, len(conf.Tasks)),
+			Partners: conf.Partners,
+			tasks:    make(map[string]*CampaignTask, len(conf.Tasks)),
+
+			ReferralPoints: conf.ReferralPoints,
+		}
+		if campaign.ReferralPoints < 0 {
+			return nil, fmt.Errorf("campaign %s has negative referral points", conf.Name)
+		}
+		if len(campaign.Partners) == 0 {
+			campaign.Partners = []string{PartnerGoPlus}
+		}
+		seenPartners := make(map[string]bool, len(campaign.Partners))
+		for _, partner := range campaign.Partners {
+			if partner == "" || seenPartners[partner] {
+				return nil, fmt.Errorf("invalid or duplicated partner %q in campaign %s", partner, conf.Name)
+			}
+			seenPartners[partner] = true
+		}
+		for _, taskConf := range conf.Tasks {
+			if taskConf.Name == "" {
+				return nil, fmt.Errorf("task of campaign %s must have a name", conf.Name)
+			}
+			if _, ok := campaign.tasks[taskConf.Name]; ok {
+				return nil, fmt.Errorf("duplicated task %s in campaign %s", taskConf.Name, conf.Name)
+			}
+			if taskConf.Handler == "" {
+				return nil, fmt.Errorf("task %s of campaign %s has no handler", taskConf.Name, conf.Name)
+			}
+			if taskConf.Points < 0 {
+				return nil, fmt.Errorf("task %s of campaign %s has negative points", taskConf.Name, conf.Name)
+			}
+			if taskConf.MaxAttempts < 0 {
+				return nil, fmt.Errorf("task %s of campaign %s has negative max attempts", taskConf.Name, conf.Name)
+			}
+			timeout := DefaultTaskTimeout
+			if taskConf.Timeout != "" {
+				var err error
+				if timeout, err = time.ParseDuration(taskConf.Timeout); err != nil || timeout <= 0 {
+					return nil, fmt.Errorf("invalid timeout %q of task %s of campaign %s", taskConf.Timeout, taskConf.Name, conf.Name)
+				}
+			}
+			task := &CampaignTask{
+				Name:        taskConf.Name,
+				Title:       taskConf.Title,
+				Description: taskConf.Description,
+				Order:       taskConf.Order,
+				Topic:       taskConf.Topic,
+				Required:    taskConf.Required,
+				Handler:     taskConf.Handler,
+				DependsOn:   taskConf.DependsOn,
+				Points:      taskConf.Points,
+				MaxAttempts: taskConf.MaxAttempts,
+
+				timeout: timeout,
+			}
+			if task.MaxAttempts == 0 {
+				task.MaxAttempts = DefaultTaskMaxAttempts
+			}
+			if task.Title == "" {
+				task.Title = task.Name
+			}
+			if task.Topic == "" {
+				task.Topic = types.Task_Topic_Goplus
+			}
+			campaign.Tasks = append(campaign.Tasks, task)
+			campaign.tasks[task.Name] = task
+		}
+		sort.SliceStable(campaign.Tasks, func(i, j int) bool {
+			return campaign.Tasks[i].Order < campaign.Tasks[j].Order
+		})
+		if len(campaign.RequiredTasks()) == 0 {
+			return nil, fmt.Errorf("campaign %s has no required task", conf.Name)
+		}
+		if err := campaign.checkDependencies(); err != nil {
+			return nil, err
+		}
+
+		c.campaigns = append(c.campaigns, campaign)
+		c.byName[campaign.Name] = campaign
+	}
+	return c, nil
+}
+
+func mustCatalog(confs []*config.CampaignConfig) *Catalog {
+	c, err := NewCatalog(confs)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Campaign returns the campaign with the name, or the default campaign if the name is empty
+func (c *Catalog) Campaign(name string) (*Campaign, error) {
+	if name == "" {
+		return c.campaigns[0], nil
+	}
+	campaign, ok := c.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("campaign %s not found", name)
+	}
+	return campaign, nil
+}
+
+// Campaigns returns all campaigns, the default one first
+func (c *Catalog) Campaigns() []*Campaign {
+	return c.campaigns
+}
+
+// HandlerTaskNames returns the names of the tasks run by the handler in any campaign
+func (c *Catalog) HandlerTaskNames(handler string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, campaign := range c.campaigns {
+		for _, task := range campaign.Tasks {
+			if task.Handler == handler && !seen[task.Name] {
+				seen[task.Name] = true
+				names = append(names, task.Name)
+			}
+		}
+	}
+	return names
+}
+
+// task finds the task in any campaign, the default campaign wins if campaigns share task names
+func (c *Catalog) task(name string) (*CampaignTask, bool) {
+	for _, campaign := range c.campaigns {
+		if task, ok := campaign.Task(name); ok {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
+// taskHandlers returns the handlers of the tasks with the name across the campaigns
+func (c *Catalog) taskHandlers(taskName string) []string {
+	var handlers []string
+	seen := make(map[string]bool)
+	for _, campaign := range c.campaigns {
+		if task, ok := campaign.Task(taskName); ok && !seen[task.Handler] {
+			seen[task.Handler] = true
+			handlers = append(handlers, task.Handler)
+		}
+	}
+	return handlers
+}
+
+// LoadCatalog replaces the catalog with the campaigns of config, the built-in goplus campaign is kept if
+// no campaign is configured.
+func LoadCatalog(confs []*config.CampaignConfig) error {
+	if len(confs) == 0 {
+		confs = defaultCampaigns
+	}
+	c, err := NewCatalog(confs)
+	if err != nil {
+		return err
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog = c
+	return nil
+}
+
+// GetCatalog returns the catalog in use
+func GetCatalog() *Catalog {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return catalog
+}
@@ -0,0 +1,185 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/config"
+)
+
+func TestDefaultCatalog(t *testing.T) {
+	require.NoError(t, LoadCatalog(nil))
+
+	campaign, err := GetCatalog().Campaign("")
+	require.NoError(t, err)
+	require.Equal(t, types.Task_Topic_Goplus, campaign.Name)
+	require.Equal(t, []string{
+		types.Task_Name_GetFaucet,
+		types.Task_Name_AddLiquidity,
+		types.Task_Name_RugPull,
+		types.Task_Name_AspectPull,
+		types.Task_Name_Sync,
+	}, campaign.TaskNames())
+	require.Len(t, campaign.RequiredTasks(), 4)
+
+	syncTask, ok := campaign.HandlerTask(HandlerSync)
+	require.True(t, ok)
+	require.Equal(t, types.Task_Topic_Sys, syncTask.Topic)
+
+	require.Equal(t, []string{types.Task_Name_AddLiquidity}, GetCatalog().HandlerTaskNames(HandlerUpdater))
+	require.Equal(t, "Aspect Work", taskDescription(types.Task_Name_AspectPull).Title)
+}
+
+func TestNewCatalog(t *testing.T) {
+	confs := []*config.CampaignConfig{
+		{
+			Name: "quest",
+			Tasks: []*config.CampaignTaskConfig{
+				{Name: "Vote", Order: 2, Required: true, Handler: "Native"},
+				{Name: "Stake", Order: 1, Required: true, Handler: "Native", Title: "Stake ART", Points: 15, Timeout: "90s", MaxAttempts: 2},
+				{Name: "Mint", Order: 3, Handler: "NFT"},
+			},
+		},
+		{
+			Name: "other",
+			Tasks: []*config.CampaignTaskConfig{
+				{Name: "Vote", Required: true, Handler: "Native"},
+			},
+			Partners: []string{"galxe", PartnerGoPlus},
+		},
+	}
+	c, err := NewCatalog(confs)
+	require.NoError(t, err)
+
+	campaign, err := c.Campaign("")
+	require.NoError(t, err)
+	require.Equal(t, "quest", campaign.Name)
+	require.Equal(t, []string{"Stake", "Vote", "Mint"}, campaign.TaskNames())
+	require.Equal(t, []string{PartnerGoPlus}, campaign.Partners)
+
+	task, ok := campaign.Task("Vote")
+	require.True(t, ok)
+	require.Equal(t, "Vote", task.Title)
+	require.Equal(t, types.Task_Topic_Goplus, task.Topic)
+	require.Zero(t, task.Points)
+	require.Equal(t, DefaultTaskTimeout, task.Timeout())
+	require.Equal(t, DefaultTaskMaxAttempts, task.MaxAttempts)
+
+	task, ok = campaign.Task("Stake")
+	require.True(t, ok)
+	require.Equal(t, int64(15), task.Points)
+	require.Equal(t, 90*time.Second, task.Timeout())
+	require.Equal(t, 2, task.MaxAttempts)
+
+	require.Equal(t, []string{"Stake", "Vote"}, c.HandlerTaskNames("Native"))
+
+	_, err = c.Campaign("missing")
+	require.Error(t, err)
+}
+
+func TestNewCatalogInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		confs []*config.CampaignConfig
+	}{
+		{name: "empty"},
+		{name: "no name", confs: []*config.CampaignConfig{{Tasks: []*config.CampaignTaskConfig{{Name: "A", Required: true, Handler: HandlerManual}}}}},
+		{name: "no task", confs: []*config.CampaignConfig{{Name: "c"}}},
+		{name: "no handler", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{{Name: "A", Required: true}}}}},
+		{name: "no required", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{{Name: "A", Handler: HandlerManual}}}}},
+		{name: "duplicated task", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{
+			{Name: "A", Required: true, Handler: HandlerManual},
+			{Name: "A", Handler: HandlerManual},
+		}}}},
+		{name: "unknown dependency", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{
+			{Name: "A", Required: true, Handler: HandlerManual, DependsOn: []string{"B"}},
+		}}}},
+		{name: "negative points", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{
+			{Name: "A", Required: true, Handler: HandlerManual, Points: -1},
+		}}}},
+		{name: "negative referral points", confs: []*config.CampaignConfig{{Name: "c", ReferralPoints: -5,
+			Tasks: []*config.CampaignTaskConfig{{Name: "A", Required: true, Handler: HandlerManual}}}}},
+		{name: "invalid timeout", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{
+			{Name: "A", Required: true, Handler: HandlerManual, Timeout: "soon"},
+		}}}},
+		{name: "negative max attempts", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{
+			{Name: "A", Required: true, Handler: HandlerManual, MaxAttempts: -1},
+		}}}},
+		{name: "duplicated partner", confs: []*config.CampaignConfig{{Name: "c", Partners: []string{"galxe", "galxe"},
+			Tasks: []*config.CampaignTaskConfig{{Name: "A", Required: true, Handler: HandlerManual}}}}},
+		{name: "dependency cycle", confs: []*config.CampaignConfig{{Name: "c", Tasks: []*config.CampaignTaskConfig{
+			{Name: "A", Required: true, Handler: HandlerManual, DependsOn: []string{"C"}},
+			{Name: "B", Handler: HandlerManual, DependsOn: []string{"A"}},
+			{Name: "C", Handler: HandlerManual, DependsOn: []string{"B"}},
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCatalog(tt.confs)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCalculateStatus(t *testing.T) {
+	campaign, err := mustCatalog(defaultCampaigns).Campaign("")
+	require.NoError(t, err)
+
+	addressTasks := func(statuses map[string]types.TaskStatus) []AddressTask {
+		var tasks []AddressTask
+		for _, name := range campaign.TaskNames() {
+			name, status := name, string(types.TaskStatusNew)
+			if s, ok := statuses[name]; ok {
+				status = string(s)
+			}
+			tasks = append(tasks, AddressTask{TaskName: &name, TaskStatus: &status})
+		}
+		return tasks
+	}
+
+	require.Equal(t, int8(0), calculateStatus(campaign, nil))
+	require.Equal(t, int8(0), calculateStatus(campaign, addressTasks(nil)))
+	require.Equal(t, int8(0), calculateStatus(campaign, addressTasks(map[string]types.TaskStatus{
+		types.Task_Name_Sync: types.TaskStatusSuccess,
+	})))
+	require.Equal(t, int8(2), calculateStatus(campaign, addressTasks(map[string]types.TaskStatus{
+		types.Task_Name_GetFaucet:    types.TaskStatusSuccess,
+		types.Task_Name_AddLiquidity: types.TaskStatusSuccess,
+		types.Task_Name_RugPull:      types.TaskStatusSuccess,
+	})))
+	require.Equal(t, int8(3), calculateStatus(campaign, addressTasks(map[string]types.TaskStatus{
+		types.Task_Name_GetFaucet:    types.TaskStatusSuccess,
+		types.Task_Name_AddLiquidity: types.TaskStatusSuccess,
+		types.Task_Name_RugPull:      types.TaskStatusSuccess,
+		types.Task_Name_AspectPull:   types.TaskStatusSuccess,
+	})))
+}
+
+func TestLockedReason(t *testing.T) {
+	require.NoError(t, LoadCatalog(nil))
+
+	campaign := types.Task_Topic_Goplus
+	task := func(name string, status types.TaskStatus) AddressTask {
+		s := string(status)
+		return AddressTask{TaskName: &name, TaskStatus: &s, Campaign: &campaign}
+	}
+
+	tasks := []AddressTask{
+		task(types.Task_Name_GetFaucet, types.TaskStatusPending),
+		task(types.Task_Name_AddLiquidity, types.TaskStatusPending),
+		task(types.Task_Name_AspectPull, types.TaskStatusNew),
+	}
+	infos := ConvertTaskInfo(tasks)
+	require.False(t, infos[0].Locked)
+	require.True(t, infos[1].Locked)
+	require.Equal(t, "waiting for Get Faucet", infos[1].LockedReason)
+	require.False(t, infos[2].Locked)
+
+	tasks[0] = task(types.Task_Name_GetFaucet, types.TaskStatusSuccess)
+	infos = ConvertTaskInfo(tasks)
+	require.False(t, infos[1].Locked)
+	require.Empty(t, infos[1].LockedReason)
+}
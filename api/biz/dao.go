@@ -10,6 +10,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
 )
 
 type UpdateTaskQuery struct {
@@ -20,11 +21,19 @@ type UpdateTaskQuery struct {
 	Txs        *string `json:"txs" xml:"txs" `
 	TaskId     *string `json:"taskId" xml:"taskId"`
 	JobBatchId *string `json:"jobBatchId" xml:"jobBatchId"`
+	Reason     *string `json:"reason" xml:"reason"`
+	// FailCode tells the kind of failure when the task fails, the reason details it
+	FailCode *string `json:"failCode" xml:"failCode"`
+	// Transition names the transition the status update takes, by default it is found from the statuses
+	Transition types.TaskTransition `json:"-" xml:"-"`
+	// Actor is who updates the task, it is recorded in the history
+	Actor string `json:"-" xml:"-"`
 
 	// where condition
 	ID             int64   `json:"id" xml:"id"`
 	AccountAddress *string `json:"accountAddress" xml:"address"`
 	TaskName       *string `json:"taskName" xml:"taskName" `
+	Campaign       *string `json:"campaign" xml:"campaign"`
 	StatusEqual    *string `json:"statusEqual" xml:"statusEqual"`
 }
 type InitTaskQuery struct {
@@ -32,6 +41,9 @@ type InitTaskQuery struct {
 	TaskId         string `json:"taskId" xml:"taskId"`
 	TaskTopic      string `json:"taskTopic" xml:"taskTopic"`
 	CaptchaToken   string `json:"captchaToken" xml:"captchaToken"`
+	Campaign       string `json:"campaign" xml:"campaign"`
+	// Referrer is the address or the referral code of who invited the address
+	Referrer string `json:"referrer" xml:"referrer"`
 }
 type TaskQuery struct {
 	ID             int64  `json:"id" xml:"id" binding:"required"`
@@ -42,6 +54,7 @@ type TaskQuery struct {
 	TaskName       string `json:"taskName" xml:"taskName"`
 	JobBatchId     string `json:"jobBatchId" xml:"jobBatchId"`
 	LimitNum       int    `json:"limitNum" xml:"limitNum"`
+	Campaign       string `json:"campaign" xml:"campaign"`
 }
 
 type AddressTask struct {
@@ -57,6 +70,11 @@ type AddressTask struct {
 	TaskId     *string `db:"task_id"`
 	TaskTopic  *string `db:"task_topic"`
 	JobBatchId *string `db:"job_batch_id"`
+	Campaign   *string `db:"campaign"`
+	// Attempts counts the times a handler picked the task up, FailCode and FailReason tell why it last failed
+	Attempts   int    `db:"attempts"`
+	FailCode   string `db:"fail_code"`
+	FailReason string `db:"fail_reason"`
 }
 type TaskInfo struct {
 	ID         int64  `json:"id,omitempty"`
@@ -65,72 +83,282 @@ type TaskInfo struct {
 	TaskId     string `json:"taskId"`
 	TaskTopic  string `json:"taskTopic"`
 
-	Title string `json:"title"`
-	Memo  string `json:"memo"`
-	Txs   string `json:"txs"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Memo        string `json:"memo"`
+	Txs         string `json:"txs"`
+	// Locked tells the task waits for its prerequisites, LockedReason names them
+	Locked       bool   `json:"locked"`
+	LockedReason string `json:"lockedReason,omitempty"`
+	// Attempts and MaxAttempts tell how many times the task was tried, FailReason explains its last failure
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"maxAttempts"`
+	FailCode    string `json:"failCode,omitempty"`
+	FailReason  string `json:"failReason,omitempty"`
 }
 
 type AccountTaskInfo struct {
 	AccountAddress string `json:"accountAddress"`
+	Campaign       string `json:"campaign"`
 	// 0:no task 3:part finish 3:completed
 	Status    int8       `json:"status"`
 	TaskInfos []TaskInfo `json:"taskInfos,omitempty"`
 }
 
-func InitTask(db *sql.DB, query *InitTaskQuery) error {
+// InitTask registers the address to the campaign by creating its tasks, in one transaction. It is idempotent,
+// the tasks the address already has are kept, and it returns the number of tasks created. The referrer of
+// the query, if any, is recorded along the registration.
+func InitTask(db *sql.DB, query *InitTaskQuery) (int64, error) {
 	if query.AccountAddress == "" || query.TaskId == "" {
-		return fmt.Errorf("address or TaskId cannot be empty")
-	}
-	// insert db
-	insertSql := "INSERT INTO address_tasks (account_address, task_name,task_status,task_id,task_topic) VALUES " +
-		"($1, $5, '0',$2,$3)," +
-		"($1, $6, '0',$2,$3)," +
-		"($1, $7, '0',$2,$3)," +
-		"($1, $8, '0',$2,$3)," +
-		"($1, $9, '0',$2,$4);"
-
-	_, err := db.Exec(insertSql,
-		query.AccountAddress,
-		query.TaskId,
-		types.Task_Topic_Goplus,
-		types.Task_Topic_Sys,
-		types.Task_Name_GetFaucet,
-		types.Task_Name_AddLiquidity,
-		types.Task_Name_RugPull,
-		types.Task_Name_AspectPull,
-		types.Task_Name_Sync,
-	)
+		return 0, fmt.Errorf("address or TaskId cannot be empty")
+	}
+	address, err := common.CanonicalAddress(query.AccountAddress)
+	if err != nil {
+		return 0, err
+	}
+	campaign, err := GetCatalog().Campaign(query.Campaign)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := checkRegistration(tx, campaign.Name, address); err != nil {
+		return 0, err
+	}
+	referrer := ""
+	if query.Referrer != "" {
+		if referrer, err = resolveReferrer(tx, query.Referrer); err != nil {
+			return 0, err
+		}
+		if err := checkReferral(tx, campaign.Name, referrer, address); err != nil {
+			return 0, err
+		}
+	}
+
+	inserted, _, err := registerTasks(tx, campaign, ActorAPI, []*Registration{{Address: address, TaskId: query.TaskId}})
+	if err != nil {
+		return 0, err
+	}
+	// only a new registration is referred
+	if referrer != "" && inserted > 0 {
+		if err := recordReferral(tx, campaign.Name, referrer, address); err != nil {
+			return 0, err
+		}
+	}
+	return inserted, tx.Commit()
+}
+
+// Registration is an address registering to a campaign, with the id the partner gave its tasks
+type Registration struct {
+	Address string
+	TaskId  string
+}
+
+// registerTasks inserts a row for each task of the campaign and registration, and records their
+// registrations. The tasks the addresses already have are kept, it returns the number of tasks created and
+// the number of addresses they belong to. The completions the indexers recorded before the addresses
+// registered are applied to the new tasks.
+func registerTasks(exec common.Executor, campaign *Campaign, actor string, registrations []*Registration) (int64, int64, error) {
+	if len(registrations) == 0 {
+		return 0, 0, nil
+	}
+
+	var queryBuilder strings.Builder
+	args := []interface{}{string(types.TaskStatusNew), campaign.Name}
+	taskArgs := make([]int, len(campaign.Tasks))
+	for i, task := range campaign.Tasks {
+		taskArgs[i] = len(args) + 1
+		args = append(args, task.Name, task.Topic)
+	}
+	queryBuilder.WriteString("WITH moved AS (INSERT INTO address_tasks (account_address, task_name,task_status,task_id,task_topic,campaign) VALUES ")
+	for i, registration := range registrations {
+		args = append(args, registration.Address, registration.TaskId)
+		address, taskId := len(args)-1, len(args)
+		for j := range campaign.Tasks {
+			if i > 0 || j > 0 {
+				queryBuilder.WriteString(",")
+			}
+			queryBuilder.WriteString(fmt.Sprintf("($%d, $%d, $1, $%d, $%d, $2)", address, taskArgs[j], taskId, taskArgs[j]+1))
+		}
+	}
+	queryBuilder.WriteString(" ON CONFLICT (campaign, (LOWER(account_address)), task_name) DO NOTHING")
+	queryBuilder.WriteString(" RETURNING id, '' AS from_status, account_address, task_name, campaign, txs), history AS (")
+	queryBuilder.WriteString(fmt.Sprintf(historyInsert, len(args)+1, len(args)+2, 1, len(args)+3))
+	queryBuilder.WriteString(") SELECT COUNT(*), COUNT(DISTINCT LOWER(account_address)) FROM moved")
+	args = append(args, string(types.TransitionRegister), actor, "")
+
+	var tasks, addresses int64
+	if err := exec.QueryRow(queryBuilder.String(), args...).Scan(&tasks, &addresses); err != nil {
+		return 0, 0, err
+	}
+	if tasks == 0 {
+		return tasks, addresses, nil
+	}
+	return tasks, addresses, applyCompletions(exec, campaign, registrations)
+}
+
+// recordedCompletion is a completion an indexer recorded for an address
+type recordedCompletion struct {
+	address  string
+	taskName string
+	txs      string
+}
+
+// applyCompletions completes the tasks of the campaign the registering addresses completed before they
+// registered, as recorded by the indexers
+func applyCompletions(exec common.Executor, campaign *Campaign, registrations []*Registration) error {
+	args := []interface{}{campaign.Name, string(types.TaskStatusNew)}
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString("SELECT completion.account_address, completion.task_name, completion.txs FROM task_completions completion ")
+	queryBuilder.WriteString("WHERE EXISTS (SELECT 1 FROM address_tasks WHERE campaign = $1 AND task_status = $2 ")
+	queryBuilder.WriteString("AND task_name = completion.task_name AND LOWER(account_address) = LOWER(completion.account_address)) ")
+	queryBuilder.WriteString("AND LOWER(completion.account_address) IN (")
+	for i, registration := range registrations {
+		if i > 0 {
+			queryBuilder.WriteString(",")
+		}
+		args = append(args, registration.Address)
+		queryBuilder.WriteString(fmt.Sprintf("LOWER($%d)", len(args)))
+	}
+	queryBuilder.WriteString(") ORDER BY completion.id")
+
+	rows, err := exec.Query(queryBuilder.String(), args...)
 	if err != nil {
 		return err
 	}
+	// the rows are read before completing, as the transaction cannot run another statement meanwhile
+	var completions []recordedCompletion
+	for rows.Next() {
+		var completion recordedCompletion
+		if err := rows.Scan(&completion.address, &completion.taskName, &completion.txs); err != nil {
+			rows.Close()
+			return err
+		}
+		completions = append(completions, completion)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, completion := range completions {
+		if _, err := CompleteTaskByName(exec, completion.address, completion.taskName, completion.txs); err != nil {
+			return err
+		}
+	}
 	return nil
 }
-func UpdateTask(db *sql.DB, query *UpdateTaskQuery) error {
-	// 生成 UPDATE 语句
 
+func UpdateTask(db *sql.DB, query *UpdateTaskQuery) error {
 	if query.AccountAddress == nil && query.ID == 0 {
 		return fmt.Errorf("address or id cannot be empty")
 	}
-	if query.ID != 0 && query.TaskStatus != nil {
-		task, err2 := GetTask(db, "", "", query.ID)
-		if err2 != nil {
-			return fmt.Errorf("Task not found.")
+
+	var to types.TaskStatus
+	if query.TaskStatus != nil {
+		to = types.TaskStatus(*query.TaskStatus)
+	} else if query.Transition != "" {
+		to = query.Transition.To()
+	}
+	if to != "" && !to.Valid() {
+		return fmt.Errorf("invalid task status %s", to)
+	}
+	if query.Transition != "" && query.Transition.To() != to {
+		return fmt.Errorf("task cannot %s to status %s", query.Transition, to)
+	}
+	actor := query.Actor
+	if actor == "" {
+		actor = ActorAPI
+	}
+	reason := ""
+	if query.Reason != nil {
+		reason = *query.Reason
+	}
+
+	var whereBuilder strings.Builder
+	var whereArgs []interface{}
+	whereBuilder.WriteString(" WHERE 1=1 ")
+	if query.ID > 0 {
+		whereBuilder.WriteString(" and id = $")
+		whereBuilder.WriteString(fmt.Sprintf("%d ", len(whereArgs)+1))
+		whereArgs = append(whereArgs, query.ID)
+	}
+	if query.AccountAddress != nil {
+		address, err := common.CanonicalAddress(*query.AccountAddress)
+		if err != nil {
+			return err
 		}
-		if query.TaskStatus != nil && task.TaskStatus != nil {
-			// 更新到1 ，原有状态必须是 0,或 4
-			if *query.TaskStatus == "1" && (!(*task.TaskStatus == "0" || *task.TaskStatus == "4")) {
-				return fmt.Errorf("when update  status to 1 Task status is not 0 or 4.")
-			}
-			if *query.TaskStatus == "2" && (*task.TaskStatus != "1") {
-				return fmt.Errorf("when update status to 2 Task status is not 1.")
-			}
-			if *query.TaskStatus == "3" && (!(*task.TaskStatus == "1" || *task.TaskStatus == "2")) {
-				return fmt.Errorf("when update status  to 3  Task status is not 2.")
-			}
-			if *query.TaskStatus == "4" && (*task.TaskStatus != "2") {
-				return fmt.Errorf("when update status to 4 Task status is not 2.")
+		whereBuilder.WriteString(" and LOWER(account_address) = LOWER($")
+		whereBuilder.WriteString(fmt.Sprintf("%d) ", len(whereArgs)+1))
+		whereArgs = append(whereArgs, address)
+	}
+	if query.TaskName != nil {
+		whereBuilder.WriteString(" and task_name = $")
+		whereBuilder.WriteString(fmt.Sprintf("%d ", len(whereArgs)+1))
+		whereArgs = append(whereArgs, query.TaskName)
+	}
+	if query.Campaign != nil {
+		whereBuilder.WriteString(" and campaign = $")
+		whereBuilder.WriteString(fmt.Sprintf("%d ", len(whereArgs)+1))
+		whereArgs = append(whereArgs, query.Campaign)
+	}
+	if query.StatusEqual != nil {
+		whereBuilder.WriteString(" and task_status = $")
+		whereBuilder.WriteString(fmt.Sprintf("%d ", len(whereArgs)+1))
+		whereArgs = append(whereArgs, query.StatusEqual)
+	}
+	// completions after the campaign ends are ignored
+	if to == types.TaskStatusSuccess {
+		whereBuilder.WriteString(" and " + campaignOpenCondition)
+	}
+
+	tx, txErr := db.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	// Defer a rollback in case anything fails.
+	defer tx.Rollback()
+
+	rows, err := tx.Query(selectTask+whereBuilder.String()+" ORDER BY id ASC FOR UPDATE", whereArgs...)
+	if err != nil {
+		return err
+	}
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	// every task must be able to take the transition
+	transitions := make([]types.TaskTransition, len(tasks))
+	for i, task := range tasks {
+		from := types.TaskStatus(*task.TaskStatus)
+		if to == "" || from == to {
+			continue
+		}
+		if query.Transition != "" {
+			if _, err := query.Transition.Apply(from); err != nil {
+				return fmt.Errorf("task %d: %w", task.ID, err)
 			}
+			transitions[i] = query.Transition
+			continue
+		}
+		transition, err := types.UpdateTransition(from, to)
+		if err != nil {
+			return fmt.Errorf("task %d: %w", task.ID, err)
+		}
+		// the task is given up once it fails its last attempt
+		if transition == types.TransitionFail && task.Attempts >= taskMaxAttempts(task) {
+			transition = types.TransitionAbandon
 		}
+		transitions[i] = transition
 	}
 
 	var queryBuilder strings.Builder
@@ -138,10 +366,10 @@ func UpdateTask(db *sql.DB, query *UpdateTaskQuery) error {
 
 	queryBuilder.WriteString("UPDATE address_tasks SET ")
 
-	if query.TaskStatus != nil {
+	if to != "" {
 		queryBuilder.WriteString("task_status = $")
 		queryBuilder.WriteString(fmt.Sprintf("%d, ", len(args)+1))
-		args = append(args, query.TaskStatus)
+		args = append(args, string(to))
 	}
 	if query.Memo != nil {
 		queryBuilder.WriteString("memo = $")
@@ -163,71 +391,120 @@ func UpdateTask(db *sql.DB, query *UpdateTaskQuery) error {
 		queryBuilder.WriteString(fmt.Sprintf("%d, ", len(args)+1))
 		args = append(args, query.JobBatchId)
 	}
-	queryBuilder.WriteString(" gmt_modify = CURRENT_TIMESTAMP ")
+	switch to {
+	case types.TaskStatusFail:
+		failCode := ""
+		if query.FailCode != nil {
+			failCode = *query.FailCode
+		}
+		queryBuilder.WriteString(fmt.Sprintf("fail_code = $%d, fail_reason = $%d, ", len(args)+1, len(args)+2))
+		args = append(args, failCode, reason)
+	case types.TaskStatusSuccess:
+		queryBuilder.WriteString("fail_code = '', fail_reason = '', ")
+	}
+	queryBuilder.WriteString(" gmt_modify = CURRENT_TIMESTAMP WHERE id IN (")
+	for i, task := range tasks {
+		if i > 0 {
+			queryBuilder.WriteString(",")
+		}
+		queryBuilder.WriteString(fmt.Sprintf("$%d", len(args)+1))
+		args = append(args, task.ID)
+	}
+	queryBuilder.WriteString(")")
 
-	queryBuilder.WriteString(" WHERE 1=1 ")
-	if query.ID > 0 {
-		queryBuilder.WriteString(" and id = $")
-		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
-		args = append(args, query.ID)
+	// execute update statement
+	if _, err := tx.Exec(queryBuilder.String(), args...); err != nil {
+		return err
 	}
-	if query.AccountAddress != nil {
-		queryBuilder.WriteString(" and account_address = $")
-		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
-		args = append(args, query.AccountAddress)
+
+	if err := abandonTasks(tx, tasks, transitions); err != nil {
+		return err
 	}
-	if query.TaskName != nil {
-		queryBuilder.WriteString(" and task_name = $")
-		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
-		args = append(args, query.TaskName)
+
+	var succeeded []AddressTask
+	for i, task := range tasks {
+		if transitions[i] == "" {
+			continue
+		}
+		if query.Txs != nil {
+			task.Txs = query.Txs
+		}
+		if err := recordTransition(tx, &task, transitions[i], actor, reason, types.TaskStatus(*task.TaskStatus), transitions[i].To()); err != nil {
+			return err
+		}
+		if to == types.TaskStatusSuccess {
+			succeeded = append(succeeded, task)
+		}
 	}
-	if query.StatusEqual != nil {
-		queryBuilder.WriteString(" and task_status = $")
-		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
-		args = append(args, query.StatusEqual)
+
+	if err := awardSucceededTasks(tx, succeeded, actor); err != nil {
+		return err
 	}
-	// remove commas and spaces at the end
-	querySql := strings.TrimSuffix(queryBuilder.String(), ", ")
-	tx, txErr := db.Begin()
-	if txErr != nil {
-		return txErr
+	// the campaigns completed by the update are synchronized to the partners by the outbox worker
+	if err := completeCampaigns(tx, succeeded, actor); err != nil {
+		return err
 	}
-	// Defer a rollback in case anything fails.
-	defer tx.Rollback()
-	// execute update statement
-	_, err := tx.Exec(querySql, args...)
+
 	// Commit the transaction.
-	if txErr = tx.Commit(); txErr != nil {
-		return txErr
-	}
+	return tx.Commit()
+}
 
-	// if update 3 it needs to be synchronized to goplus
-	if err == nil {
-		task, taskErr := GetTask(db, "", "", query.ID)
-		if taskErr != nil {
-			return taskErr
+// completeCampaigns runs completeCampaign for the campaigns the succeeded tasks may have completed
+func completeCampaigns(exec common.Executor, tasks []AddressTask, actor string) error {
+	completed := make(map[string]bool)
+	for _, task := range tasks {
+		if task.Campaign == nil || task.AccountAddress == nil || task.TaskName == nil {
+			continue
+		}
+		campaign, err := GetCatalog().Campaign(*task.Campaign)
+		if err != nil || *task.Campaign == "" {
+			continue
+		}
+		if catalogTask, ok := campaign.Task(*task.TaskName); !ok || catalogTask.Handler == HandlerSync {
+			continue
 		}
-		if !strings.EqualFold(strings.Trim(*task.TaskStatus, " "), "3") {
-			return nil
+		key := campaign.Name + "|" + strings.ToLower(*task.AccountAddress)
+		if completed[key] {
+			continue
 		}
-		// sync status to goplus
-		syncErr := SyncStatus(db, &InitTaskQuery{
-			AccountAddress: *task.AccountAddress,
-			TaskId:         *task.TaskId,
-			TaskTopic:      *task.TaskTopic,
-		})
-		if syncErr != nil {
-			log.Info("goplus|error|", syncErr.Error(), *task.AccountAddress)
-			return nil
+		completed[key] = true
+		if err := completeCampaign(exec, campaign, *task.AccountAddress, actor); err != nil {
+			return err
 		}
 	}
-	return err
+	return nil
+}
+
+// completeCampaign queues the sync of the address to the partners and qualifies its referral, once the
+// address has succeeded the required tasks of the campaign. Both only happen once per address.
+func completeCampaign(exec common.Executor, campaign *Campaign, address, actor string) error {
+	if err := enqueueSync(exec, campaign, address); err != nil {
+		return err
+	}
+	return qualifyReferral(exec, campaign, address, actor)
+}
+
+// uncompleteCampaign takes back what completeCampaign did once the address no longer succeeds the required
+// tasks of the campaign: the syncs not sent yet are dropped and the referral is disqualified. The syncs
+// already sent to the partners cannot be taken back.
+func uncompleteCampaign(exec common.Executor, campaign *Campaign, address string) error {
+	if err := dequeueSync(exec, campaign, address); err != nil {
+		return err
+	}
+	return disqualifyReferral(exec, campaign, address)
 }
+
 func GetAccountTaskInfo(db *sql.DB, query *TaskQuery) (AccountTaskInfo, error) {
 	if db == nil || query == nil {
 		return AccountTaskInfo{}, fmt.Errorf("address cannot be empty")
 	}
 
+	campaign, err := GetCatalog().Campaign(query.Campaign)
+	if err != nil {
+		return AccountTaskInfo{}, err
+	}
+	query.Campaign = campaign.Name
+
 	taskInfos, err := GetTasks(db, query)
 	if err != nil {
 		return AccountTaskInfo{}, err
@@ -235,51 +512,36 @@ func GetAccountTaskInfo(db *sql.DB, query *TaskQuery) (AccountTaskInfo, error) {
 	if len(taskInfos) == 0 {
 		return AccountTaskInfo{
 			AccountAddress: query.AccountAddress,
+			Campaign:       campaign.Name,
 			Status:         0,
 		}, nil
 	}
 	return AccountTaskInfo{
 		AccountAddress: query.AccountAddress,
-		Status:         calculateStatus(taskInfos),
+		Campaign:       campaign.Name,
+		Status:         calculateStatus(campaign, taskInfos),
 
 		TaskInfos: ConvertTaskInfo(taskInfos),
 	}, nil
 }
 
 func taskDescription(taskName string) TaskInfo {
-
-	pull := TaskInfo{
-		TaskName:   types.Task_Name_RugPull,
-		TaskStatus: 0,
-		Title:      "Rug Pull",
-	}
-	aspect := TaskInfo{
-		TaskName:   types.Task_Name_AspectPull,
-		TaskStatus: 0,
-		Title:      "Aspect Work",
+	task, ok := GetCatalog().task(taskName)
+	if !ok {
+		return TaskInfo{TaskName: taskName}
 	}
-	addLiquidity := TaskInfo{
-		TaskName:   types.Task_Name_AddLiquidity,
-		TaskStatus: 0,
-		Title:      "Add Liquidity",
-	}
-	getFaucet := TaskInfo{
-		TaskName:   types.Task_Name_GetFaucet,
-		TaskStatus: 0,
-		Title:      "Get Faucet",
-	}
-	// 把上面3个taskInfo加入到一个map中，key是TaskName
-
-	taskMap := map[string]TaskInfo{
-		pull.TaskName:         pull,
-		aspect.TaskName:       aspect,
-		addLiquidity.TaskName: addLiquidity,
-		getFaucet.TaskName:    getFaucet,
+	return TaskInfo{
+		TaskName:    task.Name,
+		Title:       task.Title,
+		Description: task.Description,
 	}
-	return taskMap[taskName]
-
 }
 func ConvertTaskInfo(tasks []AddressTask) []TaskInfo {
+	statuses := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		statuses[taskKey(task)] = *task.TaskStatus
+	}
+
 	var taskInfos []TaskInfo
 	for _, task := range tasks {
 		// 将字符串转换为int64类型
@@ -293,9 +555,10 @@ func ConvertTaskInfo(tasks []AddressTask) []TaskInfo {
 		description := taskDescription(*task.TaskName)
 
 		taskItem := TaskInfo{
-			ID:         task.ID,
-			TaskStatus: intValue,
-			Title:      description.Title,
+			ID:          task.ID,
+			TaskStatus:  intValue,
+			Title:       description.Title,
+			Description: description.Description,
 		}
 		if task.TaskTopic != nil {
 			taskItem.TaskTopic = *task.TaskTopic
@@ -312,6 +575,12 @@ func ConvertTaskInfo(tasks []AddressTask) []TaskInfo {
 		if task.Txs != nil {
 			taskItem.Txs = *task.Txs
 		}
+		taskItem.LockedReason = lockedReason(task, statuses)
+		taskItem.Locked = taskItem.LockedReason != ""
+		taskItem.Attempts = task.Attempts
+		taskItem.MaxAttempts = taskMaxAttempts(task)
+		taskItem.FailCode = task.FailCode
+		taskItem.FailReason = failReason(task, taskItem.MaxAttempts)
 		taskInfos = append(taskInfos, taskItem)
 
 	}
@@ -322,34 +591,72 @@ func ConvertTaskInfo(tasks []AddressTask) []TaskInfo {
 
 }
 
-func calculateStatus(tasks []AddressTask) int8 {
+func taskKey(task AddressTask) string {
+	campaign := ""
+	if task.Campaign != nil {
+		campaign = *task.Campaign
+	}
+	return campaign + "/" + *task.TaskName
+}
+
+// lockedReason tells which prerequisites the task is waiting for, empty if it is not locked
+func lockedReason(task AddressTask, statuses map[string]string) string {
+	if task.Campaign == nil || *task.TaskStatus == string(types.TaskStatusSuccess) {
+		return ""
+	}
+	campaign, err := GetCatalog().Campaign(*task.Campaign)
+	if err != nil {
+		return ""
+	}
+	catalogTask, ok := campaign.Task(*task.TaskName)
+	if !ok {
+		return ""
+	}
+
+	var waiting []string
+	for _, prerequisite := range campaign.Prerequisites(catalogTask) {
+		if statuses[campaign.Name+"/"+prerequisite.Name] != string(types.TaskStatusSuccess) {
+			waiting = append(waiting, prerequisite.Title)
+		}
+	}
+	if len(waiting) == 0 {
+		return ""
+	}
+	return "waiting for " + strings.Join(waiting, ", ")
+}
+
+func calculateStatus(campaign *Campaign, tasks []AddressTask) int8 {
 	status := 0
 	if len(tasks) == 0 {
 		return int8(status)
 	}
 	count := 0
 	for _, task := range tasks {
+		if catalogTask, ok := campaign.Task(*task.TaskName); !ok || !catalogTask.Required {
+			continue
+		}
 		if strings.EqualFold(*task.TaskStatus, string(types.TaskStatusSuccess)) {
 			count += 1
 		}
 	}
-	if count == 4 {
+	if count == len(campaign.RequiredTasks()) {
 		// 2:completed
 		status = 3
-	} else if count > 0 && count < 3 {
+	} else if count > 0 {
 		// 1:part finish
 		status = 2
 	}
 	return int8(status)
 }
 
+const selectTask = "SELECT id,gmt_create,gmt_modify,account_address,task_name,task_status,memo,txs,task_id,task_topic,job_batch_id,campaign," +
+	"attempts,fail_code,fail_reason FROM address_tasks "
+
 func GetTasks(db *sql.DB, query *TaskQuery) ([]AddressTask, error) {
-	// 遍历结果集
-	var addressTasks []AddressTask
 	var queryBuilder strings.Builder
 	var args []interface{}
 
-	queryBuilder.WriteString("SELECT id,gmt_create,gmt_modify,account_address,task_name,task_status,memo,txs,task_id,task_topic,job_batch_id FROM address_tasks ")
+	queryBuilder.WriteString(selectTask)
 
 	queryBuilder.WriteString(" WHERE 1=1 ")
 	if query.ID > 0 {
@@ -358,9 +665,13 @@ func GetTasks(db *sql.DB, query *TaskQuery) ([]AddressTask, error) {
 		args = append(args, query.ID)
 	}
 	if query.AccountAddress != "" {
-		queryBuilder.WriteString(" and account_address = $")
-		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
-		args = append(args, query.AccountAddress)
+		address, err := common.CanonicalAddress(query.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+		queryBuilder.WriteString(" and LOWER(account_address) = LOWER($")
+		queryBuilder.WriteString(fmt.Sprintf("%d) ", len(args)+1))
+		args = append(args, address)
 	}
 
 	if query.TaskId != "" {
@@ -388,6 +699,11 @@ func GetTasks(db *sql.DB, query *TaskQuery) ([]AddressTask, error) {
 		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
 		args = append(args, query.JobBatchId)
 	}
+	if query.Campaign != "" {
+		queryBuilder.WriteString(" and campaign = $")
+		queryBuilder.WriteString(fmt.Sprintf("%d ", len(args)+1))
+		args = append(args, query.Campaign)
+	}
 	// 去除末尾的逗号和空格
 	querySql := strings.TrimSuffix(queryBuilder.String(), ", ")
 
@@ -401,6 +717,10 @@ func GetTasks(db *sql.DB, query *TaskQuery) ([]AddressTask, error) {
 		log.Errorf("Failed to getTasks: %v", err)
 		return nil, err
 	}
+	return scanTasks(rows)
+}
+
+func scanTasks(rows *sql.Rows) ([]AddressTask, error) {
 	// 解析 query 到 struct 类型中
 	defer func(rows *sql.Rows) {
 		closeErr := rows.Close()
@@ -409,6 +729,8 @@ func GetTasks(db *sql.DB, query *TaskQuery) ([]AddressTask, error) {
 		}
 	}(rows)
 
+	// 遍历结果集
+	var addressTasks []AddressTask
 	for rows.Next() {
 		var addressTask AddressTask
 		err := rows.Scan(
@@ -423,13 +745,17 @@ func GetTasks(db *sql.DB, query *TaskQuery) ([]AddressTask, error) {
 			&addressTask.TaskId,
 			&addressTask.TaskTopic,
 			&addressTask.JobBatchId,
+			&addressTask.Campaign,
+			&addressTask.Attempts,
+			&addressTask.FailCode,
+			&addressTask.FailReason,
 		)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		addressTasks = append(addressTasks, addressTask)
 	}
-	return addressTasks, nil
+	return addressTasks, rows.Err()
 }
 
 func GetTask(db *sql.DB, addr string, taskName string, id int64) (AddressTask, error) {
@@ -444,3 +770,71 @@ func GetTask(db *sql.DB, addr string, taskName string, id int64) (AddressTask, e
 	}
 	return tasks[0], nil
 }
+
+// CompleteTaskByName marks the named task of an address as succeeded, it is used by the indexers
+// which verify tasks from on-chain events instead of sending transactions. Tasks of ended campaigns
+// are left untouched. The handler of the task in the catalog is recorded as the actor, and the points
+// of the task are credited to the address. The completion is recorded too, so it is applied to the
+// tasks of the address if it registers later.
+func CompleteTaskByName(db common.Executor, addr string, taskName string, txs string) (int64, error) {
+	if _, err := db.Exec("INSERT INTO task_completions (account_address, task_name, txs) VALUES ($1, $2, $3) "+
+		"ON CONFLICT DO NOTHING", addr, taskName, txs); err != nil {
+		return 0, err
+	}
+	actor := ActorIndexer
+	if handlers := GetCatalog().taskHandlers(taskName); len(handlers) == 1 {
+		actor = handlers[0]
+	}
+	moved, err := moveTasks(db, types.TransitionVerify, actor, "", "txs = $1",
+		"LOWER(account_address) = LOWER($2) AND task_name = $3 AND "+campaignOpenCondition, txs, addr, taskName)
+	if err != nil || moved == 0 {
+		return moved, err
+	}
+	for _, campaign := range GetCatalog().Campaigns() {
+		if _, ok := campaign.Task(taskName); ok {
+			if err := awardTaskPoints(db, campaign, taskName, actor, "LOWER(account_address) = LOWER($6)", addr); err != nil {
+				return moved, err
+			}
+			if err := completeCampaign(db, campaign, addr, actor); err != nil {
+				return moved, err
+			}
+		}
+	}
+	return moved, nil
+}
+
+// RevertTaskByName takes back the named task of an address verified by the given txs, it is used by
+// the indexers when a reorg drops the events which completed the task. The points of the task are taken
+// back, and so is what completing the campaigns of the task caused. The address has to complete the
+// task again.
+func RevertTaskByName(db common.Executor, addr string, taskName string, txs string, reason string) (int64, error) {
+	if _, err := db.Exec("DELETE FROM task_completions WHERE LOWER(account_address) = LOWER($1) AND task_name = $2 AND txs = $3",
+		addr, taskName, txs); err != nil {
+		return 0, err
+	}
+	moved, err := moveTasks(db, types.TransitionRevert, ActorIndexer, reason, "",
+		"LOWER(account_address) = LOWER($1) AND task_name = $2 AND txs = $3", addr, taskName, txs)
+	if err != nil || moved == 0 {
+		return moved, err
+	}
+	if err := voidTaskPoints(db, "LOWER(account_address) = LOWER($3) AND task_name = $4 AND txs = $5", addr, taskName, txs); err != nil {
+		return moved, err
+	}
+	for _, campaign := range GetCatalog().Campaigns() {
+		if _, ok := campaign.Task(taskName); ok {
+			if err := uncompleteCampaign(db, campaign, addr); err != nil {
+				return moved, err
+			}
+		}
+	}
+	return moved, nil
+}
+
+// TaskCompleted tells whether the address has succeeded the task of the campaign
+func TaskCompleted(db *sql.DB, campaign string, taskName string, address string) (bool, error) {
+	var completed bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM address_tasks WHERE campaign = $1 AND task_name = $2 "+
+		"AND LOWER(account_address) = LOWER($3) AND task_status = $4)",
+		campaign, taskName, address, string(types.TaskStatusSuccess)).Scan(&completed)
+	return completed, err
+}
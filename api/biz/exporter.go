@@ -0,0 +1,243 @@
+package biz
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/types"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	// ExportFormatGalxe is the list of addresses uploaded to a galxe credential, one lower case address per line
+	ExportFormatGalxe = "galxe"
+)
+
+// ExportQuery selects the tasks of an export, all the tasks of the campaign if TaskName is empty.
+// The time window applies to the time the tasks last reached the status, their completion time for
+// the succeeded tasks, From included and To excluded.
+type ExportQuery struct {
+	Campaign string
+	TaskName string
+	Status   types.TaskStatus
+	From     *time.Time
+	To       *time.Time
+}
+
+// ExportRow is a task of an export, StatusTime is the time it last reached its status as recorded in
+// its history, the completion time of a succeeded task
+type ExportRow struct {
+	Address    string    `json:"address"`
+	Campaign   string    `json:"campaign"`
+	TaskName   string    `json:"task"`
+	Status     string    `json:"status"`
+	Txs        string    `json:"txs"`
+	StatusTime time.Time `json:"statusTime"`
+}
+
+// ExportSummary tells the rows written and the sha256 of the written bytes, a snapshot exported again
+// has the same checksum as long as its tasks did not change
+type ExportSummary struct {
+	Rows     int64  `json:"rows"`
+	Checksum string `json:"checksum"`
+}
+
+type exportEncoder interface {
+	encode(row *ExportRow) (bool, error)
+	flush() error
+}
+
+// csvEncoder writes a header and a line per task
+type csvEncoder struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) encode(row *ExportRow) (bool, error) {
+	if !e.header {
+		e.header = true
+		if err := e.writer.Write([]string{"address", "campaign", "task", "status", "txs", "status_time"}); err != nil {
+			return false, err
+		}
+	}
+	return true, e.writer.Write([]string{row.Address, row.Campaign, row.TaskName, row.Status, row.Txs,
+		row.StatusTime.UTC().Format(time.RFC3339)})
+}
+
+func (e *csvEncoder) flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// ndjsonEncoder writes a json object per task
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) encode(row *ExportRow) (bool, error) {
+	utc := *row
+	utc.StatusTime = row.StatusTime.UTC()
+	return true, e.encoder.Encode(&utc)
+}
+
+func (e *ndjsonEncoder) flush() error {
+	return nil
+}
+
+// galxeEncoder writes each address once, the rows come ordered by address
+type galxeEncoder struct {
+	w    io.Writer
+	last string
+}
+
+func (e *galxeEncoder) encode(row *ExportRow) (bool, error) {
+	address := strings.ToLower(row.Address)
+	if address == e.last {
+		return false, nil
+	}
+	e.last = address
+	_, err := io.WriteString(e.w, address+"\n")
+	return true, err
+}
+
+func (e *galxeEncoder) flush() error {
+	return nil
+}
+
+func newExportEncoder(format string, w io.Writer) (exportEncoder, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvEncoder{writer: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonEncoder{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatGalxe:
+		return &galxeEncoder{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// ExportWriter encodes the rows of an export in a format and sums the written bytes
+type ExportWriter struct {
+	format  string
+	encoder exportEncoder
+	hash    hash.Hash
+	rows    int64
+}
+
+func NewExportWriter(format string, w io.Writer) (*ExportWriter, error) {
+	sum := sha256.New()
+	encoder, err := newExportEncoder(format, io.MultiWriter(w, sum))
+	if err != nil {
+		return nil, err
+	}
+	return &ExportWriter{format: format, encoder: encoder, hash: sum}, nil
+}
+
+func (w *ExportWriter) Write(row *ExportRow) error {
+	written, err := w.encoder.encode(row)
+	if written {
+		w.rows++
+	}
+	return err
+}
+
+// Close flushes the export and returns its summary
+func (w *ExportWriter) Close() (*ExportSummary, error) {
+	if err := w.encoder.flush(); err != nil {
+		return nil, err
+	}
+	return &ExportSummary{Rows: w.rows, Checksum: hex.EncodeToString(w.hash.Sum(nil))}, nil
+}
+
+// ExportTasks streams the tasks selected by the query to the writer, ordered by address, task and id so an
+// export is reproducible. A galxe list of the whole campaign only has the addresses which completed it.
+func ExportTasks(db *sql.DB, query *ExportQuery, w *ExportWriter) error {
+	campaign, err := GetCatalog().Campaign(query.Campaign)
+	if err != nil {
+		return err
+	}
+	if query.TaskName != "" {
+		if _, ok := campaign.Task(query.TaskName); !ok {
+			return fmt.Errorf("campaign %s has no task %s", campaign.Name, query.TaskName)
+		}
+	}
+	status := query.Status
+	if status == "" {
+		status = types.TaskStatusSuccess
+	}
+
+	var queryBuilder strings.Builder
+	args := []interface{}{campaign.Name, string(status)}
+	statusTime := reachedAt("address_tasks", status)
+	queryBuilder.WriteString("SELECT account_address, campaign, task_name, task_status, COALESCE(txs, ''), " + statusTime + " FROM address_tasks ")
+	queryBuilder.WriteString("WHERE campaign = $1 AND task_status = $2 ")
+	if query.TaskName != "" {
+		args = append(args, query.TaskName)
+		queryBuilder.WriteString(fmt.Sprintf("AND task_name = $%d ", len(args)))
+	} else if w.format == ExportFormatGalxe {
+		var completed string
+		completed, args = requiredCompletedBy(campaign, "address_tasks.account_address", args)
+		queryBuilder.WriteString("AND " + completed + " ")
+	}
+	if query.From != nil {
+		args = append(args, *query.From)
+		queryBuilder.WriteString(fmt.Sprintf("AND %s >= $%d ", statusTime, len(args)))
+	}
+	if query.To != nil {
+		args = append(args, *query.To)
+		queryBuilder.WriteString(fmt.Sprintf("AND %s < $%d ", statusTime, len(args)))
+	}
+	queryBuilder.WriteString("ORDER BY LOWER(account_address), task_name, id")
+
+	rows, err := db.Query(queryBuilder.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &ExportRow{}
+		if err := rows.Scan(&row.Address, &row.Campaign, &row.TaskName, &row.Status, &row.Txs, &row.StatusTime); err != nil {
+			return err
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ParseExportQuery reads the status and the RFC3339 time window of an export
+func ParseExportQuery(campaign, taskName, status, from, to string) (*ExportQuery, error) {
+	query := &ExportQuery{Campaign: campaign, TaskName: taskName}
+	if status != "" {
+		parsed, err := types.ParseTaskStatus(status)
+		if err != nil {
+			return nil, err
+		}
+		query.Status = parsed
+	}
+	for _, bound := range []struct {
+		raw    string
+		target **time.Time
+	}{{from, &query.From}, {to, &query.To}} {
+		if bound.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", bound.raw, err)
+		}
+		*bound.target = &t
+	}
+	return query, nil
+}
@@ -0,0 +1,53 @@
+package biz
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportWriter(t *testing.T) {
+	statusTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	rows := []*ExportRow{
+		{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Campaign: "goplus", TaskName: "GetFaucet", Status: "3", StatusTime: statusTime},
+		{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Campaign: "goplus", TaskName: "RugPull", Status: "3", Txs: "0x01", StatusTime: statusTime},
+		{Address: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", Campaign: "goplus", TaskName: "GetFaucet", Status: "3", StatusTime: statusTime},
+	}
+	export := func(format string) (string, *ExportSummary) {
+		buf := &bytes.Buffer{}
+		w, err := NewExportWriter(format, buf)
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, w.Write(row))
+		}
+		summary, err := w.Close()
+		require.NoError(t, err)
+		return buf.String(), summary
+	}
+
+	out, summary := export(ExportFormatCSV)
+	require.Equal(t, "address,campaign,task,status,txs,status_time\n"+
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed,goplus,GetFaucet,3,,2024-03-01T04:00:00Z\n"+
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed,goplus,RugPull,3,0x01,2024-03-01T04:00:00Z\n"+
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359,goplus,GetFaucet,3,,2024-03-01T04:00:00Z\n", out)
+	require.EqualValues(t, 3, summary.Rows)
+
+	// the same rows give the same checksum
+	_, again := export(ExportFormatCSV)
+	require.Equal(t, summary.Checksum, again.Checksum)
+	require.Len(t, summary.Checksum, 64)
+
+	out, summary = export(ExportFormatNDJSON)
+	require.Contains(t, out, `{"address":"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359","campaign":"goplus","task":"GetFaucet","status":"3","txs":"","statusTime":"2024-03-01T04:00:00Z"}`)
+	require.EqualValues(t, 3, summary.Rows)
+
+	// galxe lists each address once
+	out, summary = export(ExportFormatGalxe)
+	require.Equal(t, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed\n0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359\n", out)
+	require.EqualValues(t, 2, summary.Rows)
+
+	_, err := NewExportWriter("xml", &bytes.Buffer{})
+	require.Error(t, err)
+}
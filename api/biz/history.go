@@ -0,0 +1,159 @@
+package biz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// actors moving tasks besides the handlers of the catalog
+const (
+	ActorAPI     = "api"
+	ActorCleaner = "cleaner"
+	ActorIndexer = "indexer"
+	ActorAdmin   = "admin"
+)
+
+// TaskHistory is a transition of a task, recorded for support investigations
+type TaskHistory struct {
+	ID             int64     `json:"id"`
+	AddressTaskID  int64     `json:"addressTaskId"`
+	AccountAddress string    `json:"accountAddress"`
+	TaskName       string    `json:"taskName"`
+	Campaign       string    `json:"campaign"`
+	Transition     string    `json:"transition"`
+	Actor          string    `json:"actor"`
+	FromStatus     string    `json:"fromStatus"`
+	ToStatus       string    `json:"toStatus"`
+	Reason         string    `json:"reason"`
+	Txs            string    `json:"txs"`
+	GMTCreate      time.Time `json:"gmtCreate"`
+}
+
+type TaskHistoryQuery struct {
+	AddressTaskID  int64
+	AccountAddress string
+	Campaign       string
+	LimitNum       int
+}
+
+// historyInsert inserts the transitions of the rows a statement moved, the statement is given as a cte
+// named moved returning id, from_status, account_address, task_name, campaign and txs.
+const historyInsert = "INSERT INTO task_status_history " +
+	"(address_task_id, account_address, task_name, campaign, transition, actor, from_status, to_status, reason, txs) " +
+	"SELECT id, account_address, task_name, campaign, $%d, $%d, from_status, $%d, $%d, COALESCE(txs, '') FROM moved"
+
+// completedAt is the time a task of address_tasks last succeeded, given the alias of address_tasks in
+// the query. It is read from the history, so it does not move when the task is updated later, and a task
+// reverted then completed again counts from its new completion. Tasks which succeeded before the history
+// was recorded fall back to their last modification.
+func completedAt(alias string) string {
+	return reachedAt(alias, types.TaskStatusSuccess)
+}
+
+// reachedAt is completedAt for any status, the time the task last moved to the status. It is meant for
+// tasks currently in the status.
+func reachedAt(alias string, status types.TaskStatus) string {
+	return "COALESCE((SELECT MAX(history.gmt_create) FROM task_status_history history " +
+		"WHERE history.address_task_id = " + alias + ".id AND history.to_status = '" + string(status) + "'), " +
+		alias + ".gmt_modify)"
+}
+
+// moveTasks runs the update of the tasks selected by where, along the transition, and records the
+// transition of each moved task in the same statement. The placeholders of set and where start at $1,
+// after which come the ones of the transition, where may end with a LIMIT. It returns the number of
+// moved tasks.
+func moveTasks(exec common.Executor, transition types.TaskTransition, actor, reason, set, where string, args ...interface{}) (int64, error) {
+	from := transition.From()
+	fromPlaceholders := make([]string, len(from))
+	for i, status := range from {
+		args = append(args, string(status))
+		fromPlaceholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	n := len(args)
+	args = append(args, string(transition), actor, string(transition.To()), reason)
+
+	sets := fmt.Sprintf("task_status = $%d, gmt_modify = CURRENT_TIMESTAMP ", n+3)
+	if set != "" {
+		sets = set + ", " + sets
+	}
+	querySql := "WITH locked AS (SELECT id, task_status FROM address_tasks WHERE task_status IN (" +
+		strings.Join(fromPlaceholders, ",") + ") AND " + where + " FOR UPDATE), " +
+		"moved AS (UPDATE address_tasks SET " + sets +
+		"FROM locked WHERE address_tasks.id = locked.id " +
+		"RETURNING address_tasks.id, locked.task_status AS from_status, address_tasks.account_address, " +
+		"address_tasks.task_name, address_tasks.campaign, address_tasks.txs) " +
+		fmt.Sprintf(historyInsert, n+1, n+2, n+3, n+4)
+
+	res, err := exec.Exec(querySql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// recordTransition records a transition of a task
+func recordTransition(exec common.Executor, task *AddressTask, transition types.TaskTransition, actor, reason string, from, to types.TaskStatus) error {
+	txs := ""
+	if task.Txs != nil {
+		txs = *task.Txs
+	}
+	campaign := ""
+	if task.Campaign != nil {
+		campaign = *task.Campaign
+	}
+	_, err := exec.Exec("INSERT INTO task_status_history "+
+		"(address_task_id, account_address, task_name, campaign, transition, actor, from_status, to_status, reason, txs) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		task.ID, *task.AccountAddress, *task.TaskName, campaign, string(transition), actor, string(from), string(to), reason, txs)
+	return err
+}
+
+// GetTaskHistory returns the transitions of the tasks, the latest first
+func GetTaskHistory(db *sql.DB, query *TaskHistoryQuery) ([]TaskHistory, error) {
+	var conditions []string
+	var args []interface{}
+	if query.AddressTaskID > 0 {
+		args = append(args, query.AddressTaskID)
+		conditions = append(conditions, fmt.Sprintf("address_task_id = $%d", len(args)))
+	}
+	if query.AccountAddress != "" {
+		args = append(args, query.AccountAddress)
+		conditions = append(conditions, fmt.Sprintf("LOWER(account_address) = LOWER($%d)", len(args)))
+	}
+	if query.Campaign != "" {
+		args = append(args, query.Campaign)
+		conditions = append(conditions, fmt.Sprintf("campaign = $%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("task id or address cannot be empty")
+	}
+
+	querySql := "SELECT id, address_task_id, account_address, task_name, campaign, transition, actor, from_status, " +
+		"to_status, reason, txs, gmt_create FROM task_status_history WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY id DESC"
+	if query.LimitNum > 0 {
+		querySql += fmt.Sprintf(" LIMIT %d", query.LimitNum)
+	}
+
+	rows, err := db.Query(querySql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []TaskHistory
+	for rows.Next() {
+		var h TaskHistory
+		if err := rows.Scan(&h.ID, &h.AddressTaskID, &h.AccountAddress, &h.TaskName, &h.Campaign, &h.Transition,
+			&h.Actor, &h.FromStatus, &h.ToStatus, &h.Reason, &h.Txs, &h.GMTCreate); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
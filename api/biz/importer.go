@@ -0,0 +1,145 @@
+package biz
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+
+	// ActorImport registers the imported addresses
+	ActorImport = "import"
+
+	// importBatchSize is the number of addresses inserted per statement
+	importBatchSize = 500
+)
+
+// ImportRow is a row of an import, TaskId is optional and defaults to the task id of the import
+type ImportRow struct {
+	Line    int    `json:"line"`
+	Address string `json:"address"`
+	TaskId  string `json:"taskId"`
+}
+
+// ImportReport tells what an import did with each row, Skipped counts the rows duplicated in the import
+// or already registered
+type ImportReport struct {
+	Campaign string       `json:"campaign"`
+	Rows     int          `json:"rows"`
+	Inserted int64        `json:"inserted"`
+	Skipped  int64        `json:"skipped"`
+	Invalid  []*ImportRow `json:"invalid"`
+}
+
+// ParseImport reads the rows of a csv or json import. A csv import has the address in its first column and
+// an optional task id in its second one, a header row is allowed. A json import is an array of addresses
+// or of objects with an address and an optional taskId.
+func ParseImport(r io.Reader, format string) ([]*ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		var rows []*ImportRow
+		for first := true; ; first = false {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if first && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+				continue
+			}
+			line, _ := reader.FieldPos(0)
+			row := &ImportRow{Line: line, Address: strings.TrimSpace(record[0])}
+			if len(record) > 1 {
+				row.TaskId = strings.TrimSpace(record[1])
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	case ImportFormatJSON:
+		var raw []json.RawMessage
+		if err := json.NewDecoder(r).Decode(&raw); err != nil {
+			return nil, err
+		}
+		rows := make([]*ImportRow, 0, len(raw))
+		for i, item := range raw {
+			row := &ImportRow{Line: i + 1}
+			if bytes.HasPrefix(bytes.TrimSpace(item), []byte("{")) {
+				if err := json.Unmarshal(item, row); err != nil {
+					return nil, fmt.Errorf("invalid item %d: %w", i+1, err)
+				}
+				row.Line = i + 1
+			} else if err := json.Unmarshal(item, &row.Address); err != nil {
+				return nil, fmt.Errorf("invalid item %d: %w", i+1, err)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+// ImportAddresses registers the addresses of the rows to the campaign in one transaction. Invalid addresses
+// are reported and the others are deduplicated, so an import can be replayed. The window and quota of the
+// campaign do not apply, imports are run by the admins.
+func ImportAddresses(db *sql.DB, campaignName string, taskId string, rows []*ImportRow) (*ImportReport, error) {
+	campaign, err := GetCatalog().Campaign(campaignName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Campaign: campaign.Name, Rows: len(rows), Invalid: make([]*ImportRow, 0)}
+	registrations := make([]*Registration, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		address, err := common.CanonicalAddress(row.Address)
+		if err != nil {
+			report.Invalid = append(report.Invalid, row)
+			continue
+		}
+		if seen[address] {
+			report.Skipped++
+			continue
+		}
+		seen[address] = true
+
+		registration := &Registration{Address: address, TaskId: row.TaskId}
+		if registration.TaskId == "" {
+			registration.TaskId = taskId
+		}
+		registrations = append(registrations, registration)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(registrations); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(registrations) {
+			end = len(registrations)
+		}
+		_, inserted, err := registerTasks(tx, campaign, ActorImport, registrations[start:end])
+		if err != nil {
+			return nil, err
+		}
+		report.Inserted += inserted
+		report.Skipped += int64(end-start) - inserted
+	}
+	return report, tx.Commit()
+}
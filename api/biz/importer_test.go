@@ -0,0 +1,29 @@
+package biz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImport(t *testing.T) {
+	rows, err := ParseImport(strings.NewReader("address,task_id\n"+
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed, 7\n"+
+		"\n"+
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed\n"), ImportFormatCSV)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, &ImportRow{Line: 2, Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", TaskId: "7"}, rows[0])
+	require.Equal(t, 4, rows[1].Line)
+	require.Empty(t, rows[1].TaskId)
+
+	rows, err = ParseImport(strings.NewReader(`["0x01", {"address": "0x02", "taskId": "9"}]`), ImportFormatJSON)
+	require.NoError(t, err)
+	require.Equal(t, []*ImportRow{{Line: 1, Address: "0x01"}, {Line: 2, Address: "0x02", TaskId: "9"}}, rows)
+
+	_, err = ParseImport(strings.NewReader(`{"address": "0x01"}`), ImportFormatJSON)
+	require.Error(t, err)
+	_, err = ParseImport(strings.NewReader(""), "xml")
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package biz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusDead    OutboxStatus = "dead"
+)
+
+// OutboxItem is a pending sync of an address which completed a campaign to the partner
+type OutboxItem struct {
+	ID             int64        `json:"id"`
+	Partner        string       `json:"partner"`
+	Campaign       string       `json:"campaign"`
+	AccountAddress string       `json:"accountAddress"`
+	TaskId         string       `json:"taskId"`
+	Status         OutboxStatus `json:"status"`
+	Attempts       int          `json:"attempts"`
+	NextAttemptAt  time.Time    `json:"nextAttemptAt"`
+	LastError      string       `json:"lastError"`
+	GMTCreate      time.Time    `json:"gmtCreate"`
+	GMTModify      time.Time    `json:"gmtModify"`
+}
+
+const outboxColumns = "id, partner, campaign, account_address, task_id, status, attempts, next_attempt_at, last_error, gmt_create, gmt_modify"
+
+// enqueueSync queues the sync of the address to each partner of the campaign once it has completed the
+// required tasks of the campaign. It runs in the transaction completing the task, so the completion is
+// never lost, and queues an address only once per partner.
+func enqueueSync(exec common.Executor, campaign *Campaign, address string) error {
+	for _, partner := range campaign.Partners {
+		args := []interface{}{partner, campaign.Name, address}
+		completed, args := requiredCompleted(campaign, address, args)
+		querySql := "INSERT INTO partner_sync_outbox (partner, campaign, account_address, task_id) " +
+			"SELECT $1, campaign, account_address, COALESCE(task_id, '') FROM address_tasks " +
+			"WHERE campaign = $2 AND LOWER(account_address) = LOWER($3) AND " + completed +
+			" ORDER BY id LIMIT 1 ON CONFLICT DO NOTHING"
+		if _, err := exec.Exec(querySql, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dequeueSync drops the syncs of the address not sent yet once it no longer succeeds the required tasks
+// of the campaign
+func dequeueSync(exec common.Executor, campaign *Campaign, address string) error {
+	args := []interface{}{campaign.Name, address, string(OutboxStatusSent)}
+	completed, args := requiredCompleted(campaign, address, args)
+	_, err := exec.Exec("DELETE FROM partner_sync_outbox WHERE campaign = $1 AND LOWER(account_address) = LOWER($2) "+
+		"AND status <> $3 AND NOT "+completed, args...)
+	return err
+}
+
+// requiredCompleted returns the condition that the address succeeded all the required tasks of the
+// campaign, with args extended by its placeholders
+func requiredCompleted(campaign *Campaign, address string, args []interface{}) (string, []interface{}) {
+	args = append(args, address)
+	return requiredCompletedBy(campaign, fmt.Sprintf("$%d", len(args)), args)
+}
+
+// requiredCompletedBy is requiredCompleted for an address given as an sql expression, such as a column
+// of the enclosing query. The placeholders follow the address so they appear in order, which sqlite
+// needs to bind them.
+func requiredCompletedBy(campaign *Campaign, address string, args []interface{}) (string, []interface{}) {
+	args = append(args, campaign.Name, string(types.TaskStatusSuccess))
+	n := len(args)
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(fmt.Sprintf("(SELECT COUNT(DISTINCT task_name) FROM address_tasks done WHERE "+
+		"LOWER(done.account_address) = LOWER(%s) AND done.campaign = $%d AND done.task_status = $%d AND done.task_name IN (",
+		address, n-1, n))
+	required := campaign.RequiredTasks()
+	for i, task := range required {
+		if i > 0 {
+			queryBuilder.WriteString(",")
+		}
+		args = append(args, task.Name)
+		queryBuilder.WriteString(fmt.Sprintf("$%d", len(args)))
+	}
+	queryBuilder.WriteString(fmt.Sprintf(")) = %d", len(required)))
+	return queryBuilder.String(), args
+}
+
+// ClaimOutbox leases up to limit due items for the lease, so concurrent workers skip them while they
+// are being sent. An item whose worker dies is retried once the lease expires.
+func ClaimOutbox(db *sql.DB, limit int, lease time.Duration) ([]*OutboxItem, error) {
+	rows, err := db.Query("UPDATE partner_sync_outbox SET next_attempt_at = CURRENT_TIMESTAMP + $1 * interval '1 second', "+
+		"gmt_modify = CURRENT_TIMESTAMP WHERE id IN (SELECT id FROM partner_sync_outbox "+
+		"WHERE status = $2 AND next_attempt_at <= CURRENT_TIMESTAMP ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED) "+
+		"RETURNING "+outboxColumns, int64(lease.Seconds()), string(OutboxStatusPending), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutbox(rows)
+}
+
+// FailOutbox records a failed attempt of the item, it is retried after the backoff, or dead lettered if dead
+func FailOutbox(db *sql.DB, id int64, reason string, backoff time.Duration, dead bool) error {
+	status := OutboxStatusPending
+	if dead {
+		status = OutboxStatusDead
+	}
+	_, err := db.Exec("UPDATE partner_sync_outbox SET status = $1, attempts = attempts + 1, last_error = $2, "+
+		"next_attempt_at = CURRENT_TIMESTAMP + $3 * interval '1 second', gmt_modify = CURRENT_TIMESTAMP WHERE id = $4",
+		string(status), reason, int64(backoff.Seconds()), id)
+	return err
+}
+
+// RetryOutbox puts a dead lettered item back to the queue with a fresh budget of attempts
+func RetryOutbox(db *sql.DB, id int64) (bool, error) {
+	res, err := db.Exec("UPDATE partner_sync_outbox SET status = $1, attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, "+
+		"gmt_modify = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3", string(OutboxStatusPending), id, string(OutboxStatusDead))
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// GetOutbox returns the items of the status, latest first
+func GetOutbox(db *sql.DB, status OutboxStatus, limit int) ([]*OutboxItem, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := db.Query("SELECT "+outboxColumns+" FROM partner_sync_outbox WHERE status = $1 ORDER BY id DESC LIMIT $2",
+		string(status), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutbox(rows)
+}
+
+func scanOutbox(rows *sql.Rows) ([]*OutboxItem, error) {
+	defer rows.Close()
+
+	var items []*OutboxItem
+	for rows.Next() {
+		item := &OutboxItem{}
+		var status string
+		if err := rows.Scan(&item.ID, &item.Partner, &item.Campaign, &item.AccountAddress, &item.TaskId, &status,
+			&item.Attempts, &item.NextAttemptAt, &item.LastError, &item.GMTCreate, &item.GMTModify); err != nil {
+			return nil, err
+		}
+		item.Status = OutboxStatus(status)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,111 @@
+package biz
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// PartnerGoPlus is the partner of the campaigns not naming their partners
+const PartnerGoPlus = "goplus"
+
+var (
+	partnersMu sync.RWMutex
+	partners   = make(map[string]common.Partner)
+)
+
+// SetPartners replaces the partners the completions are reported to, init by main
+func SetPartners(list []common.Partner) {
+	byName := make(map[string]common.Partner, len(list))
+	for _, partner := range list {
+		byName[partner.Name()] = partner
+	}
+
+	partnersMu.Lock()
+	defer partnersMu.Unlock()
+	partners = byName
+}
+
+// GetPartner returns the partner with the name
+func GetPartner(name string) (common.Partner, bool) {
+	partnersMu.RLock()
+	defer partnersMu.RUnlock()
+	partner, ok := partners[name]
+	return partner, ok
+}
+
+// ReportCompletion reports the campaign completed by the address to the partner. The delivery is recorded
+// in the outbox, and the sync task of the campaign succeeds once every partner of the campaign has it.
+func ReportCompletion(db *sql.DB, partnerName, campaignName, address, taskId string) error {
+	campaign, err := GetCatalog().Campaign(campaignName)
+	if err != nil {
+		return err
+	}
+	partner, ok := GetPartner(partnerName)
+	if !ok {
+		return fmt.Errorf("partner %s is not configured", partnerName)
+	}
+
+	err = partner.ReportCompletion(&common.Completion{
+		Campaign:     campaign.Name,
+		Address:      address,
+		TaskId:       taskId,
+		CompleteTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("INSERT INTO partner_sync_outbox (partner, campaign, account_address, task_id, status, attempts) "+
+		"VALUES ($1, $2, $3, $4, $5, 1) ON CONFLICT (partner, campaign, (LOWER(account_address))) DO UPDATE SET "+
+		"status = $5, attempts = partner_sync_outbox.attempts + 1, last_error = '', gmt_modify = CURRENT_TIMESTAMP",
+		partner.Name(), campaign.Name, address, taskId, string(OutboxStatusSent))
+	if err != nil {
+		return err
+	}
+	return completeSyncTask(db, campaign, address, taskId)
+}
+
+// completeSyncTask verifies the sync task of the campaign once every partner has been reported the completion
+func completeSyncTask(db *sql.DB, campaign *Campaign, address, taskId string) error {
+	syncTask, ok := campaign.HandlerTask(HandlerSync)
+	if !ok {
+		return nil
+	}
+
+	args := []interface{}{campaign.Name, address, string(OutboxStatusSent)}
+	placeholders := make([]string, len(campaign.Partners))
+	for i, partner := range campaign.Partners {
+		args = append(args, partner)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	var sent int
+	err := db.QueryRow("SELECT COUNT(DISTINCT partner) FROM partner_sync_outbox WHERE campaign = $1 AND "+
+		"LOWER(account_address) = LOWER($2) AND status = $3 AND partner IN ("+strings.Join(placeholders, ",")+")",
+		args...).Scan(&sent)
+	if err != nil || sent < len(campaign.Partners) {
+		return err
+	}
+
+	status := string(types.TaskStatusSuccess)
+	memo := "reported to " + strings.Join(campaign.Partners, ",")
+	query := &UpdateTaskQuery{
+		TaskTopic:      &syncTask.Topic,
+		TaskStatus:     &status,
+		Memo:           &memo,
+		AccountAddress: &address,
+		TaskName:       &syncTask.Name,
+		Campaign:       &campaign.Name,
+		Transition:     types.TransitionVerify,
+		Actor:          HandlerSync,
+	}
+	if taskId != "" {
+		query.TaskId = &taskId
+	}
+	return UpdateTask(db, query)
+}
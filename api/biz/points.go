@@ -0,0 +1,236 @@
+package biz
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// kinds of the points ledger entries
+const (
+	PointsKindTask       = "task"
+	PointsKindAdjustment = "adjustment"
+	PointsKindReferral   = "referral"
+)
+
+// maxLeaderboardSize caps the page size of the leaderboard
+const maxLeaderboardSize = 100
+
+// PointsEntry is a credit or debit of the points of an address in a campaign
+type PointsEntry struct {
+	ID             int64     `json:"id"`
+	Campaign       string    `json:"campaign"`
+	AccountAddress string    `json:"accountAddress"`
+	Points         int64     `json:"points"`
+	Kind           string    `json:"kind"`
+	AddressTaskID  int64     `json:"addressTaskId,omitempty"`
+	TaskName       string    `json:"taskName,omitempty"`
+	Actor          string    `json:"actor"`
+	Memo           string    `json:"memo,omitempty"`
+	GMTCreate      time.Time `json:"gmtCreate"`
+}
+
+// PointsBalance is the total of the ledger entries of an address in a campaign. Rank is computed
+// periodically by the ranker, it is nil until the address gets ranked.
+type PointsBalance struct {
+	Campaign       string    `json:"campaign"`
+	AccountAddress string    `json:"accountAddress"`
+	Points         int64     `json:"points"`
+	Rank           *int64    `json:"rank"`
+	GMTModify      time.Time `json:"gmtModify"`
+}
+
+// creditBalances adds the entries a statement inserted to the balances, the statement is given as a cte
+// named credited returning campaign, account_address and points.
+const creditBalances = "INSERT INTO points_balances (campaign, account_address, points) " +
+	"SELECT campaign, MIN(account_address), SUM(points) FROM credited GROUP BY campaign, LOWER(account_address) " +
+	"ON CONFLICT (campaign, LOWER(account_address)) DO UPDATE SET points = points_balances.points + EXCLUDED.points, " +
+	"gmt_modify = CURRENT_TIMESTAMP"
+
+// awardTaskPoints credits the points of the task to the addresses which succeeded it, among the ones
+// selected by where whose placeholders start at $6. A task is credited only once, so it is safe to run
+// again on tasks already credited.
+func awardTaskPoints(exec common.Executor, campaign *Campaign, taskName, actor, where string, args ...interface{}) error {
+	task, ok := campaign.Task(taskName)
+	if !ok || task.Points == 0 {
+		return nil
+	}
+	args = append([]interface{}{task.Points, actor, campaign.Name, task.Name, string(types.TaskStatusSuccess)}, args...)
+	_, err := exec.Exec("WITH credited AS (INSERT INTO points_ledger "+
+		"(campaign, account_address, points, kind, address_task_id, task_name, actor) "+
+		"SELECT campaign, account_address, $1, '"+PointsKindTask+"', id, task_name, $2 FROM address_tasks "+
+		"WHERE campaign = $3 AND task_name = $4 AND task_status = $5 AND "+where+" "+
+		"ON CONFLICT DO NOTHING RETURNING campaign, account_address, points) "+creditBalances, args...)
+	return err
+}
+
+// creditBalance adds points to the balance of the address, for an entry written to the ledger one at a time
+func creditBalance(exec common.Executor, campaign, address string, points int64) error {
+	_, err := exec.Exec("INSERT INTO points_balances (campaign, account_address, points) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (campaign, LOWER(account_address)) DO UPDATE SET points = points_balances.points + EXCLUDED.points, "+
+		"gmt_modify = CURRENT_TIMESTAMP", campaign, address, points)
+	return err
+}
+
+// voidTaskPoints takes back the points credited to the tasks which are no longer succeeded, among the ones
+// selected by where whose placeholders start at $3. The tasks are credited again once they succeed again.
+func voidTaskPoints(exec common.Executor, where string, args ...interface{}) error {
+	args = append([]interface{}{PointsKindTask, string(types.TaskStatusSuccess)}, args...)
+	_, err := exec.Exec("WITH credited AS (DELETE FROM points_ledger WHERE kind = $1 AND address_task_id IN "+
+		"(SELECT id FROM address_tasks WHERE task_status <> $2 AND "+where+") "+
+		"RETURNING campaign, account_address, -points AS points) "+creditBalances, args...)
+	return err
+}
+
+// awardSucceededTasks credits the points of the tasks which just succeeded
+func awardSucceededTasks(exec common.Executor, tasks []AddressTask, actor string) error {
+	for _, task := range tasks {
+		if task.Campaign == nil || task.TaskName == nil {
+			continue
+		}
+		campaign, err := GetCatalog().Campaign(*task.Campaign)
+		if err != nil || *task.Campaign == "" {
+			continue
+		}
+		if err := awardTaskPoints(exec, campaign, *task.TaskName, actor, "id = $6", task.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillPoints credits the succeeded tasks which have not been credited, such as the ones completed
+// before the task was given points
+func BackfillPoints(db *sql.DB) error {
+	for _, campaign := range GetCatalog().Campaigns() {
+		for _, task := range campaign.Tasks {
+			if err := awardTaskPoints(db, campaign, task.Name, ActorAdmin, "TRUE"); err != nil {
+				return fmt.Errorf("backfill points of task %s of campaign %s: %w", task.Name, campaign.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AdjustPoints writes an admin adjustment to the ledger, points may be negative to take points back
+func AdjustPoints(db *sql.DB, campaign, address string, points int64, memo, actor string) (*PointsEntry, error) {
+	if points == 0 {
+		return nil, errors.New("adjustment must not be 0")
+	}
+	c, err := GetCatalog().Campaign(campaign)
+	if err != nil {
+		return nil, err
+	}
+	address, err = common.CanonicalAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if actor == "" {
+		actor = ActorAdmin
+	}
+
+	entry := &PointsEntry{}
+	err = db.QueryRow("WITH entry AS (INSERT INTO points_ledger (campaign, account_address, points, kind, actor, memo) "+
+		"VALUES ($1, $2, $3, $4, $5, $6) RETURNING "+pointsEntryColumns+"), "+
+		"credited AS (SELECT campaign, account_address, points FROM entry), "+
+		"balance AS ("+creditBalances+") SELECT * FROM entry",
+		c.Name, address, points, PointsKindAdjustment, actor, memo).
+		Scan(&entry.ID, &entry.Campaign, &entry.AccountAddress, &entry.Points, &entry.Kind, &entry.AddressTaskID,
+			&entry.TaskName, &entry.Actor, &entry.Memo, &entry.GMTCreate)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RefreshRanks recomputes the ranks of the campaigns, addresses with the same points share the rank.
+// It only writes the balances whose rank moved, and returns their number.
+func RefreshRanks(db *sql.DB) (int64, error) {
+	res, err := db.Exec("UPDATE points_balances SET rank = ranked.rank FROM " +
+		"(SELECT id, RANK() OVER (PARTITION BY campaign ORDER BY points DESC) AS rank FROM points_balances) ranked " +
+		"WHERE points_balances.id = ranked.id AND points_balances.rank IS DISTINCT FROM ranked.rank")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetPointsBalance returns the balance of the address, nil if it has never been credited
+func GetPointsBalance(db *sql.DB, campaign, address string) (*PointsBalance, error) {
+	balance := &PointsBalance{}
+	err := db.QueryRow("SELECT campaign, account_address, points, rank, gmt_modify FROM points_balances "+
+		"WHERE campaign = $1 AND LOWER(account_address) = LOWER($2)", campaign, address).
+		Scan(&balance.Campaign, &balance.AccountAddress, &balance.Points, &balance.Rank, &balance.GMTModify)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+const pointsEntryColumns = "id, campaign, account_address, points, kind, COALESCE(address_task_id, 0), task_name, actor, memo, gmt_create"
+
+// GetPointsHistory returns the ledger entries of the address, latest first
+func GetPointsHistory(db *sql.DB, campaign, address string, limit, offset int) ([]*PointsEntry, error) {
+	if limit <= 0 || limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+	rows, err := db.Query("SELECT "+pointsEntryColumns+" FROM points_ledger WHERE campaign = $1 "+
+		"AND LOWER(account_address) = LOWER($2) ORDER BY id DESC LIMIT $3 OFFSET $4", campaign, address, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*PointsEntry
+	for rows.Next() {
+		entry := &PointsEntry{}
+		if err := rows.Scan(&entry.ID, &entry.Campaign, &entry.AccountAddress, &entry.Points, &entry.Kind,
+			&entry.AddressTaskID, &entry.TaskName, &entry.Actor, &entry.Memo, &entry.GMTCreate); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetLeaderboard returns a page of the ranked balances of the campaign, pages start at 1. It reads the
+// precomputed ranks, so the addresses credited since the last refresh are not in it yet. It also tells
+// whether there is a next page.
+func GetLeaderboard(db *sql.DB, campaign string, page, size int) ([]*PointsBalance, bool, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size <= 0 || size > maxLeaderboardSize {
+		size = maxLeaderboardSize
+	}
+	rows, err := db.Query("SELECT campaign, account_address, points, rank, gmt_modify FROM points_balances "+
+		"WHERE campaign = $1 AND rank IS NOT NULL ORDER BY rank, id LIMIT $2 OFFSET $3",
+		campaign, size+1, (page-1)*size)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	balances := make([]*PointsBalance, 0, size)
+	for rows.Next() {
+		balance := &PointsBalance{}
+		if err := rows.Scan(&balance.Campaign, &balance.AccountAddress, &balance.Points, &balance.Rank, &balance.GMTModify); err != nil {
+			return nil, false, err
+		}
+		balances = append(balances, balance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	if len(balances) > size {
+		return balances[:size], true, nil
+	}
+	return balances, false, nil
+}
@@ -0,0 +1,284 @@
+package biz
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// statuses of a referral, it is qualified once the referee completes the campaign
+const (
+	ReferralStatusPending   = "pending"
+	ReferralStatusQualified = "qualified"
+)
+
+const (
+	// referralCodeAlphabet leaves out the characters easily mistaken for each other
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referralCodeLength   = 8
+	referralCodeAttempts = 3
+)
+
+var (
+	ErrSelfReferral    = errors.New("address cannot refer itself")
+	ErrUnknownReferrer = errors.New("referrer is not registered to the campaign")
+)
+
+// Referral links an address to the address which invited it to a campaign
+type Referral struct {
+	ID              int64      `json:"id"`
+	Campaign        string     `json:"campaign"`
+	ReferrerAddress string     `json:"referrerAddress"`
+	RefereeAddress  string     `json:"refereeAddress"`
+	Status          string     `json:"status"`
+	QualifiedAt     *time.Time `json:"qualifiedAt"`
+	GMTCreate       time.Time  `json:"gmtCreate"`
+}
+
+// Referrer counts the addresses an address invited to a campaign, and the ones which completed it
+type Referrer struct {
+	Campaign       string `json:"campaign"`
+	AccountAddress string `json:"accountAddress"`
+	Invited        int64  `json:"invited"`
+	Qualified      int64  `json:"qualified"`
+}
+
+// ReferralStats are the referral counts of an address, with its code and who referred it
+type ReferralStats struct {
+	Referrer
+	Code       string `json:"code"`
+	ReferredBy string `json:"referredBy,omitempty"`
+}
+
+// resolveReferrer returns the address of the referrer, given as an address or a referral code
+func resolveReferrer(exec common.Executor, referrer string) (string, error) {
+	if address, err := common.CanonicalAddress(referrer); err == nil {
+		return address, nil
+	}
+	var address string
+	err := exec.QueryRow("SELECT account_address FROM referral_codes WHERE code = $1", strings.ToUpper(referrer)).Scan(&address)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: unknown referral code %s", ErrUnknownReferrer, referrer)
+	}
+	return address, err
+}
+
+// checkReferral makes sure the referrer may refer the address to the campaign: it is not the address
+// itself, and it has registered to the campaign. As the address is registering, it cannot have referred
+// anyone yet, which keeps the referral graph free of cycles.
+func checkReferral(exec common.Executor, campaign, referrer, address string) error {
+	if strings.EqualFold(referrer, address) {
+		return ErrSelfReferral
+	}
+	var registered bool
+	err := exec.QueryRow("SELECT EXISTS(SELECT 1 FROM address_tasks WHERE campaign = $1 AND LOWER(account_address) = LOWER($2))",
+		campaign, referrer).Scan(&registered)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return ErrUnknownReferrer
+	}
+	return nil
+}
+
+// recordReferral stores the referral of the address and counts it for the referrer. An address is only
+// referred once per campaign.
+func recordReferral(exec common.Executor, campaign, referrer, address string) error {
+	_, err := exec.Exec("WITH referred AS (INSERT INTO referrals (campaign, referrer_address, referee_address) "+
+		"VALUES ($1, $2, $3) ON CONFLICT DO NOTHING RETURNING campaign, referrer_address) "+
+		"INSERT INTO referrers (campaign, account_address, invited) SELECT campaign, referrer_address, 1 FROM referred "+
+		"ON CONFLICT (campaign, LOWER(account_address)) DO UPDATE SET invited = referrers.invited + 1, gmt_modify = CURRENT_TIMESTAMP",
+		campaign, referrer, address)
+	return err
+}
+
+// qualifyReferral qualifies the pending referral of the address once it succeeded the required tasks of
+// the campaign, and credits the referral points of the campaign to the referrer. It runs in the transaction
+// completing the task.
+func qualifyReferral(exec common.Executor, campaign *Campaign, address, actor string) error {
+	args := []interface{}{ReferralStatusQualified, campaign.Name, address, ReferralStatusPending}
+	completed, args := requiredCompleted(campaign, address, args)
+
+	var referrer, referee string
+	err := exec.QueryRow("UPDATE referrals SET status = $1, qualified_at = CURRENT_TIMESTAMP, gmt_modify = CURRENT_TIMESTAMP "+
+		"WHERE campaign = $2 AND LOWER(referee_address) = LOWER($3) AND status = $4 AND "+completed+
+		" RETURNING referrer_address, referee_address", args...).Scan(&referrer, &referee)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := countQualified(exec, campaign.Name, referrer, 1); err != nil {
+		return err
+	}
+	if campaign.ReferralPoints == 0 {
+		return nil
+	}
+	if _, err := exec.Exec("INSERT INTO points_ledger (campaign, account_address, points, kind, actor, memo) "+
+		"VALUES ($1, $2, $3, $4, $5, $6)", campaign.Name, referrer, campaign.ReferralPoints, PointsKindReferral, actor, referee); err != nil {
+		return err
+	}
+	return creditBalance(exec, campaign.Name, referrer, campaign.ReferralPoints)
+}
+
+// disqualifyReferral puts the qualified referral of the address back to pending once it no longer succeeds
+// the required tasks of the campaign, and takes back the referral points credited to the referrer
+func disqualifyReferral(exec common.Executor, campaign *Campaign, address string) error {
+	args := []interface{}{ReferralStatusPending, campaign.Name, address, ReferralStatusQualified}
+	completed, args := requiredCompleted(campaign, address, args)
+
+	var referrer, referee string
+	err := exec.QueryRow("UPDATE referrals SET status = $1, qualified_at = NULL, gmt_modify = CURRENT_TIMESTAMP "+
+		"WHERE campaign = $2 AND LOWER(referee_address) = LOWER($3) AND status = $4 AND NOT "+completed+
+		" RETURNING referrer_address, referee_address", args...).Scan(&referrer, &referee)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := countQualified(exec, campaign.Name, referrer, -1); err != nil {
+		return err
+	}
+
+	var points int64
+	if err := exec.QueryRow("SELECT COALESCE(SUM(points), 0) FROM points_ledger WHERE campaign = $1 AND kind = $2 "+
+		"AND LOWER(account_address) = LOWER($3) AND memo = $4", campaign.Name, PointsKindReferral, referrer, referee).Scan(&points); err != nil {
+		return err
+	}
+	if points == 0 {
+		return nil
+	}
+	if _, err := exec.Exec("DELETE FROM points_ledger WHERE campaign = $1 AND kind = $2 AND LOWER(account_address) = LOWER($3) "+
+		"AND memo = $4", campaign.Name, PointsKindReferral, referrer, referee); err != nil {
+		return err
+	}
+	return creditBalance(exec, campaign.Name, referrer, -points)
+}
+
+// countQualified moves the count of the qualified referrals of the referrer by delta
+func countQualified(exec common.Executor, campaign, referrer string, delta int64) error {
+	_, err := exec.Exec("UPDATE referrers SET qualified = qualified + $1, gmt_modify = CURRENT_TIMESTAMP "+
+		"WHERE campaign = $2 AND LOWER(account_address) = LOWER($3)", delta, campaign, referrer)
+	return err
+}
+
+// GetReferralCode returns the referral code of the address, it is created on the first call
+func GetReferralCode(db *sql.DB, address string) (string, error) {
+	address, err := common.CanonicalAddress(address)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < referralCodeAttempts; i++ {
+		code, err := newReferralCode()
+		if err != nil {
+			return "", err
+		}
+		// the insert is skipped if the address already has a code, or on the rare clash of codes
+		if _, err := db.Exec("INSERT INTO referral_codes (code, account_address) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			code, address); err != nil {
+			return "", err
+		}
+		err = db.QueryRow("SELECT code FROM referral_codes WHERE LOWER(account_address) = LOWER($1)", address).Scan(&code)
+		if err == nil {
+			return code, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("failed to create a referral code for %s", address)
+}
+
+func newReferralCode() (string, error) {
+	raw := make([]byte, referralCodeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := make([]byte, referralCodeLength)
+	for i, b := range raw {
+		code[i] = referralCodeAlphabet[int(b)%len(referralCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// GetReferralStats returns the referral counts of the address in the campaign, with its code if it has one
+func GetReferralStats(db *sql.DB, campaign, address string) (*ReferralStats, error) {
+	stats := &ReferralStats{Referrer: Referrer{Campaign: campaign, AccountAddress: address}}
+	err := db.QueryRow("SELECT "+
+		"COALESCE((SELECT invited FROM referrers WHERE campaign = $1 AND LOWER(account_address) = LOWER($2)), 0), "+
+		"COALESCE((SELECT qualified FROM referrers WHERE campaign = $1 AND LOWER(account_address) = LOWER($2)), 0), "+
+		"COALESCE((SELECT code FROM referral_codes WHERE LOWER(account_address) = LOWER($2)), ''), "+
+		"COALESCE((SELECT referrer_address FROM referrals WHERE campaign = $1 AND LOWER(referee_address) = LOWER($2)), '')",
+		campaign, address).Scan(&stats.Invited, &stats.Qualified, &stats.Code, &stats.ReferredBy)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetReferrals returns the addresses the address referred to the campaign, latest first
+func GetReferrals(db *sql.DB, campaign, address string, limit, offset int) ([]*Referral, error) {
+	if limit <= 0 || limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+	rows, err := db.Query("SELECT id, campaign, referrer_address, referee_address, status, qualified_at, gmt_create "+
+		"FROM referrals WHERE campaign = $1 AND LOWER(referrer_address) = LOWER($2) ORDER BY id DESC LIMIT $3 OFFSET $4",
+		campaign, address, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var referrals []*Referral
+	for rows.Next() {
+		referral := &Referral{}
+		if err := rows.Scan(&referral.ID, &referral.Campaign, &referral.ReferrerAddress, &referral.RefereeAddress,
+			&referral.Status, &referral.QualifiedAt, &referral.GMTCreate); err != nil {
+			return nil, err
+		}
+		referrals = append(referrals, referral)
+	}
+	return referrals, rows.Err()
+}
+
+// GetReferralLeaderboard returns a page of the referrers of the campaign with the most qualified referrals,
+// pages start at 1. It also tells whether there is a next page.
+func GetReferralLeaderboard(db *sql.DB, campaign string, page, size int) ([]*Referrer, bool, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size <= 0 || size > maxLeaderboardSize {
+		size = maxLeaderboardSize
+	}
+	rows, err := db.Query("SELECT campaign, account_address, invited, qualified FROM referrers "+
+		"WHERE campaign = $1 AND qualified > 0 ORDER BY qualified DESC, id LIMIT $2 OFFSET $3",
+		campaign, size+1, (page-1)*size)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	referrers := make([]*Referrer, 0, size)
+	for rows.Next() {
+		referrer := &Referrer{}
+		if err := rows.Scan(&referrer.Campaign, &referrer.AccountAddress, &referrer.Invited, &referrer.Qualified); err != nil {
+			return nil, false, err
+		}
+		referrers = append(referrers, referrer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	if len(referrers) > size {
+		return referrers[:size], true, nil
+	}
+	return referrers, false, nil
+}
@@ -0,0 +1,160 @@
+package biz
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/db/migrate"
+)
+
+const (
+	testReferrer = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	testReferee  = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "biz.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := migrate.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+	_, err = migrator.Up(0)
+	require.NoError(t, err)
+	return db
+}
+
+func newReferralCampaign(t *testing.T) *Campaign {
+	c, err := NewCatalog([]*config.CampaignConfig{{
+		Name:           "ref",
+		ReferralPoints: 5,
+		Tasks: []*config.CampaignTaskConfig{
+			{Name: "Join", Required: true, Handler: HandlerManual},
+			{Name: "Share", Handler: HandlerManual},
+		},
+	}})
+	require.NoError(t, err)
+	campaign, err := c.Campaign("ref")
+	require.NoError(t, err)
+	return campaign
+}
+
+func insertTask(t *testing.T, db *sql.DB, campaign, address, taskName string, status types.TaskStatus) {
+	_, err := db.Exec("INSERT INTO address_tasks (account_address, task_name, task_status, campaign) VALUES ($1, $2, $3, $4)",
+		address, taskName, string(status), campaign)
+	require.NoError(t, err)
+}
+
+func TestNewReferralCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := newReferralCode()
+		require.NoError(t, err)
+		require.Len(t, code, referralCodeLength)
+		for _, ch := range code {
+			require.True(t, strings.ContainsRune(referralCodeAlphabet, ch), "unexpected %q in %s", ch, code)
+		}
+		seen[code] = true
+	}
+	require.Greater(t, len(seen), 90)
+}
+
+func TestResolveReferrer(t *testing.T) {
+	db := newTestDB(t)
+	_, err := db.Exec("INSERT INTO referral_codes (code, account_address) VALUES ($1, $2)", "ABCD2345", testReferrer)
+	require.NoError(t, err)
+
+	// an address is taken as is, in its checksum form
+	address, err := resolveReferrer(db, strings.ToLower(testReferrer))
+	require.NoError(t, err)
+	require.Equal(t, testReferrer, address)
+
+	// codes are matched in any case
+	address, err = resolveReferrer(db, "abcd2345")
+	require.NoError(t, err)
+	require.Equal(t, testReferrer, address)
+
+	_, err = resolveReferrer(db, "ZZZZ9999")
+	require.ErrorIs(t, err, ErrUnknownReferrer)
+}
+
+func TestCheckReferral(t *testing.T) {
+	db := newTestDB(t)
+
+	require.ErrorIs(t, checkReferral(db, "ref", strings.ToLower(testReferee), testReferee), ErrSelfReferral)
+	require.ErrorIs(t, checkReferral(db, "ref", testReferrer, testReferee), ErrUnknownReferrer)
+
+	// the referrer must have registered to the same campaign
+	insertTask(t, db, "other", testReferrer, "Join", types.TaskStatusNew)
+	require.ErrorIs(t, checkReferral(db, "ref", testReferrer, testReferee), ErrUnknownReferrer)
+	insertTask(t, db, "ref", strings.ToLower(testReferrer), "Join", types.TaskStatusNew)
+	require.NoError(t, checkReferral(db, "ref", testReferrer, testReferee))
+}
+
+func TestQualifyReferral(t *testing.T) {
+	db := newTestDB(t)
+	campaign := newReferralCampaign(t)
+
+	insertTask(t, db, "ref", testReferrer, "Join", types.TaskStatusSuccess)
+	insertTask(t, db, "ref", testReferee, "Join", types.TaskStatusNew)
+	insertTask(t, db, "ref", testReferee, "Share", types.TaskStatusSuccess)
+	_, err := db.Exec("INSERT INTO referrals (campaign, referrer_address, referee_address) VALUES ($1, $2, $3)", "ref", testReferrer, testReferee)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO referrers (campaign, account_address, invited) VALUES ($1, $2, 1)", "ref", testReferrer)
+	require.NoError(t, err)
+
+	referral := func() (string, int64, int64) {
+		var status string
+		var qualified, points int64
+		require.NoError(t, db.QueryRow("SELECT status FROM referrals WHERE campaign = 'ref'").Scan(&status))
+		require.NoError(t, db.QueryRow("SELECT qualified FROM referrers WHERE campaign = 'ref'").Scan(&qualified))
+		require.NoError(t, db.QueryRow("SELECT COALESCE(SUM(points), 0) FROM points_balances WHERE campaign = 'ref'").Scan(&points))
+		return status, qualified, points
+	}
+
+	// the referee has not succeeded the required tasks yet
+	require.NoError(t, qualifyReferral(db, campaign, testReferee, ActorIndexer))
+	status, qualified, points := referral()
+	require.Equal(t, ReferralStatusPending, status)
+	require.Zero(t, qualified)
+	require.Zero(t, points)
+
+	_, err = db.Exec("UPDATE address_tasks SET task_status = $1 WHERE account_address = $2 AND task_name = 'Join'",
+		string(types.TaskStatusSuccess), testReferee)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		// the referral is only qualified and credited once
+		require.NoError(t, qualifyReferral(db, campaign, strings.ToLower(testReferee), ActorIndexer))
+		status, qualified, points = referral()
+		require.Equal(t, ReferralStatusQualified, status)
+		require.Equal(t, int64(1), qualified)
+		require.Equal(t, campaign.ReferralPoints, points)
+	}
+	var memo string
+	require.NoError(t, db.QueryRow("SELECT memo FROM points_ledger WHERE kind = $1", PointsKindReferral).Scan(&memo))
+	require.Equal(t, testReferee, memo)
+
+	// a referee which still succeeds the required tasks keeps its referral
+	require.NoError(t, disqualifyReferral(db, campaign, testReferee))
+	status, _, _ = referral()
+	require.Equal(t, ReferralStatusQualified, status)
+
+	_, err = db.Exec("UPDATE address_tasks SET task_status = $1 WHERE account_address = $2 AND task_name = 'Join'",
+		string(types.TaskStatusNew), testReferee)
+	require.NoError(t, err)
+	require.NoError(t, disqualifyReferral(db, campaign, testReferee))
+	status, qualified, points = referral()
+	require.Equal(t, ReferralStatusPending, status)
+	require.Zero(t, qualified)
+	require.Zero(t, points)
+	var entries int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM points_ledger").Scan(&entries))
+	require.Zero(t, entries)
+}
@@ -11,55 +11,108 @@ import (
 )
 
 func GetFaucetTask(db *sql.DB, limit int) ([]AddressTask, error) {
-	return lockTasksForHandler(db, limit, types.Task_Name_GetFaucet)
+	return lockTasksForHandler(db, limit, HandlerFaucet)
 }
 
 func GetAspectPullTask(db *sql.DB, limit int) ([]AddressTask, error) {
-	return lockTasksForHandler(db, limit, types.Task_Name_AspectPull)
+	return lockTasksForHandler(db, limit, HandlerRug)
 }
 
 func GetAddLiquidityTask(db *sql.DB, limit int) ([]AddressTask, error) {
-	return lockTasksForHandler(db, limit, types.Task_Name_AddLiquidity)
+	return lockTasksForHandler(db, limit, HandlerUpdater)
 }
 
-func lockTasksForHandler(db *sql.DB, limit int, whereTaskName string) ([]AddressTask, error) {
-
-	SetStatus := string(types.TaskStatusProcessing)
-	whereStatus := string(types.TaskStatusPending)
+// lockTasksForHandler locks the pending tasks which the catalog assigns to the handler
+func lockTasksForHandler(db *sql.DB, limit int, handler string) ([]AddressTask, error) {
 	uuidV4 := uuid.New().String()
 
-	if limit == 0 && whereTaskName == "" {
-		return nil, fmt.Errorf("limit or whereTaskName cannot be empty")
+	if limit == 0 && handler == "" {
+		return nil, fmt.Errorf("limit or handler cannot be empty")
 	}
-
-	limitSql := " LIMIT $5) "
-	if strings.EqualFold(whereTaskName, types.Task_Name_AddLiquidity) {
-		limitSql = "and txs IS NOT NULL LIMIT $5) "
+	args := []interface{}{uuidV4, limit}
+	var tasks []string
+	for _, campaign := range GetCatalog().Campaigns() {
+		for _, task := range campaign.Tasks {
+			if task.Handler != handler {
+				continue
+			}
+			args = append(args, campaign.Name, task.Name)
+			condition := fmt.Sprintf("(campaign = $%d AND task_name = $%d", len(args)-1, len(args))
+			// the task is only picked up once its prerequisites succeed
+			if len(task.DependsOn) > 0 {
+				prerequisites := make([]string, len(task.DependsOn))
+				for i, name := range task.DependsOn {
+					args = append(args, name)
+					prerequisites[i] = fmt.Sprintf("$%d", len(args))
+				}
+				args = append(args, string(types.TaskStatusSuccess))
+				condition += " AND NOT EXISTS (SELECT 1 FROM address_tasks pre WHERE pre.campaign = address_tasks.campaign " +
+					"AND LOWER(pre.account_address) = LOWER(address_tasks.account_address) AND pre.task_name IN (" +
+					strings.Join(prerequisites, ",") + fmt.Sprintf(") AND pre.task_status <> $%d)", len(args))
+			}
+			tasks = append(tasks, condition+")")
+		}
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	where := "(" + strings.Join(tasks, " OR ") + ") "
+	// the updater checks the txs sent by the users, so only the tasks with txs are ready
+	if handler == HandlerUpdater {
+		where += "and txs IS NOT NULL "
 	}
-	querySql := "UPDATE address_tasks SET task_status = $1, job_batch_id = $2, gmt_modify = CURRENT_TIMESTAMP WHERE id IN (SELECT id FROM address_tasks WHERE task_name=$3 and task_status = $4 " + limitSql
 
-	// 执行 UPDATE 语句
-	_, err := db.Exec(querySql, SetStatus, uuidV4, whereTaskName, whereStatus, limit)
-	if err != nil {
+	// every lock is an attempt of the task
+	if _, err := moveTasks(db, types.TransitionLock, handler, "", "job_batch_id = $1, attempts = attempts + 1", where+"LIMIT $2", args...); err != nil {
 		return nil, err
 	}
 	// get tasks for schedule
 	query := &TaskQuery{
-		TaskName:   whereTaskName,
-		TaskStatus: SetStatus,
+		TaskStatus: string(types.TaskStatusProcessing),
 		JobBatchId: uuidV4,
 	}
 	return GetTasks(db, query)
 }
 
-// let timeout data retry
+// LetTimeoutRecordRetry hands the tasks held by a handler for longer than their timeout back to the handlers,
+// and abandons the ones which timed out on their last attempt. It returns the number of tasks moved.
 func LetTimeoutRecordRetry(db *sql.DB) (int64, error) {
-	selectSql := "UPDATE address_tasks SET task_status = '1', job_batch_id = null , gmt_modify = CURRENT_TIMESTAMP where gmt_modify < current_timestamp - interval '10 minutes' and (task_status='2')"
-	res, err := db.Exec(selectSql)
+	where, args := timedOutTasks(false, nil)
+	released, err := moveTasks(db, types.TransitionRelease, ActorCleaner, "timeout", "job_batch_id = null", where, args...)
 	if err != nil {
 		return 0, err
 	}
+	where, args = timedOutTasks(true, []interface{}{FailCodeTimeout, "no result from the handler before the timeout"})
+	abandoned, err := moveTasks(db, types.TransitionAbandon, ActorCleaner, "timeout",
+		"job_batch_id = null, fail_code = $1, fail_reason = $2", where, args...)
+	return released + abandoned, err
+}
 
-	rowsAffected, _ := res.RowsAffected()
-	return rowsAffected, nil
+// timedOutTasks returns the condition selecting the tasks held for longer than their timeout, either the ones
+// which have attempts left or the ones which used them all, with args extended by its placeholders. The tasks
+// missing from the catalog get the default timeout and attempts.
+func timedOutTasks(exhausted bool, args []interface{}) (string, []interface{}) {
+	attempts := "<"
+	if exhausted {
+		attempts = ">="
+	}
+	var conditions, cataloged []string
+	for _, campaign := range GetCatalog().Campaigns() {
+		for _, task := range campaign.Tasks {
+			args = append(args, campaign.Name, task.Name, task.MaxAttempts, int64(task.Timeout().Seconds()))
+			n := len(args)
+			conditions = append(conditions, fmt.Sprintf("(campaign = $%d AND task_name = $%d AND attempts %s $%d "+
+				"AND gmt_modify < CURRENT_TIMESTAMP - $%d * interval '1 second')", n-3, n-2, attempts, n-1, n))
+			cataloged = append(cataloged, fmt.Sprintf("($%d, $%d)", n-3, n-2))
+		}
+	}
+
+	args = append(args, DefaultTaskMaxAttempts, int64(DefaultTaskTimeout.Seconds()))
+	n := len(args)
+	fallback := fmt.Sprintf("attempts %s $%d AND gmt_modify < CURRENT_TIMESTAMP - $%d * interval '1 second'", attempts, n-1, n)
+	if len(cataloged) > 0 {
+		fallback = "(COALESCE(campaign, ''), task_name) NOT IN (" + strings.Join(cataloged, ", ") + ") AND " + fallback
+	}
+	conditions = append(conditions, "("+fallback+")")
+	return "(" + strings.Join(conditions, " OR ") + ")", args
 }
@@ -1,173 +1,87 @@
 package biz
 
 import (
-	"bytes"
-	"crypto/md5"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"strconv"
 	"strings"
-	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/artela-network/galxe-integration/api/types"
-	"github.com/artela-network/galxe-integration/config"
 )
 
-type PostBody struct {
-	ChannelCode   string `json:"channelCode"`
-	ChannelTaskId string `json:"channelTaskId"`
-	CompleteTime  string `json:"completeTime"`
-	UserAddress   string `json:"userAddress"`
-}
-type ResponseData struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Result  struct {
-		Status bool `json:"status"`
-	} `json:"result"`
-}
-
-// init by main
-var GoPlus_Config *config.GoPlusConfig
+// ErrAlreadySynced is returned when the sync task of the address has already succeeded
+var ErrAlreadySynced = errors.New("sync task has been completed")
 
+// SyncStatus reports the completed campaign of the address to the partners of the campaign right away, the
+// outbox worker reports the completions otherwise. It is exposed to retry by hand.
 func SyncStatus(db *sql.DB, input *InitTaskQuery) error {
-	compiled, err := CheckAllTaskCompiled(db, input.AccountAddress)
+	campaign, err := GetCatalog().Campaign(input.Campaign)
 	if err != nil {
 		return err
 	}
-	if compiled == false {
-		return fmt.Errorf(" All tasks haven't been completed")
-	}
 
-	postBody := &PostBody{
-		ChannelCode:   GoPlus_Config.ChannelCode,
-		ChannelTaskId: input.TaskId,
-		CompleteTime:  strconv.FormatInt(time.Now().Unix(), 10),
-		UserAddress:   input.AccountAddress,
-	}
-
-	client := &http.Client{Timeout: time.Second * 20}
-	// body
-
-	bytesData, _ := json.Marshal(postBody)
-	pstReq, postErr := http.NewRequest("POST", GoPlus_Config.SecwarexUrl, bytes.NewReader(bytesData))
-	if postErr != nil {
-		return postErr
+	compiled, err := CheckAllTaskCompiled(db, campaign.Name, input.AccountAddress)
+	if err != nil {
+		return err
 	}
-
-	// header
-	pstReq.Header.Add("Content-Type", "application/json")
-	pstReq.Header.Add("manageId", GoPlus_Config.ManageId)
-	pstReq.Header.Add("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
-
-	sign, s, postErr := createSign(postBody)
-	if postErr != nil {
-		return postErr
+	if compiled == false {
+		return fmt.Errorf(" All tasks haven't been completed")
 	}
-	pstReq.Header.Add("sign", sign)
-	log.Info("goplus|request|", GoPlus_Config.SecwarexUrl, "|body|", string(bytesData), "|sign|", sign, "|plaintext|", s)
 
-	resp, doErr := client.Do(pstReq)
-	if doErr != nil {
-		return doErr
-	}
-	body, readErr := io.ReadAll(resp.Body)
-	if readErr != nil {
-		return readErr
+	var errs []error
+	for _, partner := range campaign.Partners {
+		if err := ReportCompletion(db, partner, campaign.Name, input.AccountAddress, input.TaskId); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", partner, err))
+		}
 	}
-	log.Info("goplus|response|", string(body), "|address|", input.AccountAddress)
+	return errors.Join(errs...)
+}
 
-	responseData := &ResponseData{}
-	jErr := json.Unmarshal(body, responseData)
-	if jErr != nil {
-		return jErr
+// Check all required tasks of the campaign compiled
+func CheckAllTaskCompiled(db *sql.DB, campaignName string, addr string) (bool, error) {
+	campaign, err := GetCatalog().Campaign(campaignName)
+	if err != nil {
+		return false, err
 	}
 
-	// update db
-	if responseData.Result.Status == true {
-		topic := types.Task_Topic_Sys
-		status := string(types.TaskStatusSuccess)
-		taskName := types.Task_Name_Sync
-		result := string(body)
-		updateTaskQuery := &UpdateTaskQuery{
-			TaskTopic:      &topic,
-			TaskStatus:     &status,
-			Memo:           &result,
-			TaskId:         &input.TaskId,
-			AccountAddress: &input.AccountAddress,
-			TaskName:       &taskName,
+	log.Info("goplus|CheckAllTaskCompiled|address|1|", addr, "|campaign|", campaign.Name)
+	if syncTask, ok := campaign.HandlerTask(HandlerSync); ok {
+		syncTasks, getErr := GetTasks(db, &TaskQuery{AccountAddress: addr, TaskName: syncTask.Name, Campaign: campaign.Name})
+		if getErr != nil {
+			return false, getErr
+		}
+		var tasks AddressTask
+		if len(syncTasks) > 0 {
+			tasks = syncTasks[0]
+		}
+		if tasks.TaskStatus != nil {
+			log.Info("goplus|CheckAllTaskCompiled|address|2|", tasks.ID, *tasks.TaskStatus)
 		}
 
-		upErr := UpdateTask(db, updateTaskQuery)
-		if upErr != nil {
-			return upErr
+		if tasks.TaskStatus != nil && strings.EqualFold(*tasks.TaskStatus, string(types.TaskStatusSuccess)) {
+			return false, ErrAlreadySynced
 		}
 	}
-	return nil
-}
-
-func createSign(body *PostBody) (string, string, error) {
 
+	// check that all required tasks have been completed
+	required := campaign.RequiredTasks()
+	args := []interface{}{addr, string(types.TaskStatusSuccess), campaign.Name}
 	var queryBuilder strings.Builder
-	queryBuilder.WriteString("channelCode")
-	queryBuilder.WriteString(body.ChannelCode)
-
-	queryBuilder.WriteString("channelTaskId")
-	queryBuilder.WriteString(body.ChannelTaskId)
-
-	queryBuilder.WriteString("completeTime")
-	queryBuilder.WriteString(body.CompleteTime)
-
-	queryBuilder.WriteString("manageKey")
-	queryBuilder.WriteString(GoPlus_Config.ManageKey)
-
-	queryBuilder.WriteString("timestamp")
-	queryBuilder.WriteString(strconv.FormatInt(time.Now().UnixMilli(), 10))
-
-	queryBuilder.WriteString("userAddress")
-	queryBuilder.WriteString(body.UserAddress)
-
-	query := queryBuilder.String()
-	data := []byte(queryBuilder.String())
-	has := md5.Sum(data)
-	md5str := fmt.Sprintf("%x", has)
-
-	return md5str, query, nil
-}
-
-// Check all task compiled
-func CheckAllTaskCompiled(db *sql.DB, addr string) (bool, error) {
-	log.Info("goplus|CheckAllTaskCompiled|address|1|", addr)
-	tasks, getErr := GetTask(db, addr, types.Task_Name_Sync, 0)
-	if getErr != nil {
-		return false, getErr
+	queryBuilder.WriteString("select count(distinct task_name) from address_tasks where LOWER(account_address)=LOWER($1) and task_status=$2 and campaign=$3 and task_name IN (")
+	for i, task := range required {
+		if i > 0 {
+			queryBuilder.WriteString(",")
+		}
+		queryBuilder.WriteString(fmt.Sprintf("$%d", len(args)+1))
+		args = append(args, task.Name)
 	}
-	log.Info("goplus|CheckAllTaskCompiled|address|2|", tasks.ID, *tasks.TaskStatus)
+	queryBuilder.WriteString(")")
 
-	if tasks.TaskStatus != nil && strings.EqualFold(*tasks.TaskStatus, string(types.TaskStatusSuccess)) {
-		return false, fmt.Errorf("Sync task have been completed ")
-	}
-	// check that all four tasks have been completed；
-	countSql := "select count(*) from address_tasks where LOWER(account_address)=LOWER($1) and task_status=$2 and task_topic=$3"
-	rows, err := db.Query(countSql, addr, string(types.TaskStatusSuccess), types.Task_Topic_Goplus)
-	if err != nil {
-		return false, err
-	}
-	defer rows.Close()
 	var count int
-	for rows.Next() {
-		if countErr := rows.Scan(&count); countErr != nil {
-			return false, countErr
-		}
-	}
-	if count == 4 {
-		return true, nil
-	} else {
-		return false, nil
+	if err := db.QueryRow(queryBuilder.String(), args...).Scan(&count); err != nil {
+		return false, err
 	}
+	return count == len(required), nil
 }
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+type campaignInfo struct {
+	*biz.Campaign
+	Record       *biz.CampaignRecord `json:"record"`
+	Participants int64               `json:"participants"`
+}
+
+// admin guards the routes with the admin token of config
+func (s *Server) admin(c *gin.Context) {
+	token := s.conf.APIServer.AdminToken
+	provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(provided)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Unauthorized",
+		})
+		return
+	}
+	c.Next()
+}
+
+// campaigns lists the campaigns of the catalog with their records
+func (s *Server) campaigns(c *gin.Context) {
+	records, err := biz.GetCampaigns(s.db)
+	if err != nil {
+		log.Errorf("Failed to get campaigns: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get campaigns",
+		})
+		return
+	}
+	byName := make(map[string]*biz.CampaignRecord, len(records))
+	for _, record := range records {
+		byName[record.Name] = record
+	}
+
+	campaigns := make([]*campaignInfo, 0, len(biz.GetCatalog().Campaigns()))
+	for _, campaign := range biz.GetCatalog().Campaigns() {
+		campaigns = append(campaigns, &campaignInfo{Campaign: campaign, Record: byName[campaign.Name]})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaigns,
+	})
+}
+
+func (s *Server) campaign(c *gin.Context) {
+	campaign, err := biz.GetCatalog().Campaign(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	record, err := biz.GetCampaign(s.db, campaign.Name)
+	if err != nil {
+		log.Errorf("Failed to get campaign %s: %v", campaign.Name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get campaign",
+		})
+		return
+	}
+	participants, err := biz.CountParticipants(s.db, campaign.Name)
+	if err != nil {
+		log.Errorf("Failed to count participants of campaign %s: %v", campaign.Name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get campaign",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    &campaignInfo{Campaign: campaign, Record: record, Participants: participants},
+	})
+}
+
+func (s *Server) saveCampaign(c *gin.Context) {
+	record := &biz.CampaignRecord{}
+	if err := c.ShouldBindBodyWith(record, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to bind body " + err.Error(),
+		})
+		return
+	}
+	record.Name = c.Param("name")
+
+	if err := biz.SaveCampaign(s.db, record); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to save campaign " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+func (s *Server) setCampaignStatus(c *gin.Context) {
+	input := &struct {
+		Status biz.CampaignStatus `json:"status" binding:"required"`
+	}{}
+	if err := c.ShouldBindBodyWith(input, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to bind body " + err.Error(),
+		})
+		return
+	}
+
+	if err := biz.SetCampaignStatus(s.db, c.Param("name"), input.Status); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to set campaign status " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// importAddresses registers the csv or json list of addresses of the body to the campaign, the format is
+// taken from the format query or the content type
+func (s *Server) importAddresses(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = biz.ImportFormatCSV
+		if c.ContentType() == binding.MIMEJSON {
+			format = biz.ImportFormatJSON
+		}
+	}
+	rows, err := biz.ParseImport(c.Request.Body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to parse import " + err.Error(),
+		})
+		return
+	}
+
+	report, err := biz.ImportAddresses(s.db, c.Param("name"), c.Query("task_id"), rows)
+	if err != nil {
+		log.Errorf("Failed to import addresses to campaign %s: %v", c.Param("name"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to import addresses " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+var exportContentTypes = map[string]string{
+	biz.ExportFormatCSV:    "text/csv",
+	biz.ExportFormatNDJSON: "application/x-ndjson",
+	biz.ExportFormatGalxe:  "text/plain",
+}
+
+// exportTasks streams the tasks of the campaign, the succeeded ones by default. The row count and the
+// checksum of the body are sent as trailers once it is written.
+func (s *Server) exportTasks(c *gin.Context) {
+	query, err := biz.ParseExportQuery(c.Param("name"), c.Query("task"), c.Query("status"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	format := c.DefaultQuery("format", biz.ExportFormatCSV)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Unknown export format " + format,
+		})
+		return
+	}
+	if _, err := biz.GetCatalog().Campaign(query.Campaign); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Trailer", "X-Export-Rows, X-Export-Sha256")
+	c.Status(http.StatusOK)
+	w, _ := biz.NewExportWriter(format, c.Writer)
+	if err := biz.ExportTasks(s.db, query, w); err != nil {
+		// the body has started, the trailers are left out so the export is not mistaken for a complete one
+		log.Errorf("Failed to export tasks of campaign %s: %v", query.Campaign, err)
+		return
+	}
+	summary, err := w.Close()
+	if err != nil {
+		log.Errorf("Failed to export tasks of campaign %s: %v", query.Campaign, err)
+		return
+	}
+	c.Writer.Header().Set("X-Export-Rows", strconv.FormatInt(summary.Rows, 10))
+	c.Writer.Header().Set("X-Export-Sha256", summary.Checksum)
+}
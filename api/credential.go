@@ -0,0 +1,123 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+const (
+	defaultCredentialCacheTTL = 30 * time.Second
+	// credentialCacheSize caps the entries of the cache, expired entries are dropped once it is reached
+	// and answers are not cached while it is full of live entries
+	credentialCacheSize = 10000
+)
+
+type credentialEntry struct {
+	completed bool
+	expires   time.Time
+}
+
+// credentialCache keeps the answers of the credential routes for a short ttl, galxe calls them for every
+// address of a campaign in bursts
+type credentialCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	entries map[string]credentialEntry
+}
+
+func newCredentialCache(ttl time.Duration) *credentialCache {
+	return &credentialCache{ttl: ttl, entries: make(map[string]credentialEntry)}
+}
+
+func (c *credentialCache) get(key string, now time.Time) (bool, bool) {
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return false, false
+	}
+	return entry.completed, true
+}
+
+func (c *credentialCache) set(key string, completed bool, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= credentialCacheSize {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= credentialCacheSize {
+			return
+		}
+	}
+	c.entries[key] = credentialEntry{completed: completed, expires: now.Add(c.ttl)}
+}
+
+// credential answers the galxe rest credential of a task of the catalog, the address is given by the
+// address query in lower case or checksum form. The credential expression reads resp.data.result, which
+// is 1 once the address succeeded the task.
+func (s *Server) credential(c *gin.Context) {
+	campaign, err := biz.GetCatalog().Campaign(c.Param("campaign"))
+	if err != nil || c.Param("campaign") == "" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Unknown campaign " + c.Param("campaign"),
+		})
+		return
+	}
+	task, ok := campaign.Task(c.Param("task"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Unknown task " + c.Param("task"),
+		})
+		return
+	}
+	address, err := common.CanonicalAddress(c.Query("address"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid address " + c.Query("address"),
+		})
+		return
+	}
+
+	key := strings.Join([]string{campaign.Name, task.Name, address}, "|")
+	now := time.Now()
+	completed, cached := s.credentials.get(key, now)
+	if !cached {
+		completed, err = biz.TaskCompleted(s.db, campaign.Name, task.Name, address)
+		if err != nil {
+			log.Errorf("Failed to check credential %s: %v", key, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Failed to check credential",
+			})
+			return
+		}
+		s.credentials.set(key, completed, now)
+	}
+
+	result := 0
+	if completed {
+		result = 1
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"result":   result,
+			"campaign": campaign.Name,
+			"task":     task.Name,
+			"address":  address,
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialCache(t *testing.T) {
+	cache := newCredentialCache(time.Minute)
+	now := time.Now()
+
+	_, ok := cache.get("key", now)
+	require.False(t, ok)
+
+	cache.set("key", true, now)
+	completed, ok := cache.get("key", now.Add(30*time.Second))
+	require.True(t, ok)
+	require.True(t, completed)
+
+	// the answer expires after the ttl
+	_, ok = cache.get("key", now.Add(time.Minute))
+	require.False(t, ok)
+
+	// expired entries are pruned once the cache is full
+	for i := 0; i < credentialCacheSize; i++ {
+		cache.set(strconv.Itoa(i), false, now)
+	}
+	cache.set("fresh", false, now.Add(2*time.Minute))
+	require.Len(t, cache.entries, 1)
+
+	// the cache does not grow past its size while its entries are live
+	for i := 0; i < credentialCacheSize+10; i++ {
+		cache.set(strconv.Itoa(i), true, now.Add(2*time.Minute))
+	}
+	require.Len(t, cache.entries, credentialCacheSize)
+	_, ok = cache.get(strconv.Itoa(credentialCacheSize), now.Add(2*time.Minute))
+	require.False(t, ok)
+
+	// the live entries are still refreshed
+	cache.set("fresh", true, now.Add(2*time.Minute))
+	completed, ok = cache.get("fresh", now.Add(2*time.Minute))
+	require.True(t, ok)
+	require.True(t, completed)
+}
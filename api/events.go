@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultEventPageSize = 50
+	maxEventPageSize     = 500
+)
+
+type archivedEvent struct {
+	Contract    string          `json:"contract"`
+	Event       string          `json:"event"`
+	Args        json.RawMessage `json:"args"`
+	TxHash      string          `json:"txHash"`
+	LogIndex    uint            `json:"logIndex"`
+	BlockNumber uint64          `json:"blockNumber"`
+	BlockTime   uint64          `json:"blockTime"`
+}
+
+// archivedEvents returns the events recorded by the archive indexer, filtered by contract, event, related
+// address and block range, ordered by their positions on chain.
+func (s *Server) archivedEvents(c *gin.Context) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	badRequest := func(msg string) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   msg,
+		})
+	}
+
+	if contract := c.Query("contract"); contract != "" {
+		if !common.IsHexAddress(contract) {
+			badRequest("Invalid contract address")
+			return
+		}
+		where("contract = $%d", common.HexToAddress(contract).Hex())
+	}
+	if event := c.Query("event"); event != "" {
+		where("event = $%d", event)
+	}
+	if address := c.Query("address"); address != "" {
+		if !common.IsHexAddress(address) {
+			badRequest("Invalid Ethereum address")
+			return
+		}
+		where("$%d = ANY(addresses)", common.HexToAddress(address).Hex())
+	}
+	for _, bound := range []struct{ param, condition string }{
+		{"from_block", "block_number >= $%d"},
+		{"to_block", "block_number <= $%d"},
+	} {
+		if raw := c.Query(bound.param); raw != "" {
+			block, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				badRequest("Invalid " + bound.param)
+				return
+			}
+			where(bound.condition, block)
+		}
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		badRequest("Invalid page")
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultEventPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxEventPageSize {
+		badRequest(fmt.Sprintf("page_size must be between 1 and %d", maxEventPageSize))
+		return
+	}
+
+	query := "SELECT contract, event, args, tx_hash, log_index, block_number, block_time FROM archived_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	// fetch one more row to tell whether there is a next page
+	query += fmt.Sprintf(" ORDER BY block_number, log_index LIMIT %d OFFSET %d", pageSize+1, (page-1)*pageSize)
+
+	events, err := s.queryArchivedEvents(query, args...)
+	if err != nil {
+		log.Errorf("Failed to query archived events: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query events",
+		})
+		return
+	}
+
+	hasMore := len(events) > pageSize
+	if hasMore {
+		events = events[:pageSize]
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"events":   events,
+			"page":     page,
+			"pageSize": pageSize,
+			"hasMore":  hasMore,
+		},
+	})
+}
+
+func (s *Server) queryArchivedEvents(query string, args ...interface{}) ([]archivedEvent, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]archivedEvent, 0)
+	for rows.Next() {
+		var e archivedEvent
+		var raw string
+		if err := rows.Scan(&e.Contract, &e.Event, &raw, &e.TxHash, &e.LogIndex, &e.BlockNumber, &e.BlockTime); err != nil {
+			return nil, err
+		}
+		e.Args = json.RawMessage(raw)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
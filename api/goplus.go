@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,20 +12,41 @@ import (
 
 	"github.com/artela-network/galxe-integration/api/biz"
 	"github.com/artela-network/galxe-integration/api/types"
+	"github.com/artela-network/galxe-integration/common"
 )
 
 type UrlInput struct {
 	AccountAddress string `form:"accountAddress" json:"accountAddress" binding:"required"`
 }
 
+// canonicalAddress writes a bad request if the address is invalid, and returns its checksum form otherwise
+func canonicalAddress(c *gin.Context, address string) (string, bool) {
+	canonical, err := common.CanonicalAddress(address)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid account address " + address,
+		})
+		return "", false
+	}
+	return canonical, true
+}
+
 func (s *Server) getTasks(c *gin.Context) {
 	accountAddress := c.Query("accountAddress")
+	if accountAddress != "" {
+		var ok bool
+		if accountAddress, ok = canonicalAddress(c, accountAddress); !ok {
+			return
+		}
+	}
 	id := c.Query("id")
 	intId, _ := strconv.ParseInt(id, 10, 64)
 	query := &biz.TaskQuery{
 		AccountAddress: accountAddress,
 		ID:             intId,
 		TaskTopic:      types.Task_Topic_Goplus,
+		Campaign:       c.Query("campaign"),
 	}
 	tasks, err := biz.GetAccountTaskInfo(s.db, query)
 	if err != nil {
@@ -61,7 +83,23 @@ func (s *Server) newTasks(c *gin.Context) {
 		}
 	}
 
-	tasks, getErr := biz.GetTasks(s.db, &biz.TaskQuery{AccountAddress: input.AccountAddress, TaskId: input.TaskId, TaskTopic: input.TaskTopic})
+	accountAddress, ok := canonicalAddress(c, input.AccountAddress)
+	if !ok {
+		return
+	}
+	input.AccountAddress = accountAddress
+
+	campaign, getErr := biz.GetCatalog().Campaign(input.Campaign)
+	if getErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   getErr.Error(),
+		})
+		return
+	}
+	input.Campaign = campaign.Name
+
+	tasks, getErr := biz.GetTasks(s.db, &biz.TaskQuery{AccountAddress: input.AccountAddress, TaskId: input.TaskId, TaskTopic: input.TaskTopic, Campaign: campaign.Name})
 	if getErr != nil {
 		log.Errorf("Failed to getTasks: %v", getErr)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -80,8 +118,23 @@ func (s *Server) newTasks(c *gin.Context) {
 		return
 	}
 
-	getErr = biz.InitTask(s.db, input)
+	inserted, getErr := biz.InitTask(s.db, input)
 	// insert db
+	if errors.Is(getErr, biz.ErrCampaignNotLive) || errors.Is(getErr, biz.ErrCampaignNotStarted) ||
+		errors.Is(getErr, biz.ErrCampaignEnded) || errors.Is(getErr, biz.ErrCampaignFull) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   getErr.Error(),
+		})
+		return
+	}
+	if errors.Is(getErr, biz.ErrSelfReferral) || errors.Is(getErr, biz.ErrUnknownReferrer) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   getErr.Error(),
+		})
+		return
+	}
 	if getErr != nil {
 		log.Errorf("Failed to query database: %v", getErr)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -90,6 +143,19 @@ func (s *Server) newTasks(c *gin.Context) {
 		})
 		return
 	}
+	if inserted == 0 {
+		// a concurrent request registered the address first
+		tasks, getErr = biz.GetTasks(s.db, &biz.TaskQuery{AccountAddress: input.AccountAddress, Campaign: campaign.Name})
+		if getErr != nil {
+			log.Errorf("Failed to getTasks: %v", getErr)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"error":   "Already have tasks",
+			"data":    biz.ConvertTaskInfo(tasks),
+		})
+		return
+	}
 
 	// 返回查询结果
 	c.JSON(http.StatusOK, gin.H{
@@ -110,6 +176,13 @@ func (s *Server) updateTask(c *gin.Context) {
 		})
 		return
 	}
+	if taskUpQuery.AccountAddress != nil {
+		accountAddress, ok := canonicalAddress(c, *taskUpQuery.AccountAddress)
+		if !ok {
+			return
+		}
+		taskUpQuery.AccountAddress = &accountAddress
+	}
 
 	err := biz.UpdateTask(s.db, &taskUpQuery)
 	if err != nil {
@@ -143,12 +216,17 @@ func (s *Server) syncStatus(c *gin.Context) {
 		})
 		return
 	}
+	accountAddress, ok := canonicalAddress(c, input.AccountAddress)
+	if !ok {
+		return
+	}
+	input.AccountAddress = accountAddress
 
 	err := biz.SyncStatus(s.db, input)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "Failed to sync status to goplus " + err.Error(),
+			"error":   "Failed to sync status to partners " + err.Error(),
 		})
 		return
 	}
@@ -158,9 +236,12 @@ func (s *Server) syncStatus(c *gin.Context) {
 }
 
 func (s *Server) isCompleted(c *gin.Context) {
-	accountAddress := c.Param("address")
+	accountAddress, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
 
-	compiled, err := biz.CheckAllTaskCompiled(s.db, accountAddress)
+	compiled, err := biz.CheckAllTaskCompiled(s.db, c.Query("campaign"), accountAddress)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -172,3 +253,57 @@ func (s *Server) isCompleted(c *gin.Context) {
 		"completed": compiled,
 	})
 }
+
+// taskHistory returns the status transitions of the tasks of an address or of a single task
+func (s *Server) taskHistory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Query("id"), 10, 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	history, err := biz.GetTaskHistory(s.db, &biz.TaskHistoryQuery{
+		AddressTaskID:  id,
+		AccountAddress: c.Query("accountAddress"),
+		Campaign:       c.Query("campaign"),
+		LimitNum:       limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to get task history " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    history,
+	})
+}
+
+// resetTask gives an abandoned task a fresh budget of attempts
+func (s *Server) resetTask(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid task id",
+		})
+		return
+	}
+	reset, err := biz.ResetTask(s.db, id, biz.ActorAdmin)
+	if err != nil {
+		log.Errorf("Failed to reset task %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to reset task",
+		})
+		return
+	}
+	if !reset {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "No abandoned task " + c.Param("id"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"math/big"
+	"net/http"
+
+	"github.com/artela-network/galxe-integration/indexer/nft"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// nftActivity returns whether an address minted or holds tokens of a collection recorded by the nft indexer,
+// only the tokens of token_id are counted if given.
+func (s *Server) nftActivity(c *gin.Context) {
+	collection, ethAddress := c.Param("collection"), c.Param("address")
+	if !common.IsHexAddress(collection) || !common.IsHexAddress(ethAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid collection or Ethereum address",
+		})
+		return
+	}
+	collection = common.HexToAddress(collection).Hex()
+	address := common.HexToAddress(ethAddress).Hex()
+
+	tokenID := ""
+	if raw := c.Query("token_id"); raw != "" {
+		id, ok := new(big.Int).SetString(raw, 0)
+		if !ok || id.Sign() < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid token id",
+			})
+			return
+		}
+		tokenID = id.String()
+	}
+
+	activity, err := nft.NewDAO(s.db).GetActivity(collection, address, tokenID)
+	if err != nil {
+		log.Errorf("Failed to query nft activity: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query nft activity",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"collection":  collection,
+			"address":     address,
+			"tokenId":     tokenID,
+			"minted":      activity.Minted.Sign() > 0,
+			"mintedCount": activity.Minted.String(),
+			"holds":       activity.Held.Sign() > 0,
+			"heldCount":   activity.Held.String(),
+		},
+	})
+}
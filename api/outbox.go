@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+// outbox lists the partner syncs of a status, the dead lettered ones by default
+func (s *Server) outbox(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	status := biz.OutboxStatus(c.DefaultQuery("status", string(biz.OutboxStatusDead)))
+	items, err := biz.GetOutbox(s.db, status, limit)
+	if err != nil {
+		log.Errorf("Failed to get outbox: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get outbox",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    items,
+	})
+}
+
+// retryOutbox requeues a dead lettered partner sync
+func (s *Server) retryOutbox(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid outbox id",
+		})
+		return
+	}
+	retried, err := biz.RetryOutbox(s.db, id)
+	if err != nil {
+		log.Errorf("Failed to retry outbox item %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retry outbox item",
+		})
+		return
+	}
+	if !retried {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "No dead lettered outbox item " + c.Param("id"),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
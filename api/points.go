@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+type pointsAdjustment struct {
+	Points int64  `json:"points" binding:"required"`
+	Memo   string `json:"memo"`
+}
+
+// catalogCampaign writes a bad request if the campaign is not in the catalog, and returns its name otherwise
+func catalogCampaign(c *gin.Context) (string, bool) {
+	campaign, err := biz.GetCatalog().Campaign(c.Param("campaign"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return "", false
+	}
+	return campaign.Name, true
+}
+
+// points returns the total points of an address and its rank in the campaign
+func (s *Server) points(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	balance, err := biz.GetPointsBalance(s.db, campaign, address)
+	if err != nil {
+		log.Errorf("Failed to get points of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get points",
+		})
+		return
+	}
+	if balance == nil {
+		balance = &biz.PointsBalance{Campaign: campaign, AccountAddress: address}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    balance,
+	})
+}
+
+// pointsHistory returns the ledger entries of an address, latest first
+func (s *Server) pointsHistory(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+	entries, err := biz.GetPointsHistory(s.db, campaign, address, limit, offset)
+	if err != nil {
+		log.Errorf("Failed to get points history of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get points history",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// leaderboard returns a page of the ranked addresses of the campaign
+func (s *Server) leaderboard(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "50"))
+	balances, more, err := biz.GetLeaderboard(s.db, campaign, page, size)
+	if err != nil {
+		log.Errorf("Failed to get leaderboard of %s: %v", campaign, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get leaderboard",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    balances,
+		"page":    page,
+		"more":    more,
+	})
+}
+
+// adjustPoints credits or debits the points of an address by hand
+func (s *Server) adjustPoints(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	input := &pointsAdjustment{}
+	if err := c.ShouldBindBodyWith(input, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid adjustment " + err.Error(),
+		})
+		return
+	}
+	entry, err := biz.AdjustPoints(s.db, campaign, address, input.Points, input.Memo, biz.ActorAdmin)
+	if err != nil {
+		log.Errorf("Failed to adjust points of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to adjust points",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
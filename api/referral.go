@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+// referralCode returns the code an address shares to refer others, creating it on the first call
+func (s *Server) referralCode(c *gin.Context) {
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	code, err := biz.GetReferralCode(s.db, address)
+	if err != nil {
+		log.Errorf("Failed to get referral code of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get referral code",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"accountAddress": address,
+			"code":           code,
+		},
+	})
+}
+
+// referralStats returns how many addresses an address referred to the campaign, and how many completed it
+func (s *Server) referralStats(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	stats, err := biz.GetReferralStats(s.db, campaign, address)
+	if err != nil {
+		log.Errorf("Failed to get referral stats of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get referral stats",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// referees returns the addresses an address referred to the campaign, latest first
+func (s *Server) referees(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	address, ok := canonicalAddress(c, c.Param("address"))
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+	referrals, err := biz.GetReferrals(s.db, campaign, address, limit, offset)
+	if err != nil {
+		log.Errorf("Failed to get referrals of %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get referrals",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    referrals,
+	})
+}
+
+// referralLeaderboard returns a page of the referrers of the campaign with the most qualified referrals
+func (s *Server) referralLeaderboard(c *gin.Context) {
+	campaign, ok := catalogCampaign(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "50"))
+	referrers, more, err := biz.GetReferralLeaderboard(s.db, campaign, page, size)
+	if err != nil {
+		log.Errorf("Failed to get referral leaderboard of %s: %v", campaign, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get referral leaderboard",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    referrers,
+		"page":    page,
+		"more":    more,
+	})
+}
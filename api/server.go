@@ -16,6 +16,7 @@ import (
 
 	"github.com/artela-network/galxe-integration/common"
 	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/goclient"
 )
 
 type Server struct {
@@ -28,6 +29,10 @@ type Server struct {
 
 	fetcher  common.Fetcher
 	indexers []common.Indexer
+
+	client *goclient.Client
+
+	credentials *credentialCache
 }
 
 func NewServer(ctx context.Context, config *config.Config, _ string, db *sql.DB, fetcher common.Fetcher, indexers []common.Indexer) *Server {
@@ -69,10 +74,37 @@ func NewServer(ctx context.Context, config *config.Config, _ string, db *sql.DB,
 		fetcher:  fetcher,
 		indexers: indexers,
 	}
+	credentialTTL, err := time.ParseDuration(config.APIServer.CredentialCacheTTL)
+	if err != nil {
+		credentialTTL = defaultCredentialCacheTTL
+	}
+	s.credentials = newCredentialCache(credentialTTL)
+	if config.Fetcher != nil {
+		client, err := goclient.NewClient(config.Fetcher.EthereumRPCUrl)
+		if err != nil {
+			log.Errorf("failed to connect to chain, tx lookups are disabled: %v", err)
+		}
+		s.client = client
+	}
 
 	apiGroup := r.Group("/api")
 	apiGroup.GET("/ping", s.ping)
 	apiGroup.GET("/jit-gaming/:address", s.completedJITGaming)
+	apiGroup.GET("/aspect/:address", s.aspectActivity)
+	apiGroup.GET("/nft/:collection/:address", s.nftActivity)
+	apiGroup.GET("/events", s.archivedEvents)
+	apiGroup.GET("/tx/:hash", s.txLookup)
+	apiGroup.GET("/health", s.health)
+	apiGroup.GET("/credential/:campaign/:task", s.credential)
+	apiGroup.GET("/airdrops/:name", s.airdrop)
+	apiGroup.GET("/airdrops/:name/proof/:address", s.airdropProof)
+	apiGroup.GET("/points/:campaign/:address", s.points)
+	apiGroup.GET("/points/:campaign/:address/history", s.pointsHistory)
+	apiGroup.GET("/leaderboard/:campaign", s.leaderboard)
+	apiGroup.GET("/leaderboard/:campaign/referrals", s.referralLeaderboard)
+	apiGroup.GET("/referral-code/:address", s.referralCode)
+	apiGroup.GET("/referrals/:campaign/:address", s.referralStats)
+	apiGroup.GET("/referrals/:campaign/:address/referees", s.referees)
 	// apiGroup.GET("/metrics", s.metrics)
 
 	plusGroup := r.Group("/api/goplus/")
@@ -81,6 +113,22 @@ func NewServer(ctx context.Context, config *config.Config, _ string, db *sql.DB,
 	plusGroup.POST("/update-task", s.updateTask)
 	plusGroup.POST("/sync", s.syncStatus)
 	plusGroup.GET("/status/:address", s.isCompleted)
+
+	apiGroup.GET("/campaigns", s.campaigns)
+	apiGroup.GET("/campaigns/:name", s.campaign)
+	adminGroup := r.Group("/api/admin", s.admin)
+	adminGroup.POST("/campaigns/:name", s.saveCampaign)
+	adminGroup.POST("/campaigns/:name/status", s.setCampaignStatus)
+	adminGroup.POST("/campaigns/:name/import", s.importAddresses)
+	adminGroup.GET("/campaigns/:name/export", s.exportTasks)
+	adminGroup.GET("/tasks/history", s.taskHistory)
+	adminGroup.POST("/tasks/:id/reset", s.resetTask)
+	adminGroup.GET("/outbox", s.outbox)
+	adminGroup.POST("/outbox/:id/retry", s.retryOutbox)
+	adminGroup.GET("/airdrops", s.airdrops)
+	adminGroup.POST("/airdrops", s.buildAirdrop)
+	adminGroup.POST("/airdrops/:name/publish", s.publishAirdrop)
+	adminGroup.POST("/points/:campaign/:address", s.adjustPoints)
 	return s
 }
 
@@ -103,6 +151,26 @@ func (s *Server) metrics(c *gin.Context) {
 	})
 }
 
+// health reports 503 if any of the indexers is not healthy, so it can be used as a readiness probe.
+func (s *Server) health(c *gin.Context) {
+	healthy := true
+	indexerHealth := make(map[string]*common.Health, len(s.indexers))
+	for _, indexer := range s.indexers {
+		health := indexer.Health()
+		indexerHealth[indexer.Name()] = health
+		healthy = healthy && health.Healthy
+	}
+
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{
+		"healthy": healthy,
+		"indexer": indexerHealth,
+	})
+}
+
 func (s *Server) completedJITGaming(c *gin.Context) {
 	ethAddress := c.Param("address")
 	if strings.HasPrefix(ethAddress, "/") {
@@ -112,7 +180,8 @@ func (s *Server) completedJITGaming(c *gin.Context) {
 		ethAddress = ethAddress[:len(ethAddress)-1]
 	}
 
-	if ethAddress == "" || len(ethAddress) != 42 {
+	ethAddress, err := common.CanonicalAddress(ethAddress)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Missing Ethereum address",
 		})
@@ -120,7 +189,7 @@ func (s *Server) completedJITGaming(c *gin.Context) {
 	}
 
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM scored_players WHERE LOWER(player) = LOWER($1))", ethAddress).Scan(&exists)
+	err = s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM scored_players WHERE LOWER(player) = LOWER($1))", ethAddress).Scan(&exists)
 	if err != nil {
 		log.Errorf("Failed to query database: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/goclient"
+)
+
+// txLookup returns a tx with its calldata, logs and revert reason decoded by the abis registered
+// for the contracts involved, anything that can not be decoded is returned raw.
+func (s *Server) txLookup(c *gin.Context) {
+	if s.client == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "No chain connection",
+		})
+		return
+	}
+
+	raw, err := hexutil.Decode(c.Param("hash"))
+	if err != nil || len(raw) != common.HashLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid tx hash",
+		})
+		return
+	}
+	hash := common.BytesToHash(raw)
+
+	tx, _, err := s.client.QueryTxByHash(c, hash)
+	var receipt *types.Receipt
+	if err == nil {
+		receipt, err = s.client.TransactionReceipt(c, hash)
+	}
+	if errors.Is(err, ethereum.NotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Tx not found",
+		})
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to query tx %s: %v", hash.Hex(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to query tx",
+		})
+		return
+	}
+
+	registry := goclient.GetABIRegistry()
+	data := gin.H{
+		"hash":        hash.Hex(),
+		"blockNumber": receipt.BlockNumber.Uint64(),
+		"success":     receipt.Status == types.ReceiptStatusSuccessful,
+		"input":       hexutil.Encode(tx.Data()),
+	}
+
+	abiName := ""
+	if tx.To() != nil {
+		data["to"] = tx.To().Hex()
+		abiName, _ = registry.ByContract(*tx.To())
+	}
+	if abiName != "" {
+		if call, err := registry.DecodeCalldata(abiName, tx.Data()); err == nil {
+			data["call"] = gin.H{"method": call.Method, "args": goclient.JSONArgs(call.Args)}
+		}
+	}
+
+	logs := make([]gin.H, 0, len(receipt.Logs))
+	for _, ethLog := range receipt.Logs {
+		decoded := gin.H{
+			"address":  ethLog.Address.Hex(),
+			"logIndex": ethLog.Index,
+			"topics":   ethLog.Topics,
+			"data":     hexutil.Encode(ethLog.Data),
+		}
+		if name, ok := registry.ByContract(ethLog.Address); ok {
+			if event, err := registry.DecodeLog(name, ethLog); err == nil {
+				decoded["event"] = event.Event
+				decoded["args"] = goclient.JSONArgs(event.Args)
+			}
+		}
+		logs = append(logs, decoded)
+	}
+	data["logs"] = logs
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		revertData, err := s.client.ReplayRevert(c, tx, receipt)
+		if err != nil {
+			log.Debugf("Failed to replay reverted tx %s: %v", hash.Hex(), err)
+		} else if len(revertData) > 0 {
+			data["revertData"] = hexutil.Encode(revertData)
+			if revert, err := registry.DecodeRevert(abiName, revertData); err == nil {
+				data["revert"] = gin.H{"error": revert.Error, "args": goclient.JSONArgs(revert.Args)}
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
@@ -8,6 +8,7 @@ var (
 	TaskStatusProcessing TaskStatus = "2" // manual handling
 	TaskStatusSuccess    TaskStatus = "3"
 	TaskStatusFail       TaskStatus = "4"
+	TaskStatusAbandoned  TaskStatus = "5" // out of attempts, only an admin retries it
 )
 
 const (
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+)
+
+// TaskTransition names a move of a task between statuses
+type TaskTransition string
+
+const (
+	// TransitionRegister creates the task when an address registers to a campaign
+	TransitionRegister TaskTransition = "register"
+	// TransitionSubmit is the front end reporting the task done, it is also how a failed task is retried
+	TransitionSubmit TaskTransition = "submit"
+	// TransitionLock is a handler picking the task up to check it on chain
+	TransitionLock TaskTransition = "lock"
+	// TransitionSucceed is a handler confirming the task
+	TransitionSucceed TaskTransition = "succeed"
+	// TransitionFail is a handler rejecting the task
+	TransitionFail TaskTransition = "fail"
+	// TransitionRelease hands a task locked for too long back to the handlers
+	TransitionRelease TaskTransition = "release"
+	// TransitionVerify completes the task from outside of the handlers, by an indexer or the partner sync
+	TransitionVerify TaskTransition = "verify"
+	// TransitionAbandon gives the task up once it failed or timed out on its last attempt
+	TransitionAbandon TaskTransition = "abandon"
+	// TransitionReset is an admin handing an abandoned task back to the handlers with fresh attempts
+	TransitionReset TaskTransition = "reset"
+	// TransitionRevert takes back a task verified by an indexer once the events which completed it were dropped by a reorg
+	TransitionRevert TaskTransition = "revert"
+)
+
+var taskStatusNames = map[TaskStatus]string{
+	TaskStatusNew:        "new",
+	TaskStatusPending:    "pending",
+	TaskStatusProcessing: "processing",
+	TaskStatusSuccess:    "success",
+	TaskStatusFail:       "fail",
+	TaskStatusAbandoned:  "abandoned",
+}
+
+var taskTransitions = map[TaskTransition]struct {
+	from []TaskStatus
+	to   TaskStatus
+}{
+	TransitionSubmit:  {from: []TaskStatus{TaskStatusNew, TaskStatusFail}, to: TaskStatusPending},
+	TransitionLock:    {from: []TaskStatus{TaskStatusPending}, to: TaskStatusProcessing},
+	TransitionSucceed: {from: []TaskStatus{TaskStatusPending, TaskStatusProcessing}, to: TaskStatusSuccess},
+	TransitionFail:    {from: []TaskStatus{TaskStatusProcessing}, to: TaskStatusFail},
+	TransitionRelease: {from: []TaskStatus{TaskStatusProcessing}, to: TaskStatusPending},
+	TransitionVerify: {from: []TaskStatus{TaskStatusNew, TaskStatusPending, TaskStatusProcessing, TaskStatusFail, TaskStatusAbandoned},
+		to: TaskStatusSuccess},
+	TransitionAbandon: {from: []TaskStatus{TaskStatusProcessing}, to: TaskStatusAbandoned},
+	TransitionReset:   {from: []TaskStatus{TaskStatusAbandoned}, to: TaskStatusPending},
+	TransitionRevert:  {from: []TaskStatus{TaskStatusSuccess}, to: TaskStatusNew},
+}
+
+// updateTransitions are the transitions a plain status update may take, in the order they are matched
+var updateTransitions = []TaskTransition{TransitionSubmit, TransitionLock, TransitionSucceed, TransitionFail}
+
+// ParseTaskStatus reads a status from its code or its name
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	if status := TaskStatus(s); status.Valid() {
+		return status, nil
+	}
+	for status, name := range taskStatusNames {
+		if name == s {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown task status %q", s)
+}
+
+// String returns the name of the status
+func (s TaskStatus) String() string {
+	if name, ok := taskStatusNames[s]; ok {
+		return name
+	}
+	return string(s)
+}
+
+// Valid reports whether the status is known
+func (s TaskStatus) Valid() bool {
+	_, ok := taskStatusNames[s]
+	return ok
+}
+
+// Apply returns the status a task in from moves to, or an error if the transition does not start from it
+func (t TaskTransition) Apply(from TaskStatus) (TaskStatus, error) {
+	transition, ok := taskTransitions[t]
+	if !ok {
+		return "", fmt.Errorf("unknown task transition %s", t)
+	}
+	for _, status := range transition.from {
+		if status == from {
+			return transition.to, nil
+		}
+	}
+	return "", fmt.Errorf("cannot %s a task in status %s", t, from)
+}
+
+// From returns the statuses the transition starts from
+func (t TaskTransition) From() []TaskStatus {
+	return taskTransitions[t].from
+}
+
+// To returns the status the transition ends in
+func (t TaskTransition) To() TaskStatus {
+	return taskTransitions[t].to
+}
+
+// UpdateTransition finds the transition a plain status update from one status to another takes
+func UpdateTransition(from, to TaskStatus) (TaskTransition, error) {
+	for _, transition := range updateTransitions {
+		if next, err := transition.Apply(from); err == nil && next == to {
+			return transition, nil
+		}
+	}
+	return "", fmt.Errorf("task cannot move from %s to %s", from, to)
+}
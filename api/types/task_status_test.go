@@ -0,0 +1,71 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTransitionApply(t *testing.T) {
+	tests := []struct {
+		transition TaskTransition
+		from       TaskStatus
+		to         TaskStatus
+		ok         bool
+	}{
+		{TransitionSubmit, TaskStatusNew, TaskStatusPending, true},
+		{TransitionSubmit, TaskStatusFail, TaskStatusPending, true},
+		{TransitionSubmit, TaskStatusSuccess, "", false},
+		{TransitionLock, TaskStatusPending, TaskStatusProcessing, true},
+		{TransitionLock, TaskStatusNew, "", false},
+		{TransitionSucceed, TaskStatusProcessing, TaskStatusSuccess, true},
+		{TransitionFail, TaskStatusPending, "", false},
+		{TransitionRelease, TaskStatusProcessing, TaskStatusPending, true},
+		{TransitionVerify, TaskStatusNew, TaskStatusSuccess, true},
+		{TransitionVerify, TaskStatusSuccess, "", false},
+		{TransitionVerify, TaskStatusAbandoned, TaskStatusSuccess, true},
+		{TransitionAbandon, TaskStatusProcessing, TaskStatusAbandoned, true},
+		{TransitionAbandon, TaskStatusFail, "", false},
+		{TransitionReset, TaskStatusAbandoned, TaskStatusPending, true},
+		{TransitionSubmit, TaskStatusAbandoned, "", false},
+		{TransitionRevert, TaskStatusSuccess, TaskStatusNew, true},
+		{TransitionRevert, TaskStatusPending, "", false},
+		{"unknown", TaskStatusNew, "", false},
+	}
+	for _, tt := range tests {
+		to, err := tt.transition.Apply(tt.from)
+		if !tt.ok {
+			require.Error(t, err, "%s from %s", tt.transition, tt.from)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.to, to)
+	}
+}
+
+func TestUpdateTransition(t *testing.T) {
+	transition, err := UpdateTransition(TaskStatusFail, TaskStatusPending)
+	require.NoError(t, err)
+	require.Equal(t, TransitionSubmit, transition)
+
+	transition, err = UpdateTransition(TaskStatusPending, TaskStatusSuccess)
+	require.NoError(t, err)
+	require.Equal(t, TransitionSucceed, transition)
+
+	// only the cleaner releases a locked task, and only indexers verify a new one
+	_, err = UpdateTransition(TaskStatusProcessing, TaskStatusPending)
+	require.Error(t, err)
+	_, err = UpdateTransition(TaskStatusNew, TaskStatusSuccess)
+	require.Error(t, err)
+
+	require.Equal(t, "processing", TaskStatusProcessing.String())
+	status, err := ParseTaskStatus("success")
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusSuccess, status)
+	status, err = ParseTaskStatus("4")
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusFail, status)
+	_, err = ParseTaskStatus("done")
+	require.Error(t, err)
+	require.False(t, TaskStatus("9").Valid())
+}
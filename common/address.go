@@ -0,0 +1,26 @@
+package common
+
+import (
+	"errors"
+	"strings"
+
+	eth "github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+// CanonicalAddress validates an evm address and returns its EIP-55 checksum form. Addresses in a single
+// case are accepted as is, while mixed case ones must carry a valid checksum.
+func CanonicalAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !eth.IsHexAddress(address) {
+		return "", ErrInvalidAddress
+	}
+
+	canonical := eth.HexToAddress(address).Hex()
+	hex := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && "0x"+hex != canonical {
+		return "", ErrInvalidAddress
+	}
+	return canonical, nil
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalAddress(t *testing.T) {
+	const checksum = "0x8997ec639d49D2F08EC0e6b858f36317680A6eE7"
+
+	tests := []struct {
+		input string
+		err   bool
+	}{
+		{input: checksum},
+		{input: "0x8997ec639d49d2f08ec0e6b858f36317680a6ee7"},
+		{input: "0x8997EC639D49D2F08EC0E6B858F36317680A6EE7"},
+		{input: " 0x8997ec639d49d2f08ec0e6b858f36317680a6ee7 "},
+		{input: "8997ec639d49d2f08ec0e6b858f36317680a6ee7"},
+		// a mixed case address with a broken checksum
+		{input: "0x8997ec639d49D2F08EC0e6b858f36317680A6eE8", err: true},
+		{input: "0x8997ec639d49d2f08ec0e6b858f36317680a6e", err: true},
+		{input: "", err: true},
+	}
+	for _, tt := range tests {
+		canonical, err := CanonicalAddress(tt.input)
+		if tt.err {
+			require.ErrorIs(t, err, ErrInvalidAddress, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		require.Equal(t, checksum, canonical)
+	}
+}
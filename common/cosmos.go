@@ -0,0 +1,45 @@
+package common
+
+import "time"
+
+// Actions of the cosmos messages decoded by the cosmos fetcher.
+const (
+	CosmosActionBankSend = "bank_send"
+	CosmosActionDelegate = "delegate"
+	CosmosActionVote     = "vote"
+)
+
+// CosmosTx is a native cosmos tx, with the messages the cosmos fetcher decoded and the events the tx emitted.
+type CosmosTx struct {
+	ChainID  string
+	Height   uint64
+	Time     time.Time
+	Hash     string
+	Index    uint32
+	Code     uint32
+	Log      string
+	Messages []*CosmosMsg
+	Events   []*CosmosEvent
+}
+
+// Succeeded reports whether the tx was executed successfully.
+func (t *CosmosTx) Succeeded() bool {
+	return t.Code == 0
+}
+
+// CosmosMsg is a message of a cosmos tx, Action is empty for the messages the fetcher does not decode.
+// Signer is the bech32 address of the account sending the message, Attributes holds the other fields
+// of the message, e.g. "to" and "amount" of bank sends.
+type CosmosMsg struct {
+	Index      int
+	TypeURL    string
+	Action     string
+	Signer     string
+	Attributes map[string]string
+}
+
+// CosmosEvent is an abci event emitted by a cosmos tx.
+type CosmosEvent struct {
+	Type       string
+	Attributes map[string]string
+}
@@ -1,5 +1,12 @@
 package common
 
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+)
+
 type Measurable interface {
 	Metrics() interface{}
 }
@@ -8,14 +15,65 @@ type Indexer interface {
 	Measurable
 	Input() chan<- *EventContext
 	Name() string
+	// Start launches the workers of the indexer, events sent before Start are buffered.
+	Start() error
+	// Stop waits for the buffered events to be processed and stops the workers.
+	Stop()
+	Health() *Health
+}
+
+// CosmosIndexer is implemented by the indexers of native cosmos txs. The cosmos fetcher only dispatches
+// txs to them, and the evm fetcher dispatches txs to the other indexers.
+type CosmosIndexer interface {
+	Indexer
+	// CosmosIndexer marks the indexer as an indexer of cosmos txs.
+	CosmosIndexer()
+}
+
+// Rewinder is an indexer which undoes what it recorded from blocks replaced by a reorg
+type Rewinder interface {
+	// Rewind drops what was recorded from the block number on, the blocks are processed again afterwards.
+	Rewind(blockNumber uint64) error
 }
 
 type Fetcher interface {
 	Measurable
 	RegisterIndexer(indexer Indexer)
 	Start()
+	// Stop stops fetching new blocks and waits for the blocks being dispatched to be processed.
+	Stop()
 }
 
 type Notifier interface {
 	Notify(msg, from string, throttle bool)
 }
+
+// ErrUnsupported is returned by the partners lacking an operation
+var ErrUnsupported = errors.New("operation not supported by the partner")
+
+// Completion is an address which completed a campaign, TaskId is the id the partner gave the address
+// task at registration.
+type Completion struct {
+	Campaign     string
+	Address      string
+	TaskId       string
+	CompleteTime time.Time
+}
+
+// Partner is a platform running a campaign with us, it is told about the addresses completing the campaign.
+type Partner interface {
+	Name() string
+	// ReportCompletion pushes the completion to the partner, reporting a completion twice must be harmless.
+	ReportCompletion(completion *Completion) error
+	// QueryStatus tells whether the partner has recorded the completion.
+	QueryStatus(completion *Completion) (bool, error)
+	// VerifyCallback authenticates a request the partner sent us, body is its read body.
+	VerifyCallback(req *http.Request, body []byte) error
+}
+
+// Executor is implemented by both *sql.DB and *sql.Tx, so DAO methods can run inside a caller's transaction.
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
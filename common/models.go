@@ -1,10 +1,55 @@
 package common
 
-import "github.com/ethereum/go-ethereum/core/types"
+import (
+	"math/big"
+	"time"
 
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventContext is a tx sent to an indexer. The evm fetcher sets BlockHeader, Transaction and Receipt,
+// while the cosmos fetcher sets Cosmos instead.
 type EventContext struct {
+	ChainID     *big.Int
 	BlockHeader *types.Header
 	Transaction *types.Transaction
 	Receipt     *types.Receipt
+	Cosmos      *CosmosTx
 	ResultChan  chan<- error
 }
+
+// TxHash returns the hash of the tx, whichever fetcher sent it.
+func (e *EventContext) TxHash() string {
+	if e.Cosmos != nil {
+		return e.Cosmos.Hash
+	}
+	return e.Transaction.Hash().Hex()
+}
+
+// BlockNumber returns the number of the block including the tx, whichever fetcher sent it.
+func (e *EventContext) BlockNumber() uint64 {
+	if e.Cosmos != nil {
+		return e.Cosmos.Height
+	}
+	return e.BlockHeader.Number.Uint64()
+}
+
+type HealthStatus string
+
+const (
+	HealthStatusCreated  HealthStatus = "created"
+	HealthStatusRunning  HealthStatus = "running"
+	HealthStatusStopping HealthStatus = "stopping"
+	HealthStatusStopped  HealthStatus = "stopped"
+)
+
+type Health struct {
+	Status          HealthStatus `json:"status"`
+	Healthy         bool         `json:"healthy"`
+	WaitingTx       int          `json:"waiting_tx"`
+	ProcessedTx     uint64       `json:"processed_tx"`
+	FailedTx        uint64       `json:"failed_tx"`
+	LastProcessedAt *time.Time   `json:"last_processed_at,omitempty"`
+	LastError       string       `json:"last_error,omitempty"`
+	LastErrorAt     *time.Time   `json:"last_error_at,omitempty"`
+}
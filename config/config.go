@@ -5,16 +5,68 @@ import (
 )
 
 type Config struct {
-	Notifiers []json.RawMessage `json:"notifiers"`
-	Indexers  []*IndexerConfig  `json:"indexers"`
-	APIServer *APIConfig        `json:"api_server"`
-	Fetcher   *FetcherConfig    `json:"fetcher"`
-	DB        *DBConfig         `json:"db"`
-	GoPlus    *GoPlusConfig     `json:"biz_goplus"`
-	Faucet    *FaucetConfig     `json:"faucet"`
-	Rug       *RugConfig        `json:"rug"`
-	Updater   *UpdaterConfig    `json:"updater"`
-	Recaptcha *RecaptchaConfig  `json:"recaptcha"`
+	Notifiers   []json.RawMessage  `json:"notifiers"`
+	Partners    []json.RawMessage  `json:"partners"`
+	Indexers    []*IndexerConfig   `json:"indexers"`
+	APIServer   *APIConfig         `json:"api_server"`
+	Fetcher     *FetcherConfig     `json:"fetcher"`
+	Cosmos      *CosmosConfig      `json:"cosmos_fetcher"`
+	DB          *DBConfig          `json:"db"`
+	GoPlus      *GoPlusConfig      `json:"biz_goplus"`
+	Faucet      *FaucetConfig      `json:"faucet"`
+	Rug         *RugConfig         `json:"rug"`
+	Updater     *UpdaterConfig     `json:"updater"`
+	Distributor *DistributorConfig `json:"distributor"`
+	Recaptcha   *RecaptchaConfig   `json:"recaptcha"`
+	ABI         *ABIConfig         `json:"abi"`
+	Campaigns   []*CampaignConfig  `json:"campaigns"`
+}
+
+// CampaignConfig declares a campaign of the task catalog, the first campaign configured is the default
+// one, which is used by the requests not naming a campaign. Partners names the partners the completions
+// are reported to, goplus if none.
+type CampaignConfig struct {
+	Name     string                `json:"name"`
+	Tasks    []*CampaignTaskConfig `json:"tasks"`
+	Partners []string              `json:"partners"`
+	// ReferralPoints are credited to the referrer once the address it referred completes the campaign
+	ReferralPoints int64 `json:"referral_points"`
+}
+
+// CampaignTaskConfig declares a task of a campaign. Required tasks must all succeed for the campaign to
+// be completed, Handler names the onchain handler or indexer which verifies the task, and the handler
+// only runs the task once the tasks of DependsOn succeed.
+type CampaignTaskConfig struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Order       int      `json:"order"`
+	Topic       string   `json:"topic"`
+	Required    bool     `json:"required"`
+	Handler     string   `json:"handler"`
+	DependsOn   []string `json:"depends_on"`
+	// Points are credited to the address once the task succeeds
+	Points int64 `json:"points"`
+	// Timeout is how long a handler may hold the task, such as "10m", and MaxAttempts how many times it
+	// is tried before it is abandoned
+	Timeout     string `json:"timeout"`
+	MaxAttempts int    `json:"max_attempts"`
+}
+
+// ABIConfig tells which abis are loaded into the abi registry. Dir is scanned for *.abi files named
+// by their file names, Files maps extra abi names to files, and Contracts maps contract addresses
+// to the abi names their calls, logs and reverts are decoded with.
+type ABIConfig struct {
+	Dir       string            `json:"dir"`
+	Files     map[string]string `json:"files"`
+	Contracts map[string]string `json:"contracts"`
+}
+
+func (c *ABIConfig) FillDefaults() *ABIConfig {
+	if c.Dir == "" {
+		c.Dir = "./contracts"
+	}
+	return c
 }
 
 // Updater get receipt and update status to db
@@ -76,6 +128,23 @@ func (c *RugConfig) FillDefaults() {
 	}
 }
 
+// DistributorConfig publishes the merkle roots of the airdrops to the distributor contract
+type DistributorConfig struct {
+	OnChain
+	TxConfig
+	ContractAddress string `json:"contract_address"`
+	// PublishTimeout is how long an airdrop may stay publishing, such as "10m", before it is failed
+	PublishTimeout string `json:"publish_timeout"`
+}
+
+func (c *DistributorConfig) FillDefaults() {
+	c.OnChain.FillDefaults()
+	c.TxConfig.FillDefaults()
+	if c.PublishTimeout == "" {
+		c.PublishTimeout = "10m"
+	}
+}
+
 type TxConfig struct {
 	GasLimit int64 `json:"gas_limit"`
 	GasPrice int64 `json:"gas_price"`
@@ -136,9 +205,26 @@ type DBConfig struct {
 }
 
 type IndexerConfig struct {
-	Type     string `json:"type"`
-	Thread   uint64 `json:"thread"`
-	Contract string `json:"contract"`
+	Type     string          `json:"type"`
+	Thread   uint64          `json:"thread"`
+	Contract string          `json:"contract"`
+	Options  json.RawMessage `json:"options"`
+}
+
+// EventFilter selects a contract event and tells which address the event is attributed to.
+// ABI is either the name of an abi in the abi registry or the path of an abi file.
+// AddressField is either the name of an event argument of type address, "tx.from" or "tx.to".
+type EventFilter struct {
+	Contract     string `json:"contract"`
+	ABI          string `json:"abi"`
+	Event        string `json:"event"`
+	AddressField string `json:"address_field"`
+}
+
+func (c *EventFilter) FillDefaults() {
+	if c.AddressField == "" {
+		c.AddressField = "tx.from"
+	}
 }
 
 type FetcherConfig struct {
@@ -150,6 +236,9 @@ type FetcherConfig struct {
 	PollThread        uint64 `json:"poll_thread"`
 	BlockMaxRetry     uint64 `json:"block_max_retry"`
 	MaxProcessingTime string `json:"max_processing_time"`
+	DrainTimeout      string `json:"drain_timeout"`
+	// ReorgDepth is the number of the last processed blocks checked against the canonical chain
+	ReorgDepth uint64 `json:"reorg_depth"`
 }
 
 func (c *FetcherConfig) FillDefaults() *FetcherConfig {
@@ -177,12 +266,42 @@ func (c *FetcherConfig) FillDefaults() *FetcherConfig {
 	if c.MaxProcessingTime == "" {
 		c.MaxProcessingTime = "5m"
 	}
+	if c.DrainTimeout == "" {
+		c.DrainTimeout = "30s"
+	}
+	if c.ReorgDepth == 0 {
+		c.ReorgDepth = 12
+	}
+	return c
+}
+
+// CosmosConfig configures the fetcher of native cosmos txs, the polling and retry options are the same
+// as the evm fetcher's, and its block status is kept in its own table.
+type CosmosConfig struct {
+	FetcherConfig
+	CometRPCUrl string `json:"comet_rpc_url"`
+	// StatusTable must exist in the schema, the migrations only create cosmos_block_status
+	StatusTable string `json:"status_table"`
+}
+
+func (c *CosmosConfig) FillDefaults() *CosmosConfig {
+	c.FetcherConfig.FillDefaults()
+	if c.CometRPCUrl == "" {
+		c.CometRPCUrl = "http://localhost:26657"
+	}
+	if c.StatusTable == "" {
+		c.StatusTable = "cosmos_block_status"
+	}
 	return c
 }
 
 type APIConfig struct {
 	Host string `json:"host"`
 	Port uint16 `json:"port"`
+	// AdminToken guards the admin routes as a bearer token, they are disabled if it is empty
+	AdminToken string `json:"admin_token"`
+	// CredentialCacheTTL is how long the answers of the credential routes are cached, 30s by default
+	CredentialCacheTTL string `json:"credential_cache_ttl"`
 }
 
 type TypeConf struct {
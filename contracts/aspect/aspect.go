@@ -0,0 +1,15 @@
+// Package aspect holds the ABI of the Artela Aspect system contract, which is a precompile
+// rather than a deployed contract, so there is no generated binding for it.
+package aspect
+
+import (
+	_ "embed"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SystemContractAddress is the address of the Artela Aspect system contract.
+var SystemContractAddress = common.HexToAddress("0x0000000000000000000000000000000000A27E14")
+
+//go:embed aspect.abi
+var ABI string
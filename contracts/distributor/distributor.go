@@ -0,0 +1,42 @@
+// Package distributor binds the merkle distributor contract the airdrops are claimed from. Its leaves are
+// the ones of the StandardMerkleTree of OpenZeppelin, abi.encode(account, amount) hashed twice.
+package distributor
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//go:embed distributor.abi
+var ABI string
+
+type Distributor struct {
+	contract *bind.BoundContract
+}
+
+func NewDistributor(address common.Address, backend bind.ContractBackend) (*Distributor, error) {
+	parsed, err := abi.JSON(strings.NewReader(ABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Distributor{contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+// MerkleRoot returns the root the claims are verified against
+func (d *Distributor) MerkleRoot(opts *bind.CallOpts) (common.Hash, error) {
+	var out []interface{}
+	if err := d.contract.Call(opts, &out, "merkleRoot"); err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(*abi.ConvertType(out[0], new([32]byte)).(*[32]byte)), nil
+}
+
+// SetMerkleRoot publishes a new root
+func (d *Distributor) SetMerkleRoot(opts *bind.TransactOpts, root common.Hash) (*types.Transaction, error) {
+	return d.contract.Transact(opts, "setMerkleRoot", [32]byte(root))
+}
@@ -0,0 +1,13 @@
+// Package nft holds the standard ABIs of ERC-721 and ERC-1155 collections, only the events
+// and views used by the indexers are included.
+package nft
+
+import (
+	_ "embed"
+)
+
+//go:embed erc721.abi
+var ERC721ABI string
+
+//go:embed erc1155.abi
+var ERC1155ABI string
@@ -0,0 +1,9 @@
+// Package scored holds the ABI of the game contract emitting the Scored events.
+package scored
+
+import (
+	_ "embed"
+)
+
+//go:embed scored.abi
+var ABI string
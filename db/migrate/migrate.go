@@ -0,0 +1,264 @@
+package migrate
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DialectPostgres = "postgres"
+	DialectSqlite   = "sqlite"
+)
+
+// ErrSchemaOutdated is returned by Check when the schema misses migrations of this build
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
+//go:embed migrations
+var migrationFS embed.FS
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change of the schema, Down reverts what Up applies
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Dialect returns the sql dialect of the db driver
+func Dialect(driver string) (string, error) {
+	switch driver {
+	case "postgres", "postgresql":
+		return DialectPostgres, nil
+	case "sqlite", "sqlite3":
+		return DialectSqlite, nil
+	}
+	return "", fmt.Errorf("no migrations for db driver %s", driver)
+}
+
+// Load reads the migrations of the dialect ordered by version
+func Load(dialect string) ([]*Migration, error) {
+	return load(migrationFS, path.Join("migrations", dialect))
+}
+
+func load(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down sql", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator applies and reverts the migrations, keeping the applied versions in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	dialect, err := Dialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{db: db, migrations: migrations}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+        version BIGINT PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    )`); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return m, nil
+}
+
+// Latest returns the version of the last migration of this build
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Applied returns the applied versions in order
+func (m *Migrator) Applied() ([]int64, error) {
+	rows, err := m.db.Query("SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []int64
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// Version returns the last applied version, 0 if nothing is applied
+func (m *Migrator) Version() (int64, error) {
+	applied, err := m.Applied()
+	if err != nil || len(applied) == 0 {
+		return 0, err
+	}
+	return applied[len(applied)-1], nil
+}
+
+// Pending returns the migrations not applied yet, up to the target version, or all if target is 0
+func (m *Migrator) Pending(target int64) ([]*Migration, error) {
+	applied, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+	return pending(m.migrations, applied, target), nil
+}
+
+func pending(migrations []*Migration, applied []int64, target int64) []*Migration {
+	done := make(map[int64]bool, len(applied))
+	for _, version := range applied {
+		done[version] = true
+	}
+
+	var todo []*Migration
+	for _, migration := range migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if !done[migration.Version] {
+			todo = append(todo, migration)
+		}
+	}
+	return todo
+}
+
+// Up applies the pending migrations up to the target version, or all of them if target is 0, each in its
+// own transaction. It returns the applied migrations.
+func (m *Migrator) Up(target int64) ([]*Migration, error) {
+	todo, err := m.Pending(target)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range todo {
+		log.Infof("migrate: applying %d_%s", migration.Version, migration.Name)
+		err := m.run(migration.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+		if err != nil {
+			return todo[:i], fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return todo, nil
+}
+
+// Down reverts the last applied migrations, steps of them, latest first. It returns the reverted migrations.
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	applied, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var reverted []*Migration
+	for i := len(applied) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration, ok := byVersion[applied[i]]
+		if !ok {
+			return reverted, fmt.Errorf("migration %d is unknown to this build", applied[i])
+		}
+		log.Infof("migrate: reverting %d_%s", migration.Version, migration.Name)
+		if err := m.run(migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+			return reverted, fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// run executes the sql of a migration and its bookkeeping in one transaction
+func (m *Migrator) run(migrationSql string, bookkeeping string, args ...interface{}) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migrationSql); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(bookkeeping, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Check refuses a schema missing any migration of this build
+func (m *Migrator) Check() error {
+	todo, err := m.Pending(0)
+	if err != nil {
+		return err
+	}
+	if len(todo) > 0 {
+		version, err := m.Version()
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: at version %d with %d pending migrations up to %d", ErrSchemaOutdated, version, len(todo), m.Latest())
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	postgres, err := Load(DialectPostgres)
+	require.NoError(t, err)
+	sqlite, err := Load(DialectSqlite)
+	require.NoError(t, err)
+
+	// both dialects must carry the same migrations
+	require.Equal(t, len(postgres), len(sqlite))
+	for i := range postgres {
+		require.Equal(t, int64(i+1), postgres[i].Version)
+		require.Equal(t, postgres[i].Version, sqlite[i].Version)
+		require.Equal(t, postgres[i].Name, sqlite[i].Name)
+	}
+	require.Equal(t, "address_tasks", postgres[0].Name)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := load(fstest.MapFS{
+		"m/0001_init.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER)")},
+	}, "m")
+	require.ErrorContains(t, err, "both up and down")
+
+	_, err = load(fstest.MapFS{
+		"m/init.sql": {Data: []byte("CREATE TABLE a (id INTEGER)")},
+	}, "m")
+	require.ErrorContains(t, err, "unexpected migration file")
+
+	_, err = load(fstest.MapFS{
+		"m/0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id INTEGER)")},
+		"m/0001_other.down.sql": {Data: []byte("DROP TABLE a")},
+	}, "m")
+	require.ErrorContains(t, err, "named both")
+}
+
+func TestPending(t *testing.T) {
+	migrations, err := load(fstest.MapFS{
+		"m/0001_a.up.sql":   {Data: []byte("up")},
+		"m/0001_a.down.sql": {Data: []byte("down")},
+		"m/0002_b.up.sql":   {Data: []byte("up")},
+		"m/0002_b.down.sql": {Data: []byte("down")},
+		"m/0010_c.up.sql":   {Data: []byte("up")},
+		"m/0010_c.down.sql": {Data: []byte("down")},
+	}, "m")
+	require.NoError(t, err)
+
+	versions := func(migrations []*Migration) []int64 {
+		var versions []int64
+		for _, migration := range migrations {
+			versions = append(versions, migration.Version)
+		}
+		return versions
+	}
+	require.Equal(t, []int64{1, 2, 10}, versions(pending(migrations, nil, 0)))
+	require.Equal(t, []int64{2, 10}, versions(pending(migrations, []int64{1}, 0)))
+	require.Equal(t, []int64{2}, versions(pending(migrations, []int64{1}, 9)))
+	require.Empty(t, pending(migrations, []int64{1, 2, 10}, 0))
+
+	_, err = Dialect("mysql")
+	require.Error(t, err)
+}
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS address_tasks (
+    id SERIAL PRIMARY KEY,
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    account_address VARCHAR(42) NOT NULL,
+    task_name VARCHAR(64) NOT NULL,
+    task_status VARCHAR(4) NOT NULL DEFAULT '0',
+    memo TEXT,
+    txs TEXT,
+    task_id VARCHAR(128),
+    task_topic VARCHAR(32),
+    job_batch_id VARCHAR(64)
+);
+
+CREATE INDEX IF NOT EXISTS address_tasks_account_address_index ON address_tasks (account_address);
+CREATE INDEX IF NOT EXISTS address_tasks_task_status_index ON address_tasks (task_name, task_status);
+CREATE INDEX IF NOT EXISTS address_tasks_job_batch_id_index ON address_tasks (job_batch_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cosmos_block_status;
+DROP TABLE IF EXISTS block_status;
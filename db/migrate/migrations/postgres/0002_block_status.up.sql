@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS block_status (
+    id SERIAL PRIMARY KEY,
+    block_number INTEGER NOT NULL UNIQUE,
+    status INTEGER NOT NULL,
+    retry_count INTEGER DEFAULT 0,
+    last_retry_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS status_index ON block_status (status);
+CREATE INDEX IF NOT EXISTS last_retry_at_index ON block_status (last_retry_at);
+
+CREATE TABLE IF NOT EXISTS cosmos_block_status (
+    id SERIAL PRIMARY KEY,
+    block_number INTEGER NOT NULL UNIQUE,
+    status INTEGER NOT NULL,
+    retry_count INTEGER DEFAULT 0,
+    last_retry_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS cosmos_block_status_status_index ON cosmos_block_status (status);
+CREATE INDEX IF NOT EXISTS cosmos_block_status_last_retry_at_index ON cosmos_block_status (last_retry_at);
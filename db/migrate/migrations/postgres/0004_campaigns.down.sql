@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS address_tasks_campaign_index;
+ALTER TABLE address_tasks DROP COLUMN IF EXISTS campaign;
+DROP TABLE IF EXISTS campaigns;
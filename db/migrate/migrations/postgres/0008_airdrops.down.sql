@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS airdrop_leaves;
+DROP TABLE IF EXISTS airdrops;
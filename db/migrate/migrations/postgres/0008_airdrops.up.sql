@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS airdrops (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    campaign VARCHAR(64) NOT NULL,
+    rewards TEXT NOT NULL,
+    snapshot_time TIMESTAMP NOT NULL,
+    merkle_root VARCHAR(66) NOT NULL,
+    total_amount NUMERIC(78, 0) NOT NULL,
+    leaves INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'built',
+    txs TEXT NOT NULL DEFAULT '',
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS airdrop_leaves (
+    airdrop_id BIGINT NOT NULL,
+    leaf_index INT NOT NULL,
+    account_address VARCHAR(42) NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+    proof TEXT NOT NULL,
+    PRIMARY KEY (airdrop_id, leaf_index)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS airdrop_leaves_address_index ON airdrop_leaves (airdrop_id, LOWER(account_address));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS points_balances;
+DROP TABLE IF EXISTS points_ledger;
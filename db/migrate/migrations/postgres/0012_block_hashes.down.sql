@@ -0,0 +1,2 @@
+ALTER TABLE cosmos_block_status DROP COLUMN IF EXISTS block_hash;
+ALTER TABLE block_status DROP COLUMN IF EXISTS block_hash;
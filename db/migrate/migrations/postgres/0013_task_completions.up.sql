@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS task_completions (
+    id BIGSERIAL PRIMARY KEY,
+    account_address VARCHAR(42) NOT NULL,
+    task_name VARCHAR(64) NOT NULL,
+    txs TEXT NOT NULL DEFAULT '',
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS task_completions_unique ON task_completions (LOWER(account_address), task_name, txs);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS native_actions;
+DROP TABLE IF EXISTS aspect_bindings;
+DROP TABLE IF EXISTS aspect_deployments;
+DROP TABLE IF EXISTS archived_events;
+DROP TABLE IF EXISTS nft_balances;
+DROP TABLE IF EXISTS nft_mints;
+DROP TABLE IF EXISTS aggregation_stats;
+DROP TABLE IF EXISTS quest_completions;
+DROP TABLE IF EXISTS quest_events;
+DROP TABLE IF EXISTS scored_players;
+DROP TABLE IF EXISTS processed_logs;
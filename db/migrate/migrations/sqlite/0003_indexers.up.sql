@@ -0,0 +1,136 @@
+CREATE TABLE IF NOT EXISTS processed_logs (
+    chain VARCHAR(32) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    indexer VARCHAR(64) NOT NULL,
+    block_number BIGINT NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chain, tx_hash, log_index, indexer)
+);
+
+CREATE TABLE IF NOT EXISTS scored_players (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    player VARCHAR(42) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS quest_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quest VARCHAR(64) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    step INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash VARCHAR(66) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_time BIGINT NOT NULL,
+    UNIQUE (quest, address, step, tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS quest_events_address_index ON quest_events (quest, address);
+
+CREATE TABLE IF NOT EXISTS quest_completions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quest VARCHAR(64) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    start_tx_hash VARCHAR(66) NOT NULL,
+    end_tx_hash VARCHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    UNIQUE (quest, address)
+);
+
+CREATE TABLE IF NOT EXISTS aggregation_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rule VARCHAR(64) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    total TEXT NOT NULL DEFAULT '0',
+    balance TEXT NOT NULL DEFAULT '0',
+    hold_since BIGINT,
+    completed_at BIGINT,
+    UNIQUE (rule, address)
+);
+
+CREATE INDEX IF NOT EXISTS aggregation_stats_hold_since_index ON aggregation_stats (rule, hold_since);
+
+CREATE TABLE IF NOT EXISTS nft_mints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection VARCHAR(42) NOT NULL,
+    token_id VARCHAR(78) NOT NULL,
+    minter VARCHAR(42) NOT NULL,
+    amount TEXT NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    UNIQUE (collection, token_id, tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS nft_mints_minter_index ON nft_mints (collection, minter);
+
+CREATE TABLE IF NOT EXISTS nft_balances (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection VARCHAR(42) NOT NULL,
+    token_id VARCHAR(78) NOT NULL,
+    owner VARCHAR(42) NOT NULL,
+    balance TEXT NOT NULL DEFAULT '0',
+    UNIQUE (collection, owner, token_id)
+);
+
+CREATE TABLE IF NOT EXISTS archived_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contract VARCHAR(42) NOT NULL,
+    event VARCHAR(128) NOT NULL,
+    args TEXT NOT NULL,
+    addresses TEXT NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash VARCHAR(66) NOT NULL,
+    block_time BIGINT NOT NULL,
+    UNIQUE (tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS archived_events_contract_index ON archived_events (contract, event, block_number);
+
+CREATE TABLE IF NOT EXISTS aspect_deployments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    aspect_id VARCHAR(42) NOT NULL UNIQUE,
+    deployer VARCHAR(42) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_time BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS aspect_deployments_deployer_index ON aspect_deployments (deployer);
+
+CREATE TABLE IF NOT EXISTS aspect_bindings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    aspect_id VARCHAR(42) NOT NULL,
+    contract VARCHAR(42) NOT NULL,
+    binder VARCHAR(42) NOT NULL,
+    version BIGINT NOT NULL,
+    priority SMALLINT NOT NULL,
+    bound BOOLEAN NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    UNIQUE (aspect_id, contract)
+);
+
+CREATE INDEX IF NOT EXISTS aspect_bindings_binder_index ON aspect_bindings (binder);
+
+CREATE TABLE IF NOT EXISTS native_actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_name VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    signer VARCHAR(128) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    msg_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    UNIQUE (task_name, tx_hash, msg_index)
+);
+
+CREATE INDEX IF NOT EXISTS native_actions_address_index ON native_actions (address);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    name VARCHAR(64) PRIMARY KEY,
+    start_time TIMESTAMP,
+    end_time TIMESTAMP,
+    max_participants BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL DEFAULT 'draft',
+    partner_channel VARCHAR(64) NOT NULL DEFAULT '',
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE address_tasks ADD COLUMN campaign VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS address_tasks_campaign_index ON address_tasks (campaign, account_address);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS task_status_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    address_task_id BIGINT NOT NULL,
+    account_address VARCHAR(42) NOT NULL,
+    task_name VARCHAR(64) NOT NULL,
+    campaign VARCHAR(64) NOT NULL DEFAULT '',
+    transition VARCHAR(16) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    from_status VARCHAR(4) NOT NULL,
+    to_status VARCHAR(4) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    txs TEXT NOT NULL DEFAULT '',
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS task_status_history_task_index ON task_status_history (address_task_id);
+CREATE INDEX IF NOT EXISTS task_status_history_address_index ON task_status_history (LOWER(account_address));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS address_tasks_lower_account_address_index;
+DROP INDEX IF EXISTS address_tasks_campaign_address_task_unique;
@@ -0,0 +1,12 @@
+-- keep a single task per campaign, address and task name, preferring the succeeded one and then the oldest
+DELETE FROM address_tasks WHERE id IN (
+    SELECT a.id FROM address_tasks a JOIN address_tasks b
+        ON a.campaign = b.campaign AND LOWER(a.account_address) = LOWER(b.account_address) AND a.task_name = b.task_name
+    WHERE a.id <> b.id AND (
+        (b.task_status = '3' AND a.task_status <> '3') OR
+        ((b.task_status = '3') = (a.task_status = '3') AND b.id < a.id)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS address_tasks_campaign_address_task_unique ON address_tasks (campaign, LOWER(account_address), task_name);
+CREATE INDEX IF NOT EXISTS address_tasks_lower_account_address_index ON address_tasks (LOWER(account_address));
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS partner_sync_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    partner VARCHAR(64) NOT NULL,
+    campaign VARCHAR(64) NOT NULL,
+    account_address VARCHAR(42) NOT NULL,
+    task_id VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS partner_sync_outbox_unique ON partner_sync_outbox (partner, campaign, LOWER(account_address));
+CREATE INDEX IF NOT EXISTS partner_sync_outbox_due_index ON partner_sync_outbox (status, next_attempt_at);
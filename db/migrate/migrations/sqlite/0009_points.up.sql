@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS points_ledger (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign VARCHAR(64) NOT NULL,
+    account_address VARCHAR(42) NOT NULL,
+    points BIGINT NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    address_task_id BIGINT,
+    task_name VARCHAR(64) NOT NULL DEFAULT '',
+    actor VARCHAR(64) NOT NULL,
+    memo TEXT NOT NULL DEFAULT '',
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS points_ledger_task_unique ON points_ledger (address_task_id) WHERE kind = 'task';
+CREATE INDEX IF NOT EXISTS points_ledger_address_index ON points_ledger (campaign, LOWER(account_address), id);
+
+CREATE TABLE IF NOT EXISTS points_balances (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign VARCHAR(64) NOT NULL,
+    account_address VARCHAR(42) NOT NULL,
+    points BIGINT NOT NULL DEFAULT 0,
+    rank BIGINT,
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS points_balances_unique ON points_balances (campaign, LOWER(account_address));
+CREATE INDEX IF NOT EXISTS points_balances_rank_index ON points_balances (campaign, rank, id);
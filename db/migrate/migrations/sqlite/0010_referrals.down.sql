@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS referrers;
+DROP TABLE IF EXISTS referrals;
+DROP TABLE IF EXISTS referral_codes;
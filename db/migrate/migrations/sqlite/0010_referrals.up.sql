@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS referral_codes (
+    code VARCHAR(16) PRIMARY KEY,
+    account_address VARCHAR(42) NOT NULL,
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS referral_codes_address_unique ON referral_codes (LOWER(account_address));
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign VARCHAR(64) NOT NULL,
+    referrer_address VARCHAR(42) NOT NULL,
+    referee_address VARCHAR(42) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    qualified_at TIMESTAMP,
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS referrals_referee_unique ON referrals (campaign, LOWER(referee_address));
+CREATE INDEX IF NOT EXISTS referrals_referrer_index ON referrals (campaign, LOWER(referrer_address), id);
+
+CREATE TABLE IF NOT EXISTS referrers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    campaign VARCHAR(64) NOT NULL,
+    account_address VARCHAR(42) NOT NULL,
+    invited BIGINT NOT NULL DEFAULT 0,
+    qualified BIGINT NOT NULL DEFAULT 0,
+    gmt_create TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    gmt_modify TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS referrers_unique ON referrers (campaign, LOWER(account_address));
+CREATE INDEX IF NOT EXISTS referrers_qualified_index ON referrers (campaign, qualified DESC, id);
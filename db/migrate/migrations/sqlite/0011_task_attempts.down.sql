@@ -0,0 +1,4 @@
+UPDATE address_tasks SET task_status = '4' WHERE task_status = '5';
+ALTER TABLE address_tasks DROP COLUMN fail_reason;
+ALTER TABLE address_tasks DROP COLUMN fail_code;
+ALTER TABLE address_tasks DROP COLUMN attempts;
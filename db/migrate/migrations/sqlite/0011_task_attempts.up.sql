@@ -0,0 +1,3 @@
+ALTER TABLE address_tasks ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE address_tasks ADD COLUMN fail_code VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE address_tasks ADD COLUMN fail_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE block_status ADD COLUMN block_hash VARCHAR(66);
+ALTER TABLE cosmos_block_status ADD COLUMN block_hash VARCHAR(66);
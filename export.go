@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+// runExport runs the export command, it writes the tasks of a campaign to a file or stdout and prints the
+// row count and checksum of the export to stderr
+func runExport(conn *sql.DB, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	task := flags.String("task", "", "only export this task")
+	status := flags.String("status", "success", "status of the exported tasks")
+	from := flags.String("from", "", "only export the tasks changed since this RFC3339 time")
+	to := flags.String("to", "", "only export the tasks changed before this RFC3339 time")
+	format := flags.String("format", biz.ExportFormatCSV, "csv, ndjson or galxe")
+	out := flags.String("out", "", "file to write, stdout if empty")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: export [flags] <campaign>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	query, err := biz.ParseExportQuery(flags.Arg(0), *task, *status, *from, *to)
+	if err != nil {
+		log.Fatalf("invalid export: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("failed to create export file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	exportWriter, err := biz.NewExportWriter(*format, w)
+	if err != nil {
+		log.Fatalf("invalid export: %v", err)
+	}
+	if err := biz.ExportTasks(conn, query, exportWriter); err != nil {
+		log.Fatalf("export failed: %v", err)
+	}
+	summary, err := exportWriter.Close()
+	if err != nil {
+		log.Fatalf("export failed: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "rows %d sha256 %s\n", summary.Rows, summary.Checksum)
+}
@@ -0,0 +1,143 @@
+package cosmos
+
+import (
+	"fmt"
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/cosmos/gogoproto/proto"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// msgDecoder unpacks a message into its signer and attributes
+type msgDecoder struct {
+	action string
+	decode func(value []byte) (signer string, attributes map[string]string, err error)
+}
+
+var msgDecoders = map[string]*msgDecoder{
+	sdk.MsgTypeURL(&banktypes.MsgSend{}): {
+		action: common.CosmosActionBankSend,
+		decode: func(value []byte) (string, map[string]string, error) {
+			msg := &banktypes.MsgSend{}
+			if err := proto.Unmarshal(value, msg); err != nil {
+				return "", nil, err
+			}
+			return msg.FromAddress, map[string]string{
+				"to":     msg.ToAddress,
+				"amount": msg.Amount.String(),
+			}, nil
+		},
+	},
+	sdk.MsgTypeURL(&stakingtypes.MsgDelegate{}): {
+		action: common.CosmosActionDelegate,
+		decode: func(value []byte) (string, map[string]string, error) {
+			msg := &stakingtypes.MsgDelegate{}
+			if err := proto.Unmarshal(value, msg); err != nil {
+				return "", nil, err
+			}
+			return msg.DelegatorAddress, map[string]string{
+				"validator": msg.ValidatorAddress,
+				"amount":    msg.Amount.String(),
+			}, nil
+		},
+	},
+	sdk.MsgTypeURL(&govv1.MsgVote{}): {
+		action: common.CosmosActionVote,
+		decode: func(value []byte) (string, map[string]string, error) {
+			msg := &govv1.MsgVote{}
+			if err := proto.Unmarshal(value, msg); err != nil {
+				return "", nil, err
+			}
+			return msg.Voter, map[string]string{
+				"proposal_id": strconv.FormatUint(msg.ProposalId, 10),
+				"option":      msg.Option.String(),
+			}, nil
+		},
+	},
+	sdk.MsgTypeURL(&govv1beta1.MsgVote{}): {
+		action: common.CosmosActionVote,
+		decode: func(value []byte) (string, map[string]string, error) {
+			msg := &govv1beta1.MsgVote{}
+			if err := proto.Unmarshal(value, msg); err != nil {
+				return "", nil, err
+			}
+			return msg.Voter, map[string]string{
+				"proposal_id": strconv.FormatUint(msg.ProposalId, 10),
+				// v1beta1 options share the names of the v1 ones
+				"option": govv1.VoteOption(msg.Option).String(),
+			}, nil
+		},
+	},
+}
+
+// decodeTx decodes the messages of a raw tx, and pairs it with its execution result.
+// Txs which are not cosmos sdk txs, or carry no message known by the decoder, return nil.
+func decodeTx(chainID string, block *cmttypes.Block, index int, result *abci.ExecTxResult) (*common.CosmosTx, error) {
+	rawTx := block.Data.Txs[index]
+	raw := &tx.TxRaw{}
+	if err := proto.Unmarshal(rawTx, raw); err != nil {
+		return nil, nil
+	}
+	body := &tx.TxBody{}
+	if err := proto.Unmarshal(raw.BodyBytes, body); err != nil {
+		return nil, nil
+	}
+
+	var messages []*common.CosmosMsg
+	for i, msgAny := range body.Messages {
+		decoder, ok := msgDecoders[msgAny.TypeUrl]
+		if !ok {
+			continue
+		}
+		signer, attributes, err := decoder.decode(msgAny.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s message: %w", msgAny.TypeUrl, err)
+		}
+		messages = append(messages, &common.CosmosMsg{
+			Index:      i,
+			TypeURL:    msgAny.TypeUrl,
+			Action:     decoder.action,
+			Signer:     signer,
+			Attributes: attributes,
+		})
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	cosmosTx := &common.CosmosTx{
+		ChainID:  chainID,
+		Height:   uint64(block.Height),
+		Time:     block.Time,
+		Hash:     fmt.Sprintf("%X", rawTx.Hash()),
+		Index:    uint32(index),
+		Messages: messages,
+	}
+	if result != nil {
+		cosmosTx.Code = result.Code
+		cosmosTx.Log = result.Log
+		cosmosTx.Events = decodeEvents(result.Events)
+	}
+	return cosmosTx, nil
+}
+
+func decodeEvents(events []abci.Event) []*common.CosmosEvent {
+	decoded := make([]*common.CosmosEvent, 0, len(events))
+	for _, event := range events {
+		attributes := make(map[string]string, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			attributes[attr.Key] = attr.Value
+		}
+		decoded = append(decoded, &common.CosmosEvent{Type: event.Type, Attributes: attributes})
+	}
+	return decoded
+}
@@ -0,0 +1,143 @@
+package cosmos
+
+import (
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+const (
+	sender    = "art1qyqszqgpqyqszqgpqyqszqgpqyqszqgp8apuk5"
+	receiver  = "art1qgpqyqszqgpqyqszqgpqyqszqgpqyqszmhyzvp"
+	validator = "artvaloper1qyqszqgpqyqszqgpqyqszqgpqyqszqgpxlghk3"
+)
+
+// encodeTx encodes the messages into a raw sdk tx
+func encodeTx(t *testing.T, msgs ...proto.Message) cmttypes.Tx {
+	t.Helper()
+
+	body := &tx.TxBody{}
+	for _, msg := range msgs {
+		msgAny, err := codectypes.NewAnyWithValue(msg)
+		require.NoError(t, err)
+		body.Messages = append(body.Messages, msgAny)
+	}
+	bodyBytes, err := proto.Marshal(body)
+	require.NoError(t, err)
+	raw, err := proto.Marshal(&tx.TxRaw{BodyBytes: bodyBytes})
+	require.NoError(t, err)
+	return raw
+}
+
+func TestDecodeTx(t *testing.T) {
+	uart := func(amount int64) sdk.Coin { return sdk.NewCoin("uart", sdkmath.NewInt(amount)) }
+
+	tests := []struct {
+		name     string
+		tx       func(t *testing.T) cmttypes.Tx
+		messages []*common.CosmosMsg
+	}{
+		{
+			name: "bank send",
+			tx: func(t *testing.T) cmttypes.Tx {
+				return encodeTx(t, &banktypes.MsgSend{FromAddress: sender, ToAddress: receiver, Amount: sdk.NewCoins(uart(100))})
+			},
+			messages: []*common.CosmosMsg{{
+				TypeURL:    "/cosmos.bank.v1beta1.MsgSend",
+				Action:     common.CosmosActionBankSend,
+				Signer:     sender,
+				Attributes: map[string]string{"to": receiver, "amount": "100uart"},
+			}},
+		},
+		{
+			name: "delegate and vote",
+			tx: func(t *testing.T) cmttypes.Tx {
+				return encodeTx(t,
+					&stakingtypes.MsgDelegate{DelegatorAddress: sender, ValidatorAddress: validator, Amount: uart(5)},
+					&govv1beta1.MsgVote{ProposalId: 3, Voter: sender, Option: govv1beta1.OptionNo},
+					&govv1.MsgVote{ProposalId: 4, Voter: sender, Option: govv1.OptionYes},
+				)
+			},
+			messages: []*common.CosmosMsg{
+				{
+					TypeURL:    "/cosmos.staking.v1beta1.MsgDelegate",
+					Action:     common.CosmosActionDelegate,
+					Signer:     sender,
+					Attributes: map[string]string{"validator": validator, "amount": "5uart"},
+				},
+				{
+					Index:      1,
+					TypeURL:    "/cosmos.gov.v1beta1.MsgVote",
+					Action:     common.CosmosActionVote,
+					Signer:     sender,
+					Attributes: map[string]string{"proposal_id": "3", "option": "VOTE_OPTION_NO"},
+				},
+				{
+					Index:      2,
+					TypeURL:    "/cosmos.gov.v1.MsgVote",
+					Action:     common.CosmosActionVote,
+					Signer:     sender,
+					Attributes: map[string]string{"proposal_id": "4", "option": "VOTE_OPTION_YES"},
+				},
+			},
+		},
+		{
+			name: "unknown messages",
+			tx: func(t *testing.T) cmttypes.Tx {
+				return encodeTx(t, &banktypes.MsgMultiSend{})
+			},
+		},
+		{
+			name: "not a cosmos tx",
+			tx: func(t *testing.T) cmttypes.Tx {
+				return cmttypes.Tx("not a tx")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTx := tt.tx(t)
+			block := &cmttypes.Block{
+				Header: cmttypes.Header{Height: 10, Time: time.Unix(1700000000, 0)},
+				Data:   cmttypes.Data{Txs: cmttypes.Txs{rawTx}},
+			}
+			result := &abci.ExecTxResult{Events: []abci.Event{{
+				Type:       "transfer",
+				Attributes: []abci.EventAttribute{{Key: "amount", Value: "100uart"}},
+			}}}
+
+			cosmosTx, err := decodeTx("artela_11822-1", block, 0, result)
+			require.NoError(t, err)
+			if tt.messages == nil {
+				require.Nil(t, cosmosTx)
+				return
+			}
+			require.Equal(t, tt.messages, cosmosTx.Messages)
+			require.Equal(t, uint64(10), cosmosTx.Height)
+			require.Equal(t, "artela_11822-1", cosmosTx.ChainID)
+			require.Len(t, cosmosTx.Hash, 64)
+			require.True(t, cosmosTx.Succeeded())
+			require.Equal(t, []*common.CosmosEvent{{Type: "transfer", Attributes: map[string]string{"amount": "100uart"}}}, cosmosTx.Events)
+		})
+	}
+}
+
+func TestEVMChainID(t *testing.T) {
+	require.Equal(t, int64(11822), evmChainID("artela_11822-1").Int64())
+	require.Nil(t, evmChainID("cosmoshub-4"))
+}
@@ -0,0 +1,429 @@
+// Package cosmos implements the fetcher of native cosmos txs. It follows the blocks and block results
+// of the CometBFT rpc, decodes the sdk messages and events of the txs, and sends them to the cosmos indexers.
+package cosmos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sync"
+	"time"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/fetcher"
+)
+
+// evmChainIDPattern matches ethermint chain ids like artela_11822-1, which embed the evm chain id
+var evmChainIDPattern = regexp.MustCompile(`^[a-z]+_(\d+)-\d+$`)
+
+// rpcClient is the part of the CometBFT rpc client used by the fetcher.
+type rpcClient interface {
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+	Block(ctx context.Context, height *int64) (*coretypes.ResultBlock, error)
+	BlockResults(ctx context.Context, height *int64) (*coretypes.ResultBlockResults, error)
+}
+
+type block struct {
+	block   *cmttypes.Block
+	results *coretypes.ResultBlockResults
+}
+
+type cosmosFetcher struct {
+	client              rpcClient
+	chainID             string
+	evmChainID          *big.Int
+	blockCache          chan *block
+	blockFetchTaskCache chan uint64
+	dao                 fetcher.DAO
+	pullInterval        time.Duration
+	retryInterval       time.Duration
+	pollThread          uint64
+	blockMaxRetry       uint64
+	beginBlock          uint64
+	maxProcessingTime   time.Duration
+	drainTimeout        time.Duration
+
+	// ctx stops fetching new blocks, processCtx aborts the blocks being dispatched
+	ctx           context.Context
+	cancel        context.CancelFunc
+	processCtx    context.Context
+	processCancel context.CancelFunc
+	dispatchers   sync.WaitGroup
+
+	indexers []common.Indexer
+}
+
+func NewFetcher(ctx context.Context, conf *config.CosmosConfig, driver string, db *sql.DB) (common.Fetcher, error) {
+	conf.FillDefaults()
+
+	client, err := rpchttp.New(conf.CometRPCUrl, "/websocket")
+	if err != nil {
+		log.Error("failed to dial comet rpc", err)
+		return nil, err
+	}
+
+	dao := fetcher.GetRegistry().GetDAOForTable(ctx, driver, db, conf.StatusTable)
+	if dao == nil {
+		return nil, fmt.Errorf("no fetcher dao for driver %s", driver)
+	}
+	return newFetcher(ctx, conf, client, dao)
+}
+
+func newFetcher(ctx context.Context, conf *config.CosmosConfig, client rpcClient, dao fetcher.DAO) (*cosmosFetcher, error) {
+	status, err := client.Status(ctx)
+	if err != nil {
+		log.Error("failed to get comet node status", err)
+		return nil, err
+	}
+
+	maxProcessingTime, err := time.ParseDuration(conf.MaxProcessingTime)
+	if err != nil {
+		log.Error("failed to parse max processing time", err)
+		return nil, err
+	}
+	drainTimeout, err := time.ParseDuration(conf.DrainTimeout)
+	if err != nil {
+		log.Error("failed to parse drain timeout", err)
+		return nil, err
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	processCtx, processCancel := context.WithCancel(ctx)
+	return &cosmosFetcher{
+		ctx:                 fetchCtx,
+		cancel:              cancel,
+		processCtx:          processCtx,
+		processCancel:       processCancel,
+		client:              client,
+		chainID:             status.NodeInfo.Network,
+		evmChainID:          evmChainID(status.NodeInfo.Network),
+		blockCache:          make(chan *block, conf.BlockCacheSize),
+		blockFetchTaskCache: make(chan uint64, conf.BlockCacheSize),
+		dao:                 dao,
+		pullInterval:        time.Duration(conf.PullIntervalMs) * time.Millisecond,
+		retryInterval:       time.Duration(conf.RetryIntervalMs) * time.Millisecond,
+		pollThread:          conf.PollThread,
+		blockMaxRetry:       conf.BlockMaxRetry,
+		beginBlock:          conf.BeginBlock,
+		maxProcessingTime:   maxProcessingTime,
+		drainTimeout:        drainTimeout,
+	}, nil
+}
+
+// evmChainID returns the evm chain id embedded in the cosmos chain id, so the txs of both fetchers
+// are recorded under the same chain in the processed logs ledger, or nil if there is none.
+func evmChainID(chainID string) *big.Int {
+	matches := evmChainIDPattern.FindStringSubmatch(chainID)
+	if matches == nil {
+		return nil
+	}
+	id, _ := new(big.Int).SetString(matches[1], 10)
+	return id
+}
+
+// RegisterIndexer only registers cosmos indexers, the others process evm txs.
+func (f *cosmosFetcher) RegisterIndexer(indexer common.Indexer) {
+	if _, ok := indexer.(common.CosmosIndexer); !ok {
+		log.Debugf("[cosmos fetcher] %s indexer does not process cosmos txs, skip", indexer.Name())
+		return
+	}
+	f.indexers = append(f.indexers, indexer)
+}
+
+func (f *cosmosFetcher) Start() {
+	for i := uint64(0); i < f.pollThread; i++ {
+		go f.createWorker(i)
+	}
+
+	go f.createBlockListener()
+
+	for i := uint64(0); i < f.pollThread; i++ {
+		f.dispatchers.Add(1)
+		go f.createEventDispatcher()
+	}
+
+	go f.monitorStaleProcessingTasks()
+}
+
+// Stop stops fetching and dispatching new blocks, the blocks being dispatched are given drainTimeout to finish.
+func (f *cosmosFetcher) Stop() {
+	log.Info("[cosmos fetcher] stopping, draining in-flight blocks...")
+	f.cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		f.dispatchers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Info("[cosmos fetcher] all in-flight blocks drained")
+	case <-time.After(f.drainTimeout):
+		log.Warnf("[cosmos fetcher] in-flight blocks not drained in %s, aborting", f.drainTimeout)
+		f.processCancel()
+		<-drained
+	}
+	f.processCancel()
+}
+
+func (f *cosmosFetcher) createBlockListener() {
+	ticker := time.NewTicker(f.pullInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			log.Info("[cosmos block listener]: stopped")
+			return
+		case <-ticker.C:
+			if err := f.poll(); err != nil {
+				log.Error("[cosmos block listener]: ", err)
+				time.Sleep(f.retryInterval)
+			}
+		}
+	}
+}
+
+// poll adds the new blocks to the status table, and submits the unprocessed and retry blocks to the workers
+func (f *cosmosFetcher) poll() error {
+	status, err := f.client.Status(f.ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching node status: %w", err)
+	}
+
+	lastProcessedBlock, err := f.dao.GetLatestProcessedBlock()
+	if err != nil {
+		return fmt.Errorf("failed to load latest processed block: %w", err)
+	}
+	lastProcessedBlock = max(lastProcessedBlock, f.beginBlock-1)
+
+	currentBlock := uint64(status.SyncInfo.LatestBlockHeight)
+	fetchTargetBlock := min(currentBlock, lastProcessedBlock+uint64(cap(f.blockCache)))
+	for i := lastProcessedBlock + 1; i <= fetchTargetBlock; i++ {
+		if err := f.dao.AddBlock(i, fetcher.StatusUnprocessed); err != nil {
+			return fmt.Errorf("failed to add block task: %w", err)
+		}
+	}
+
+	unprocessedBlocks, err := f.dao.GetUnprocessedBlocks()
+	if err != nil {
+		return fmt.Errorf("failed to load unprocessed blocks: %w", err)
+	}
+	retryBlocks, err := f.dao.GetRetryBlocks(f.blockMaxRetry, f.retryInterval)
+	if err != nil {
+		return fmt.Errorf("failed to load retry blocks: %w", err)
+	}
+	for _, height := range append(unprocessedBlocks, retryBlocks...) {
+		select {
+		case <-f.ctx.Done():
+			return nil
+		case f.blockFetchTaskCache <- height:
+			log.Debugf("[cosmos block listener]: submitted block task %d", height)
+		}
+	}
+	return nil
+}
+
+func (f *cosmosFetcher) createWorker(index uint64) {
+	for {
+		select {
+		case <-f.ctx.Done():
+			log.Infof("[cosmos fetcher worker%d]: stopped", index)
+			return
+		case height := <-f.blockFetchTaskCache:
+			status, err := f.dao.GetBlockStatus(height)
+			if err != nil {
+				log.Errorf("[cosmos fetcher worker%d]: failed to load block status: %v", index, err)
+				continue
+			}
+			if status != fetcher.StatusUnprocessed && status != fetcher.StatusRetry {
+				continue
+			}
+
+			fetched, err := f.fetchBlock(height)
+			if err != nil {
+				log.Errorf("[cosmos fetcher worker%d]: error fetching block %d: %v", index, height, err)
+				continue
+			}
+
+			select {
+			case <-f.ctx.Done():
+				log.Infof("[cosmos fetcher worker%d]: stopped", index)
+				return
+			case f.blockCache <- fetched:
+				log.Debugf("[cosmos fetcher worker%d]: fetched block %d", index, height)
+			}
+		}
+	}
+}
+
+func (f *cosmosFetcher) fetchBlock(height uint64) (*block, error) {
+	h := int64(height)
+	resultBlock, err := f.client.Block(f.ctx, &h)
+	if err != nil {
+		return nil, err
+	}
+	results, err := f.client.BlockResults(f.ctx, &h)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.TxsResults) != len(resultBlock.Block.Data.Txs) {
+		return nil, fmt.Errorf("block has %d txs but %d results", len(resultBlock.Block.Data.Txs), len(results.TxsResults))
+	}
+	return &block{block: resultBlock.Block, results: results}, nil
+}
+
+func (f *cosmosFetcher) createEventDispatcher() {
+	defer f.dispatchers.Done()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			log.Info("[cosmos event dispatcher]: stopped")
+			return
+		case b := <-f.blockCache:
+			f.dispatchBlock(b)
+		}
+	}
+}
+
+func (f *cosmosFetcher) dispatchBlock(b *block) {
+	height := uint64(b.block.Height)
+	if err := f.dao.UpdateBlockStatus(height, fetcher.StatusProcessing); err != nil {
+		log.Errorf("[cosmos event dispatcher]: failed to update block status to processing: %v", err)
+		return
+	}
+
+	var processErr error
+	for i := range b.block.Data.Txs {
+		cosmosTx, err := decodeTx(f.chainID, b.block, i, b.results.TxsResults[i])
+		if err != nil {
+			// a malformed message will not decode on retry either
+			log.Errorf("[cosmos event dispatcher]: failed to decode tx %d of block %d: %v", i, height, err)
+			continue
+		}
+		if cosmosTx == nil {
+			continue
+		}
+		if processErr = f.dispatchTx(cosmosTx); processErr != nil {
+			break
+		}
+	}
+
+	if processErr != nil {
+		log.Errorf("[cosmos event dispatcher]: failed to process block %d: %v", height, processErr)
+		if err := f.dao.MarkBlockForRetry(height, f.blockMaxRetry); err != nil {
+			log.Errorf("[cosmos event dispatcher]: failed to mark block for retry: %v", err)
+		}
+		return
+	}
+	if err := f.dao.UpdateBlockStatus(height, fetcher.StatusProcessed); err != nil {
+		log.Errorf("[cosmos event dispatcher]: failed to update block status to processed: %v", err)
+		return
+	}
+	log.Infof("[cosmos event dispatcher]: processed block %d", height)
+}
+
+// dispatchTx sends the tx to every indexer and waits for all of them to process it
+func (f *cosmosFetcher) dispatchTx(cosmosTx *common.CosmosTx) error {
+	resChs := make([]chan error, 0, len(f.indexers))
+	for _, indexer := range f.indexers {
+		resCh := make(chan error, 1)
+		resChs = append(resChs, resCh)
+		eventCtx := &common.EventContext{
+			ChainID:    f.evmChainID,
+			Cosmos:     cosmosTx,
+			ResultChan: resCh,
+		}
+
+		go func(indexer common.Indexer) {
+			select {
+			case <-f.processCtx.Done():
+				log.Info("[cosmos event dispatcher]: aborted")
+			case indexer.Input() <- eventCtx:
+				log.Debugf("[cosmos event dispatcher]: submitted tx %s to %s indexer", cosmosTx.Hash, indexer.Name())
+			}
+		}(indexer)
+	}
+
+	for _, resCh := range resChs {
+		select {
+		case <-f.processCtx.Done():
+			return f.processCtx.Err()
+		case err, ok := <-resCh:
+			if !ok {
+				return errors.New("result chan closed")
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *cosmosFetcher) monitorStaleProcessingTasks() {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			log.Info("[cosmos stale processing task monitor]: stopped")
+			return
+		case <-ticker.C:
+			if err := f.dao.ResetStaleProcessingBlocks(f.maxProcessingTime); err != nil {
+				log.Errorf("[cosmos stale processing task monitor]: failed to reset stale processing blocks: %v", err)
+			}
+		}
+	}
+}
+
+func (f *cosmosFetcher) Metrics() interface{} {
+	status, err := f.client.Status(f.processCtx)
+	if err != nil {
+		log.Error("[cosmos fetcher] error fetching node status:", err)
+		return nil
+	}
+	highestSyncedBlock, err := f.dao.GetLatestProcessedBlock()
+	if err != nil {
+		log.Error("[cosmos fetcher] error fetching highest synced block:", err)
+		return nil
+	}
+	waitingBlocks, err := f.dao.GetCountByBlockStatus(fetcher.StatusUnprocessed)
+	if err != nil {
+		log.Error("[cosmos fetcher] error fetching waiting blocks:", err)
+		return nil
+	}
+	retryBlocks, err := f.dao.GetCountByBlockStatus(fetcher.StatusRetry)
+	if err != nil {
+		log.Error("[cosmos fetcher] error fetching retry blocks:", err)
+		return nil
+	}
+
+	return struct {
+		ChainID            string `json:"chain_id"`
+		LatestBlock        int64  `json:"latest_block"`
+		HighestSyncedBlock uint64 `json:"highest_synced_block"`
+		WaitingBlocks      uint64 `json:"waiting_blocks"`
+		RetryBlocks        uint64 `json:"retry_blocks"`
+		BlockCacheSize     int    `json:"block_cache_queue_size"`
+	}{
+		ChainID:            f.chainID,
+		LatestBlock:        status.SyncInfo.LatestBlockHeight,
+		HighestSyncedBlock: highestSyncedBlock,
+		WaitingBlocks:      waitingBlocks,
+		RetryBlocks:        retryBlocks,
+		BlockCacheSize:     len(f.blockCache),
+	}
+}
@@ -0,0 +1,227 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/p2p"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/fetcher"
+	"github.com/artela-network/galxe-integration/indexer"
+)
+
+type stubClient struct {
+	blocks map[int64]*cmttypes.Block
+}
+
+func (c *stubClient) Status(context.Context) (*coretypes.ResultStatus, error) {
+	return &coretypes.ResultStatus{
+		NodeInfo: p2p.DefaultNodeInfo{Network: "artela_11822-1"},
+		SyncInfo: coretypes.SyncInfo{LatestBlockHeight: int64(len(c.blocks))},
+	}, nil
+}
+
+func (c *stubClient) Block(_ context.Context, height *int64) (*coretypes.ResultBlock, error) {
+	return &coretypes.ResultBlock{Block: c.blocks[*height]}, nil
+}
+
+func (c *stubClient) BlockResults(_ context.Context, height *int64) (*coretypes.ResultBlockResults, error) {
+	results := make([]*abci.ExecTxResult, len(c.blocks[*height].Data.Txs))
+	for i := range results {
+		results[i] = &abci.ExecTxResult{}
+	}
+	return &coretypes.ResultBlockResults{Height: *height, TxsResults: results}, nil
+}
+
+// memoryDAO keeps the block status and retry count of each block
+type memoryDAO struct {
+	mu      sync.Mutex
+	status  map[uint64]fetcher.BlockStatus
+	retries map[uint64]uint64
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{status: make(map[uint64]fetcher.BlockStatus), retries: make(map[uint64]uint64)}
+}
+
+func (dao *memoryDAO) AddBlock(blockNumber uint64, status fetcher.BlockStatus) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+	if _, ok := dao.status[blockNumber]; !ok {
+		dao.status[blockNumber] = status
+	}
+	return nil
+}
+
+func (dao *memoryDAO) UpdateBlockStatus(blockNumber uint64, status fetcher.BlockStatus) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+	dao.status[blockNumber] = status
+	return nil
+}
+
+func (dao *memoryDAO) SetBlockHash(uint64, string) error { return nil }
+
+func (dao *memoryDAO) GetBlockHashes(uint64) (map[uint64]string, error) { return nil, nil }
+
+func (dao *memoryDAO) ResetBlocks(uint64) error { return nil }
+
+func (dao *memoryDAO) MigrateBlockStatus(blockNumber uint64, from fetcher.BlockStatus, to fetcher.BlockStatus) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+	if dao.status[blockNumber] == from {
+		dao.status[blockNumber] = to
+	}
+	return nil
+}
+
+func (dao *memoryDAO) byStatus(status fetcher.BlockStatus) []uint64 {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+	var blocks []uint64
+	for block, s := range dao.status {
+		if s == status {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func (dao *memoryDAO) GetUnprocessedBlocks() ([]uint64, error) {
+	return dao.byStatus(fetcher.StatusUnprocessed), nil
+}
+
+func (dao *memoryDAO) GetRetryBlocks(uint64, time.Duration) ([]uint64, error) {
+	return nil, nil
+}
+
+func (dao *memoryDAO) MarkBlockForRetry(blockNumber uint64, _ uint64) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+	dao.status[blockNumber] = fetcher.StatusRetry
+	dao.retries[blockNumber]++
+	return nil
+}
+
+func (dao *memoryDAO) GetLatestProcessedBlock() (uint64, error) {
+	var latest uint64
+	for _, block := range dao.byStatus(fetcher.StatusProcessed) {
+		latest = max(latest, block)
+	}
+	return latest, nil
+}
+
+func (dao *memoryDAO) GetBlockStatus(blockNumber uint64) (fetcher.BlockStatus, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+	status, ok := dao.status[blockNumber]
+	if !ok {
+		return 0, errors.New("block not found")
+	}
+	return status, nil
+}
+
+func (dao *memoryDAO) ResetStaleProcessingBlocks(time.Duration) error { return nil }
+
+func (dao *memoryDAO) GetCountByBlockStatus(status fetcher.BlockStatus) (uint64, error) {
+	return uint64(len(dao.byStatus(status))), nil
+}
+
+func (dao *memoryDAO) GetMaxProcessedBlockNumber() (uint64, error) {
+	return dao.GetLatestProcessedBlock()
+}
+
+type cosmosIndexer struct {
+	*indexer.Runner
+}
+
+func (c *cosmosIndexer) CosmosIndexer() {}
+
+func (c *cosmosIndexer) Metrics() interface{} { return nil }
+
+func (c *cosmosIndexer) Name() string { return "cosmos" }
+
+type evmIndexer struct {
+	*indexer.Runner
+}
+
+func (e *evmIndexer) Metrics() interface{} { return nil }
+
+func (e *evmIndexer) Name() string { return "evm" }
+
+func TestCosmosFetcher(t *testing.T) {
+	send := func(t *testing.T) cmttypes.Tx {
+		return encodeTx(t, &banktypes.MsgSend{FromAddress: sender, ToAddress: receiver, Amount: sdk.NewCoins(sdk.NewCoin("uart", sdkmath.NewInt(1)))})
+	}
+	client := &stubClient{blocks: map[int64]*cmttypes.Block{
+		1: {Header: cmttypes.Header{Height: 1}, Data: cmttypes.Data{Txs: cmttypes.Txs{send(t), cmttypes.Tx("evm tx")}}},
+		2: {Header: cmttypes.Header{Height: 2}, Data: cmttypes.Data{Txs: cmttypes.Txs{send(t)}}},
+		3: {Header: cmttypes.Header{Height: 3}},
+	}}
+
+	var mu sync.Mutex
+	var received []*common.EventContext
+	fail := true
+	cosmos := &cosmosIndexer{indexer.NewRunner(context.Background(), "cosmos", 1, func(eventCtx *common.EventContext) error {
+		mu.Lock()
+		defer mu.Unlock()
+		// the tx of block 2 fails once, so the block is retried
+		if eventCtx.Cosmos.Height == 2 && fail {
+			fail = false
+			return errors.New("failed")
+		}
+		received = append(received, eventCtx)
+		return nil
+	})}
+	evm := &evmIndexer{indexer.NewRunner(context.Background(), "evm", 1, func(*common.EventContext) error {
+		t.Error("evm indexer received a cosmos tx")
+		return nil
+	})}
+	require.NoError(t, cosmos.Start())
+	require.NoError(t, evm.Start())
+	t.Cleanup(cosmos.Stop)
+	t.Cleanup(evm.Stop)
+
+	dao := newMemoryDAO()
+	conf := &config.CosmosConfig{}
+	conf.FillDefaults()
+	f, err := newFetcher(context.Background(), conf, client, dao)
+	require.NoError(t, err)
+	f.RegisterIndexer(cosmos)
+	f.RegisterIndexer(evm)
+	require.Len(t, f.indexers, 1)
+
+	for height := uint64(1); height <= 3; height++ {
+		require.NoError(t, dao.AddBlock(height, fetcher.StatusUnprocessed))
+		b, err := f.fetchBlock(height)
+		require.NoError(t, err)
+		f.dispatchBlock(b)
+	}
+	require.Equal(t, []uint64{2}, dao.byStatus(fetcher.StatusRetry))
+
+	b, err := f.fetchBlock(2)
+	require.NoError(t, err)
+	f.dispatchBlock(b)
+	require.Empty(t, dao.byStatus(fetcher.StatusRetry))
+	require.Len(t, dao.byStatus(fetcher.StatusProcessed), 3)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2)
+	for _, eventCtx := range received {
+		require.Equal(t, int64(11822), eventCtx.ChainID.Int64())
+		require.Equal(t, common.CosmosActionBankSend, eventCtx.Cosmos.Messages[0].Action)
+	}
+}
@@ -17,9 +17,15 @@ const (
 )
 
 type DAO interface {
-	Init() DAO
 	AddBlock(blockNumber uint64, status BlockStatus) error
 	UpdateBlockStatus(blockNumber uint64, status BlockStatus) error
+	// SetBlockHash records the hash of the block being processed at the height, the indexers
+	// compare it with the hashes they recorded to detect reorgs.
+	SetBlockHash(blockNumber uint64, blockHash string) error
+	// GetBlockHashes returns the recorded hashes of the processed blocks from the block number on
+	GetBlockHashes(from uint64) (map[uint64]string, error)
+	// ResetBlocks marks the processed blocks from the block number on to be processed again
+	ResetBlocks(from uint64) error
 	MigrateBlockStatus(blockNumber uint64, from BlockStatus, to BlockStatus) error
 	GetUnprocessedBlocks() ([]uint64, error)
 	GetRetryBlocks(maxRetry uint64, retryThreshold time.Duration) ([]uint64, error)
@@ -31,7 +37,11 @@ type DAO interface {
 	GetMaxProcessedBlockNumber() (uint64, error)
 }
 
-type Builder func(ctx context.Context, db *sql.DB) DAO
+// DefaultTable is the table the block status of the evm fetcher is kept in.
+const DefaultTable = "block_status"
+
+// Builder creates a DAO keeping the block status in table, so fetchers of different chains do not share blocks.
+type Builder func(ctx context.Context, db *sql.DB, table string) DAO
 
 var registry Registry
 
@@ -44,13 +54,17 @@ func (r *Registry) Register(tpy string, builder Builder) {
 }
 
 func (r *Registry) GetDAO(ctx context.Context, driver string, db *sql.DB) DAO {
+	return r.GetDAOForTable(ctx, driver, db, DefaultTable)
+}
+
+func (r *Registry) GetDAOForTable(ctx context.Context, driver string, db *sql.DB, table string) DAO {
 	// Parsing the connection string (assuming it's in PostgreSQL format)
 	builder, exist := r.daos.Load(driver)
 	if !exist {
 		return nil
 	}
 
-	return builder.(Builder)(ctx, db)
+	return builder.(Builder)(ctx, db, table)
 }
 
 func GetRegistry() *Registry {
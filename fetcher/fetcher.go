@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/artela-network/galxe-integration/common"
 	"github.com/artela-network/galxe-integration/config"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -11,21 +12,31 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	log "github.com/sirupsen/logrus"
 	"math/big"
+	"sync"
 	"time"
 )
 
 type fetcher struct {
 	client              *ethclient.Client
+	chainID             *big.Int
 	blockCache          chan *types.Block
 	blockFetchTaskCache chan uint64
 	dao                 DAO
-	ctx                 context.Context
 	pullInterval        time.Duration
 	retryInterval       time.Duration
 	pollThread          uint64
 	blockMaxRetry       uint64
 	beginBlock          uint64
 	maxProcessingTime   time.Duration
+	drainTimeout        time.Duration
+	reorgDepth          uint64
+
+	// ctx stops fetching new blocks, processCtx aborts the blocks being dispatched
+	ctx           context.Context
+	cancel        context.CancelFunc
+	processCtx    context.Context
+	processCancel context.CancelFunc
+	dispatchers   sync.WaitGroup
 
 	indexers []common.Indexer
 }
@@ -40,28 +51,51 @@ func NewFetcher(ctx context.Context, config *config.FetcherConfig, driver string
 	}
 
 	client := ethclient.NewClient(rpcClient)
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		log.Error("failed to get chain id", err)
+		return nil, err
+	}
+
 	maxProcessingTime, err := time.ParseDuration(config.MaxProcessingTime)
 	if err != nil {
 		log.Error("failed to parse max processing time", err)
 		return nil, err
 	}
+	drainTimeout, err := time.ParseDuration(config.DrainTimeout)
+	if err != nil {
+		log.Error("failed to parse drain timeout", err)
+		return nil, err
+	}
 
+	fetchCtx, cancel := context.WithCancel(ctx)
+	processCtx, processCancel := context.WithCancel(ctx)
 	return &fetcher{
-		ctx:                 ctx,
+		ctx:                 fetchCtx,
+		cancel:              cancel,
+		processCtx:          processCtx,
+		processCancel:       processCancel,
 		client:              client,
+		chainID:             chainID,
 		blockCache:          make(chan *types.Block, config.BlockCacheSize),
 		blockFetchTaskCache: make(chan uint64, config.BlockCacheSize),
-		dao:                 GetRegistry().GetDAO(ctx, driver, db).Init(),
+		dao:                 GetRegistry().GetDAO(ctx, driver, db),
 		pullInterval:        time.Duration(config.PullIntervalMs) * time.Millisecond,
 		retryInterval:       time.Duration(config.RetryIntervalMs) * time.Millisecond,
 		pollThread:          config.PollThread,
 		blockMaxRetry:       config.BlockMaxRetry,
 		beginBlock:          config.BeginBlock,
 		maxProcessingTime:   maxProcessingTime,
+		drainTimeout:        drainTimeout,
+		reorgDepth:          config.ReorgDepth,
 	}, nil
 }
 
 func (f *fetcher) RegisterIndexer(indexer common.Indexer) {
+	if _, ok := indexer.(common.CosmosIndexer); ok {
+		log.Debugf("[fetcher] %s indexer only processes cosmos txs, skip", indexer.Name())
+		return
+	}
 	if f.indexers == nil {
 		f.indexers = make([]common.Indexer, 0, 1)
 	}
@@ -77,10 +111,36 @@ func (f *fetcher) Start() {
 	go f.createBlockListener()
 
 	for i := uint64(0); i < f.pollThread; i++ {
+		f.dispatchers.Add(1)
 		go f.createEventDispatcher()
 	}
 
 	go f.monitorStaleProcessingTasks()
+	go f.monitorReorgs()
+}
+
+// Stop stops fetching and dispatching new blocks, the blocks being dispatched are given
+// drainTimeout to finish. Blocks which are fetched but not dispatched yet stay unprocessed
+// in the db, and will be fetched again on the next start.
+func (f *fetcher) Stop() {
+	log.Info("[fetcher] stopping, draining in-flight blocks...")
+	f.cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		f.dispatchers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Info("[fetcher] all in-flight blocks drained")
+	case <-time.After(f.drainTimeout):
+		log.Warnf("[fetcher] in-flight blocks not drained in %s, aborting", f.drainTimeout)
+		f.processCancel()
+		<-drained
+	}
+	f.processCancel()
 }
 
 func (f *fetcher) monitorQueueSizes() {
@@ -175,83 +235,98 @@ func (f *fetcher) createBlockListener() {
 }
 
 func (f *fetcher) createEventDispatcher() {
+	defer f.dispatchers.Done()
+
 	for {
 		select {
 		case <-f.ctx.Done():
 			log.Info("[event dispatcher]: stopped")
 			return
 		case block := <-f.blockCache:
-			log.Debugf("[event dispatcher]: start dispatching block %d", block.NumberU64())
-			if err := f.dao.UpdateBlockStatus(block.NumberU64(), StatusProcessing); err != nil {
-				log.Errorf("[event dispatcher]: failed to update block status to prcessing: %v", err)
-				continue
+			f.dispatchBlock(block)
+		}
+	}
+}
+
+func (f *fetcher) dispatchBlock(block *types.Block) {
+	log.Debugf("[event dispatcher]: start dispatching block %d", block.NumberU64())
+	if err := f.dao.UpdateBlockStatus(block.NumberU64(), StatusProcessing); err != nil {
+		log.Errorf("[event dispatcher]: failed to update block status to prcessing: %v", err)
+		return
+	}
+	if err := f.dao.SetBlockHash(block.NumberU64(), block.Hash().Hex()); err != nil {
+		log.Errorf("[event dispatcher]: failed to record block hash: %v", err)
+		return
+	}
+	var processErr error
+	for i, tx := range block.Transactions() {
+		if tx.To() == nil {
+			log.Debugf("[event dispatcher]: ignore contract creation tx %s", tx.Hash().Hex())
+			continue
+		}
+
+		receipt, err := f.client.TransactionReceipt(f.processCtx, tx.Hash())
+		if err != nil {
+			log.Errorf("[event dispatcher]: error fetching receipt for tx %s: %v", tx.Hash().Hex(), err)
+			processErr = err
+			break
+		}
+		resChs := make([]chan error, 0, len(f.indexers))
+		for _, indexer := range f.indexers {
+			resCh := make(chan error, 1)
+			eventCtx := &common.EventContext{
+				ChainID:     f.chainID,
+				BlockHeader: block.Header(),
+				Transaction: tx,
+				Receipt:     receipt,
+				ResultChan:  resCh,
 			}
-			var processErr error
-			for i, tx := range block.Transactions() {
-				if tx.To() == nil {
-					log.Debugf("[event dispatcher]: ignore contract creation tx %s", tx.Hash().Hex())
-					continue
+			resChs = append(resChs, resCh)
+
+			go func(indexer common.Indexer, eventCtx *common.EventContext) {
+				log.Debugf("[event dispatcher]: submitting event task [block %d]->[tx %d]", block.NumberU64(), i)
+				select {
+				case <-f.processCtx.Done():
+					log.Info("[event dispatcher]: aborted")
+				case indexer.Input() <- eventCtx:
+					log.Debugf("[event dispatcher]: submitted event task [block %d]->[tx %d]", block.NumberU64(), i)
 				}
+			}(indexer, eventCtx)
+		}
 
-				receipt, err := f.client.TransactionReceipt(f.ctx, tx.Hash())
+		for _, resCh := range resChs {
+			select {
+			case <-f.processCtx.Done():
+				log.Info("[event dispatcher]: aborted")
+				processErr = f.processCtx.Err()
+			case err, ok := <-resCh:
+				if !ok {
+					log.Errorf("[event dispatcher]: error dispatching event: channel closed")
+					processErr = errors.New("unknown error")
+					break
+				}
 				if err != nil {
-					log.Errorf("[event dispatcher]: error fetching receipt for tx %s: %v", tx.Hash().Hex(), err)
+					log.Errorf("[event dispatcher]: error dispatching event: %v", err)
 					processErr = err
 					break
 				}
-				resChs := make([]chan error, 0, len(f.indexers))
-				for _, indexer := range f.indexers {
-					resCh := make(chan error, 1)
-					eventCtx := &common.EventContext{
-						BlockHeader: block.Header(),
-						Transaction: tx,
-						Receipt:     receipt,
-						ResultChan:  resCh,
-					}
-					resChs = append(resChs, resCh)
-
-					go func(indexer common.Indexer, eventCtx *common.EventContext) {
-						log.Debugf("[event dispatcher]: submitting event task [block %d]->[tx %d]", block.NumberU64(), i)
-						select {
-						case <-f.ctx.Done():
-							log.Info("[event dispatcher]: stopped")
-						case indexer.Input() <- eventCtx:
-							log.Debugf("[event dispatcher]: submitted event task [block %d]->[tx %d]", block.NumberU64(), i)
-						}
-					}(indexer, eventCtx)
-				}
-
-				for _, resCh := range resChs {
-					select {
-					case <-f.ctx.Done():
-						log.Info("[event dispatcher]: stopped")
-					case err, ok := <-resCh:
-						if !ok {
-							log.Errorf("[event dispatcher]: error dispatching event: channel closed")
-							processErr = errors.New("unknown error")
-							break
-						}
-						if err != nil {
-							log.Errorf("[event dispatcher]: error dispatching event: %v", err)
-							processErr = err
-							break
-						}
-					}
-				}
-			}
-			if processErr != nil {
-				log.Errorf("[event dispatcher]: failed to process block %d: %v", block.NumberU64(), processErr)
-				if err := f.dao.MarkBlockForRetry(block.NumberU64(), f.blockMaxRetry); err != nil {
-					log.Errorf("[event dispatcher]: failed to mark block for retry: %v", err)
-				}
-			} else {
-				if err := f.dao.UpdateBlockStatus(block.NumberU64(), StatusProcessed); err != nil {
-					log.Errorf("[event dispatcher]: failed to update block status to processed: %v", err)
-					continue
-				}
-				log.Infof("[event dispatcher]: processed block %d", block.NumberU64())
 			}
 		}
+		if processErr != nil {
+			break
+		}
+	}
+	if processErr != nil {
+		log.Errorf("[event dispatcher]: failed to process block %d: %v", block.NumberU64(), processErr)
+		if err := f.dao.MarkBlockForRetry(block.NumberU64(), f.blockMaxRetry); err != nil {
+			log.Errorf("[event dispatcher]: failed to mark block for retry: %v", err)
+		}
+	} else {
+		if err := f.dao.UpdateBlockStatus(block.NumberU64(), StatusProcessed); err != nil {
+			log.Errorf("[event dispatcher]: failed to update block status to processed: %v", err)
+			return
+		}
+		log.Infof("[event dispatcher]: processed block %d", block.NumberU64())
 	}
 }
 
@@ -311,8 +386,69 @@ func (f *fetcher) monitorStaleProcessingTasks() {
 	}
 }
 
+func (f *fetcher) monitorReorgs() {
+	// checks every 5s the last processed blocks against the canonical chain
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			log.Info("[reorg monitor]: stopped")
+			return
+		case <-ticker.C:
+			if err := f.checkReorg(); err != nil {
+				log.Errorf("[reorg monitor]: failed to check reorgs: %v", err)
+			}
+		}
+	}
+}
+
+// checkReorg compares the hashes recorded for the last processed blocks with the canonical chain. From the
+// first replaced block on, the indexers rewind what they recorded and the blocks are processed again. If an
+// indexer fails to rewind, the blocks are left as they are and the reorg is found again on the next check.
+func (f *fetcher) checkReorg() error {
+	lastProcessedBlock, err := f.dao.GetLatestProcessedBlock()
+	if err != nil || lastProcessedBlock < f.beginBlock {
+		return err
+	}
+	from := f.beginBlock
+	if lastProcessedBlock-from+1 > f.reorgDepth {
+		from = lastProcessedBlock - f.reorgDepth + 1
+	}
+	hashes, err := f.dao.GetBlockHashes(from)
+	if err != nil {
+		return err
+	}
+
+	for number := from; number <= lastProcessedBlock; number++ {
+		recorded, ok := hashes[number]
+		if !ok {
+			continue
+		}
+		header, err := f.client.HeaderByNumber(f.ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		if header.Hash().Hex() == recorded {
+			continue
+		}
+
+		log.Warnf("[reorg monitor]: block %d was replaced by a reorg, rewinding the indexers", number)
+		for _, indexer := range f.indexers {
+			if rewinder, ok := indexer.(common.Rewinder); ok {
+				if err := rewinder.Rewind(number); err != nil {
+					return fmt.Errorf("failed to rewind %s indexer to block %d: %w", indexer.Name(), number, err)
+				}
+			}
+		}
+		return f.dao.ResetBlocks(number)
+	}
+	return nil
+}
+
 func (f *fetcher) Metrics() interface{} {
-	blockNumber, err := f.client.BlockNumber(f.ctx)
+	blockNumber, err := f.client.BlockNumber(f.processCtx)
 	if err != nil {
 		log.Error("[fetcher] error fetching latest block number:", err)
 		return nil
@@ -7,68 +7,71 @@ import (
 	"fmt"
 	"github.com/artela-network/galxe-integration/fetcher"
 	_ "github.com/lib/pq"
-	log "github.com/sirupsen/logrus"
 	"time"
 )
 
 const driver = "postgres"
 
 type postgresDAO struct {
-	conn *sql.DB
+	conn  *sql.DB
+	table string
 }
 
-func createIndex(conn *sql.DB, indexName, tableName, columnName string) {
-	query := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s);", indexName, tableName, columnName)
-	_, err := conn.Exec(query)
-	if err != nil {
-		log.Fatalf("Error creating index %s: %q", indexName, err)
-	} else {
-		log.Infof("Index %s created successfully.", indexName)
-	}
-}
-
-func newPostgresDAO(_ context.Context, db *sql.DB) fetcher.DAO {
+func newPostgresDAO(_ context.Context, db *sql.DB, table string) fetcher.DAO {
 	return &postgresDAO{
-		conn: db,
+		conn:  db,
+		table: table,
 	}
 }
-func (dao *postgresDAO) Init() fetcher.DAO {
-	createTableSQL := `
-        CREATE TABLE IF NOT EXISTS block_status (
-            id SERIAL PRIMARY KEY,
-            block_number INTEGER NOT NULL UNIQUE,
-            status INTEGER NOT NULL,
-            retry_count INTEGER DEFAULT 0,
-            last_retry_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-        );`
-	_, err := dao.conn.Exec(createTableSQL)
-	if err != nil {
-		log.Fatal(err)
-	}
 
-	createIndex(dao.conn, "status_index", "block_status", "status")
-	createIndex(dao.conn, "last_retry_at_index", "block_status", "last_retry_at")
+func (dao *postgresDAO) AddBlock(blockNumber uint64, status fetcher.BlockStatus) error {
+	_, err := dao.conn.Exec("INSERT INTO "+dao.table+" (block_number, status) VALUES ($1, $2) ON CONFLICT (block_number) DO NOTHING", blockNumber, status)
+	return err
+}
 
-	return dao
+func (dao *postgresDAO) UpdateBlockStatus(blockNumber uint64, status fetcher.BlockStatus) error {
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = $1 WHERE block_number = $2", status, blockNumber)
+	return err
 }
 
-func (dao *postgresDAO) AddBlock(blockNumber uint64, status fetcher.BlockStatus) error {
-	_, err := dao.conn.Exec("INSERT INTO block_status (block_number, status) VALUES ($1, $2) ON CONFLICT (block_number) DO NOTHING", blockNumber, status)
+func (dao *postgresDAO) SetBlockHash(blockNumber uint64, blockHash string) error {
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET block_hash = $1 WHERE block_number = $2", blockHash, blockNumber)
 	return err
 }
 
-func (dao *postgresDAO) UpdateBlockStatus(blockNumber uint64, status fetcher.BlockStatus) error {
-	_, err := dao.conn.Exec("UPDATE block_status SET status = $1 WHERE block_number = $2", status, blockNumber)
+func (dao *postgresDAO) GetBlockHashes(from uint64) (map[uint64]string, error) {
+	rows, err := dao.conn.Query("SELECT block_number, block_hash FROM "+dao.table+" WHERE block_number >= $1 AND status = $2 "+
+		"AND block_hash IS NOT NULL", from, fetcher.StatusProcessed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make(map[uint64]string)
+	for rows.Next() {
+		var blockNumber uint64
+		var blockHash string
+		if err := rows.Scan(&blockNumber, &blockHash); err != nil {
+			return nil, err
+		}
+		hashes[blockNumber] = blockHash
+	}
+	return hashes, rows.Err()
+}
+
+func (dao *postgresDAO) ResetBlocks(from uint64) error {
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = $1, retry_count = 0 WHERE block_number >= $2 AND status = $3",
+		fetcher.StatusUnprocessed, from, fetcher.StatusProcessed)
 	return err
 }
 
 func (dao *postgresDAO) MigrateBlockStatus(blockNumber uint64, from fetcher.BlockStatus, to fetcher.BlockStatus) error {
-	_, err := dao.conn.Exec("UPDATE block_status SET status = $1 WHERE block_number = $2 AND status = $3", to, blockNumber, from)
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = $1 WHERE block_number = $2 AND status = $3", to, blockNumber, from)
 	return err
 }
 
 func (dao *postgresDAO) GetUnprocessedBlocks() ([]uint64, error) {
-	rows, err := dao.conn.Query("SELECT block_number FROM block_status WHERE status = 0")
+	rows, err := dao.conn.Query("SELECT block_number FROM " + dao.table + " WHERE status = 0")
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +90,7 @@ func (dao *postgresDAO) GetUnprocessedBlocks() ([]uint64, error) {
 }
 
 func (dao *postgresDAO) GetRetryBlocks(maxRetry uint64, retryThreshold time.Duration) ([]uint64, error) {
-	rows, err := dao.conn.Query("SELECT block_number FROM block_status WHERE status = 3 AND retry_count < $1 AND (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - EXTRACT(EPOCH FROM last_retry_at)) > $2", maxRetry, int64(retryThreshold.Seconds()))
+	rows, err := dao.conn.Query("SELECT block_number FROM "+dao.table+" WHERE status = 3 AND retry_count < $1 AND (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - EXTRACT(EPOCH FROM last_retry_at)) > $2", maxRetry, int64(retryThreshold.Seconds()))
 	if err != nil {
 		return nil, err
 	}
@@ -106,14 +109,14 @@ func (dao *postgresDAO) GetRetryBlocks(maxRetry uint64, retryThreshold time.Dura
 }
 
 func (dao *postgresDAO) MarkBlockForRetry(blockNumber uint64, maxRetry uint64) error {
-	_, err := dao.conn.Exec("UPDATE block_status SET status = $1, retry_count = retry_count + 1, last_retry_at = CURRENT_TIMESTAMP WHERE block_number = $2 AND retry_count < $3",
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = $1, retry_count = retry_count + 1, last_retry_at = CURRENT_TIMESTAMP WHERE block_number = $2 AND retry_count < $3",
 		fetcher.StatusRetry, blockNumber, maxRetry)
 	return err
 }
 
 func (dao *postgresDAO) GetLatestProcessedBlock() (uint64, error) {
 	var latestBlock uint64
-	row := dao.conn.QueryRow("SELECT block_number FROM block_status WHERE status = $1 ORDER BY block_number DESC LIMIT 1", fetcher.StatusProcessed)
+	row := dao.conn.QueryRow("SELECT block_number FROM "+dao.table+" WHERE status = $1 ORDER BY block_number DESC LIMIT 1", fetcher.StatusProcessed)
 	err := row.Scan(&latestBlock)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -126,7 +129,7 @@ func (dao *postgresDAO) GetLatestProcessedBlock() (uint64, error) {
 
 func (dao *postgresDAO) GetBlockStatus(blockNumber uint64) (fetcher.BlockStatus, error) {
 	var status fetcher.BlockStatus
-	row := dao.conn.QueryRow("SELECT status FROM block_status WHERE block_number = $1", blockNumber)
+	row := dao.conn.QueryRow("SELECT status FROM "+dao.table+" WHERE block_number = $1", blockNumber)
 	err := row.Scan(&status)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -139,14 +142,14 @@ func (dao *postgresDAO) GetBlockStatus(blockNumber uint64) (fetcher.BlockStatus,
 
 func (dao *postgresDAO) ResetStaleProcessingBlocks(threshold time.Duration) error {
 	_, err := dao.conn.Exec(
-		"UPDATE block_status SET status = $1, last_retry_at = CURRENT_TIMESTAMP WHERE status = $2 AND (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - EXTRACT(EPOCH FROM last_retry_at)) > $3",
+		"UPDATE "+dao.table+" SET status = $1, last_retry_at = CURRENT_TIMESTAMP WHERE status = $2 AND (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - EXTRACT(EPOCH FROM last_retry_at)) > $3",
 		fetcher.StatusUnprocessed, fetcher.StatusProcessing, int64(threshold.Seconds()))
 	return err
 }
 
 func (dao *postgresDAO) GetCountByBlockStatus(status fetcher.BlockStatus) (uint64, error) {
 	var count uint64
-	row := dao.conn.QueryRow("SELECT COUNT(*) FROM block_status WHERE status = $1", status)
+	row := dao.conn.QueryRow("SELECT COUNT(*) FROM "+dao.table+" WHERE status = $1", status)
 	err := row.Scan(&count)
 	if err != nil {
 		return 0, err
@@ -156,7 +159,7 @@ func (dao *postgresDAO) GetCountByBlockStatus(status fetcher.BlockStatus) (uint6
 
 func (dao *postgresDAO) GetMaxProcessedBlockNumber() (uint64, error) {
 	var maxBlockNumber uint64
-	row := dao.conn.QueryRow("SELECT MAX(block_number) FROM block_status WHERE status = $1", fetcher.StatusProcessed)
+	row := dao.conn.QueryRow("SELECT MAX(block_number) FROM "+dao.table+" WHERE status = $1", fetcher.StatusProcessed)
 	err := row.Scan(&maxBlockNumber)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -6,14 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/artela-network/galxe-integration/fetcher"
-	log "github.com/sirupsen/logrus"
 	"time"
 )
 
 const driver = "sqlite3"
 
 type sqliteDAO struct {
-	conn *sql.DB
+	conn  *sql.DB
+	table string
 }
 
 func (dao *sqliteDAO) GetCountByBlockStatus(status fetcher.BlockStatus) (uint64, error) {
@@ -26,46 +26,61 @@ func (dao *sqliteDAO) GetMaxProcessedBlockNumber() (uint64, error) {
 	panic("implement me")
 }
 
-func newSqliteDAO(_ context.Context, db *sql.DB) fetcher.DAO {
+func newSqliteDAO(_ context.Context, db *sql.DB, table string) fetcher.DAO {
 	return &sqliteDAO{
-		conn: db,
+		conn:  db,
+		table: table,
 	}
 }
 
-func (dao *sqliteDAO) Init() fetcher.DAO {
-	createTableSQL := `
-		CREATE TABLE IF NOT EXISTS block_status (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			block_number INTEGER NOT NULL UNIQUE,
-			status INTEGER NOT NULL,
-			retry_count INTEGER DEFAULT 0,
-			last_retry_at DATETIME
-		);`
-	_, err := dao.conn.Exec(createTableSQL)
-	if err != nil {
-		log.Fatal(err)
-	}
+func (dao *sqliteDAO) AddBlock(blockNumber uint64, status fetcher.BlockStatus) error {
+	_, err := dao.conn.Exec("INSERT OR IGNORE INTO "+dao.table+" (block_number, status) VALUES (?, ?)", blockNumber, status)
+	return err
+}
 
-	return dao
+func (dao *sqliteDAO) UpdateBlockStatus(blockNumber uint64, status fetcher.BlockStatus) error {
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = ? WHERE block_number = ?", status, blockNumber)
+	return err
 }
 
-func (dao *sqliteDAO) AddBlock(blockNumber uint64, status fetcher.BlockStatus) error {
-	_, err := dao.conn.Exec("INSERT OR IGNORE INTO block_status (block_number, status) VALUES (?, ?)", blockNumber, status)
+func (dao *sqliteDAO) SetBlockHash(blockNumber uint64, blockHash string) error {
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET block_hash = ? WHERE block_number = ?", blockHash, blockNumber)
 	return err
 }
 
-func (dao *sqliteDAO) UpdateBlockStatus(blockNumber uint64, status fetcher.BlockStatus) error {
-	_, err := dao.conn.Exec("UPDATE block_status SET status = ? WHERE block_number = ?", status, blockNumber)
+func (dao *sqliteDAO) GetBlockHashes(from uint64) (map[uint64]string, error) {
+	rows, err := dao.conn.Query("SELECT block_number, block_hash FROM "+dao.table+" WHERE block_number >= ? AND status = ? "+
+		"AND block_hash IS NOT NULL", from, fetcher.StatusProcessed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make(map[uint64]string)
+	for rows.Next() {
+		var blockNumber uint64
+		var blockHash string
+		if err := rows.Scan(&blockNumber, &blockHash); err != nil {
+			return nil, err
+		}
+		hashes[blockNumber] = blockHash
+	}
+	return hashes, rows.Err()
+}
+
+func (dao *sqliteDAO) ResetBlocks(from uint64) error {
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = ?, retry_count = 0 WHERE block_number >= ? AND status = ?",
+		fetcher.StatusUnprocessed, from, fetcher.StatusProcessed)
 	return err
 }
 
 func (dao *sqliteDAO) MigrateBlockStatus(blockNumber uint64, from fetcher.BlockStatus, to fetcher.BlockStatus) error {
-	_, err := dao.conn.Exec("UPDATE block_status SET status = ? WHERE block_number = ? AND status = ?", to, blockNumber, from)
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = ? WHERE block_number = ? AND status = ?", to, blockNumber, from)
 	return err
 }
 
 func (dao *sqliteDAO) GetUnprocessedBlocks() ([]uint64, error) {
-	rows, err := dao.conn.Query("SELECT block_number FROM block_status WHERE status = 0")
+	rows, err := dao.conn.Query("SELECT block_number FROM " + dao.table + " WHERE status = 0")
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +99,7 @@ func (dao *sqliteDAO) GetUnprocessedBlocks() ([]uint64, error) {
 }
 
 func (dao *sqliteDAO) GetRetryBlocks(maxRetry uint64, retryThreshold time.Duration) ([]uint64, error) {
-	rows, err := dao.conn.Query("SELECT block_number FROM block_status WHERE status = 3 AND retry_count < ? AND (strftime('%s', 'now') - strftime('%s', last_retry_at)) > ?", maxRetry, retryThreshold)
+	rows, err := dao.conn.Query("SELECT block_number FROM "+dao.table+" WHERE status = 3 AND retry_count < ? AND (strftime('%s', 'now') - strftime('%s', last_retry_at)) > ?", maxRetry, retryThreshold)
 	if err != nil {
 		return nil, err
 	}
@@ -103,14 +118,14 @@ func (dao *sqliteDAO) GetRetryBlocks(maxRetry uint64, retryThreshold time.Durati
 }
 
 func (dao *sqliteDAO) MarkBlockForRetry(blockNumber uint64, maxRetry uint64) error {
-	_, err := dao.conn.Exec("UPDATE block_status SET status = ?, retry_count = retry_count + 1, last_retry_at = CURRENT_TIMESTAMP WHERE block_number = ? AND retry_count < ?",
+	_, err := dao.conn.Exec("UPDATE "+dao.table+" SET status = ?, retry_count = retry_count + 1, last_retry_at = CURRENT_TIMESTAMP WHERE block_number = ? AND retry_count < ?",
 		fetcher.StatusUnprocessed, blockNumber, maxRetry)
 	return err
 }
 
 func (dao *sqliteDAO) GetLatestProcessedBlock() (uint64, error) {
 	var latestBlock uint64
-	row := dao.conn.QueryRow("SELECT block_number FROM block_status WHERE status = ? ORDER BY block_number DESC LIMIT 1", fetcher.StatusProcessed)
+	row := dao.conn.QueryRow("SELECT block_number FROM "+dao.table+" WHERE status = ? ORDER BY block_number DESC LIMIT 1", fetcher.StatusProcessed)
 	err := row.Scan(&latestBlock)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -123,7 +138,7 @@ func (dao *sqliteDAO) GetLatestProcessedBlock() (uint64, error) {
 
 func (dao *sqliteDAO) GetBlockStatus(blockNumber uint64) (fetcher.BlockStatus, error) {
 	var status fetcher.BlockStatus
-	row := dao.conn.QueryRow("SELECT status FROM block_status WHERE block_number = ?", blockNumber)
+	row := dao.conn.QueryRow("SELECT status FROM "+dao.table+" WHERE block_number = ?", blockNumber)
 	err := row.Scan(&status)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -136,7 +151,7 @@ func (dao *sqliteDAO) GetBlockStatus(blockNumber uint64) (fetcher.BlockStatus, e
 
 func (dao *sqliteDAO) ResetStaleProcessingBlocks(threshold time.Duration) error {
 	_, err := dao.conn.Exec(
-		"UPDATE block_status SET status = ? WHERE status = ? AND (strftime('%s', 'now') - strftime('%s', last_retry_at)) > ?",
+		"UPDATE "+dao.table+" SET status = ? WHERE status = ? AND (strftime('%s', 'now') - strftime('%s', last_retry_at)) > ?",
 		fetcher.StatusUnprocessed, fetcher.StatusProcessing, int64(threshold.Seconds()))
 	return err
 }
@@ -0,0 +1,395 @@
+package goclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/artela-network/galxe-integration/config"
+	aspectcontract "github.com/artela-network/galxe-integration/contracts/aspect"
+	nftcontract "github.com/artela-network/galxe-integration/contracts/nft"
+	scoredcontract "github.com/artela-network/galxe-integration/contracts/scored"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Names of the abis registered in the default registry.
+const (
+	ABIAspect  = "aspect"
+	ABIERC721  = "erc721"
+	ABIERC1155 = "erc1155"
+	ABIScored  = "scored"
+)
+
+var (
+	ErrABINotFound     = errors.New("abi not found")
+	ErrUnknownSelector = errors.New("unknown selector")
+	ErrUnknownEvent    = errors.New("unknown event")
+
+	// selectors of the errors every solidity contract may revert with
+	revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector  = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+var (
+	registry     *ABIRegistry
+	registryOnce sync.Once
+)
+
+// GetABIRegistry returns the registry shared by the indexers, the updater and the api,
+// the abis of the contracts shipped with the service are registered by default.
+func GetABIRegistry() *ABIRegistry {
+	registryOnce.Do(func() {
+		registry = NewABIRegistry()
+		for name, abiJSON := range map[string]string{
+			ABIAspect:  aspectcontract.ABI,
+			ABIERC721:  nftcontract.ERC721ABI,
+			ABIERC1155: nftcontract.ERC1155ABI,
+			ABIScored:  scoredcontract.ABI,
+		} {
+			if err := registry.Register(name, abiJSON); err != nil {
+				panic(err)
+			}
+		}
+		registry.BindContract(aspectcontract.SystemContractAddress, ABIAspect)
+	})
+	return registry
+}
+
+// ABIRegistry keeps the named abis, and which abi each known contract is decoded with.
+type ABIRegistry struct {
+	sync.RWMutex
+	abis      map[string]*abi.ABI
+	contracts map[common.Address]string
+}
+
+func NewABIRegistry() *ABIRegistry {
+	return &ABIRegistry{
+		abis:      make(map[string]*abi.ABI),
+		contracts: make(map[common.Address]string),
+	}
+}
+
+// Register parses the abi json and registers it under name, replacing any abi with the same name.
+func (r *ABIRegistry) Register(name string, abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return fmt.Errorf("failed to parse abi %s: %w", name, err)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.abis[name] = &parsed
+	return nil
+}
+
+// RegisterFile registers the abi json file under name.
+func (r *ABIRegistry) RegisterFile(name string, file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to open abi %s: %w", file, err)
+	}
+	return r.Register(name, string(content))
+}
+
+// LoadDir registers every *.abi file under dir, named by the file name without the extension.
+func (r *ABIRegistry) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.abi"))
+	if err != nil {
+		return err
+	}
+	direct, err := filepath.Glob(filepath.Join(dir, "*.abi"))
+	if err != nil {
+		return err
+	}
+	for _, file := range append(direct, files...) {
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if err := r.RegisterFile(name, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load registers the abis and contracts of the config.
+func (r *ABIRegistry) Load(conf *config.ABIConfig) error {
+	if conf.Dir != "" {
+		if err := r.LoadDir(conf.Dir); err != nil {
+			return err
+		}
+	}
+	for name, file := range conf.Files {
+		if err := r.RegisterFile(name, file); err != nil {
+			return err
+		}
+	}
+	for address, name := range conf.Contracts {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid contract address %q of abi %s", address, name)
+		}
+		if _, err := r.Get(name); err != nil {
+			return err
+		}
+		r.BindContract(common.HexToAddress(address), name)
+	}
+	return nil
+}
+
+// BindContract tells the registry to decode the calls and logs of the contract with the named abi.
+func (r *ABIRegistry) BindContract(contract common.Address, name string) {
+	r.Lock()
+	defer r.Unlock()
+	r.contracts[contract] = name
+}
+
+// Get returns the abi registered under name.
+func (r *ABIRegistry) Get(name string) (*abi.ABI, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	parsed, ok := r.abis[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrABINotFound, name)
+	}
+	return parsed, nil
+}
+
+// Resolve returns the abi registered under nameOrFile, or loads it from the file with that path
+// and registers it under the path, so configs can refer to abis either way.
+func (r *ABIRegistry) Resolve(nameOrFile string) (*abi.ABI, error) {
+	if parsed, err := r.Get(nameOrFile); err == nil {
+		return parsed, nil
+	}
+	if err := r.RegisterFile(nameOrFile, nameOrFile); err != nil {
+		return nil, err
+	}
+	return r.Get(nameOrFile)
+}
+
+// ByContract returns the name of the abi bound to the contract.
+func (r *ABIRegistry) ByContract(contract common.Address) (string, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	name, ok := r.contracts[contract]
+	return name, ok
+}
+
+// DecodedCall is a contract call decoded from the tx calldata.
+type DecodedCall struct {
+	Method string                 `json:"method"`
+	Args   map[string]interface{} `json:"args"`
+}
+
+// DecodedEvent is a contract event decoded from a receipt log.
+type DecodedEvent struct {
+	Event string                 `json:"event"`
+	Args  map[string]interface{} `json:"args"`
+}
+
+// DecodedRevert is the error a tx reverted with.
+type DecodedRevert struct {
+	Error string                 `json:"error"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// DecodeCalldata decodes the tx calldata with the named abi.
+func (r *ABIRegistry) DecodeCalldata(name string, data []byte) (*DecodedCall, error) {
+	parsed, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, ErrUnknownSelector
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSelector, hexutil.Encode(data[:4]))
+	}
+
+	args := make(map[string]interface{}, len(method.Inputs))
+	if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return nil, fmt.Errorf("failed to decode %s call: %w", method.Name, err)
+	}
+	return &DecodedCall{Method: method.Name, Args: args}, nil
+}
+
+// DecodeLog decodes the receipt log with the named abi.
+func (r *ABIRegistry) DecodeLog(name string, ethLog *types.Log) (*DecodedEvent, error) {
+	parsed, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(ethLog.Topics) == 0 {
+		return nil, ErrUnknownEvent
+	}
+	event, err := parsed.EventByID(ethLog.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, ethLog.Topics[0].Hex())
+	}
+
+	args, err := DecodeEventLog(event, ethLog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", event.Name, err)
+	}
+	return &DecodedEvent{Event: event.Name, Args: args}, nil
+}
+
+// DecodeEventLog unpacks both indexed and non-indexed event arguments into a map keyed by argument name.
+func DecodeEventLog(event *abi.Event, ethLog *types.Log) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(event.Inputs))
+	if err := event.Inputs.UnpackIntoMap(args, ethLog.Data); err != nil {
+		return nil, err
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if len(indexed) > 0 {
+		if len(ethLog.Topics) != len(indexed)+1 {
+			return nil, fmt.Errorf("expected %d topics, got %d", len(indexed)+1, len(ethLog.Topics))
+		}
+		if err := abi.ParseTopicsIntoMap(args, indexed, ethLog.Topics[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// DecodeRevert decodes the revert data of a failed tx. Error(string) and Panic(uint256) are decoded
+// without an abi, custom errors are looked up in the named abi if name is not empty.
+func (r *ABIRegistry) DecodeRevert(name string, data []byte) (*DecodedRevert, error) {
+	if len(data) < 4 {
+		return nil, ErrUnknownSelector
+	}
+
+	switch {
+	case bytes.Equal(data[:4], revertSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil, err
+		}
+		return &DecodedRevert{Error: "Error", Args: map[string]interface{}{"reason": reason}}, nil
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) != 4+32 {
+			return nil, errors.New("invalid panic data")
+		}
+		return &DecodedRevert{Error: "Panic", Args: map[string]interface{}{"code": new(big.Int).SetBytes(data[4:])}}, nil
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSelector, hexutil.Encode(data[:4]))
+	}
+	parsed, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	customErr, err := parsed.ErrorByID(selector)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSelector, hexutil.Encode(data[:4]))
+	}
+	args := make(map[string]interface{}, len(customErr.Inputs))
+	if err := customErr.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return nil, fmt.Errorf("failed to decode %s error: %w", customErr.Name, err)
+	}
+	return &DecodedRevert{Error: customErr.Name, Args: args}, nil
+}
+
+// RevertData extracts the revert data from the error returned by eth_call or eth_estimateGas.
+func RevertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	encoded, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(encoded)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// JSONArgs converts the decoded values which do not marshal into readable json, integers
+// are kept as strings since they may exceed the precision of json numbers.
+func JSONArgs(args map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		values[name] = jsonValue(value)
+	}
+	return values
+}
+
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case []*big.Int:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = v[i].String()
+		}
+		return values
+	case common.Address:
+		return v.Hex()
+	case []common.Address:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = v[i].Hex()
+		}
+		return values
+	case common.Hash:
+		return v.Hex()
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return v
+	}
+}
+
+// Addresses returns the sorted addresses appearing in the decoded args.
+func Addresses(args map[string]interface{}) []common.Address {
+	seen := make(map[common.Address]bool)
+	for _, value := range args {
+		switch address := value.(type) {
+		case common.Address:
+			seen[address] = true
+		case []common.Address:
+			for _, a := range address {
+				seen[a] = true
+			}
+		}
+	}
+	addresses := make([]common.Address, 0, len(seen))
+	for address := range seen {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	return addresses
+}
+
+// MarshalArgs marshals the decoded args into json.
+func MarshalArgs(args map[string]interface{}) ([]byte, error) {
+	return json.Marshal(JSONArgs(args))
+}
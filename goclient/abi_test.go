@@ -0,0 +1,167 @@
+package goclient
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"account","type":"address"},{"name":"needed","type":"uint256"}]}
+]`
+
+func newTestRegistry(t *testing.T) (*ABIRegistry, abi.ABI) {
+	registry := NewABIRegistry()
+	require.NoError(t, registry.Register("token", testABI))
+	parsed, err := registry.Get("token")
+	require.NoError(t, err)
+	return registry, *parsed
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	registry, token := newTestRegistry(t)
+	to := common.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+	input, err := token.Pack("transfer", to, big.NewInt(42))
+	require.NoError(t, err)
+
+	call, err := registry.DecodeCalldata("token", input)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", call.Method)
+	require.Equal(t, to, call.Args["to"])
+	require.Equal(t, "42", JSONArgs(call.Args)["amount"])
+
+	_, err = registry.DecodeCalldata("token", []byte{0xde, 0xad, 0xbe, 0xef})
+	require.ErrorIs(t, err, ErrUnknownSelector)
+	_, err = registry.DecodeCalldata("missing", input)
+	require.ErrorIs(t, err, ErrABINotFound)
+}
+
+func TestDecodeLog(t *testing.T) {
+	registry, token := newTestRegistry(t)
+	from := common.HexToAddress("0x8997ec639d49D2F08EC0e6b858f36317680A6eE7")
+	to := common.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+	transfer := token.Events["Transfer"]
+	data, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(7))
+	require.NoError(t, err)
+
+	event, err := registry.DecodeLog("token", &types.Log{
+		Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", event.Event)
+	require.Equal(t, from, event.Args["from"])
+	require.Equal(t, to, event.Args["to"])
+	require.Equal(t, int64(7), event.Args["value"].(*big.Int).Int64())
+	require.Equal(t, []common.Address{from, to}, Addresses(event.Args))
+
+	_, err = registry.DecodeLog("token", &types.Log{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Other()"))}})
+	require.ErrorIs(t, err, ErrUnknownEvent)
+	_, err = registry.DecodeLog("token", &types.Log{Topics: []common.Hash{transfer.ID}, Data: data})
+	require.Error(t, err)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	registry, token := newTestRegistry(t)
+	account := common.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+
+	stringType, _ := abi.NewType("string", "", nil)
+	reason, err := abi.Arguments{{Type: stringType}}.Pack("not enough balance")
+	require.NoError(t, err)
+	uintType, _ := abi.NewType("uint256", "", nil)
+	code, err := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	require.NoError(t, err)
+	custom, err := token.Errors["InsufficientBalance"].Inputs.Pack(account, big.NewInt(100))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		abi     string
+		data    []byte
+		error   string
+		args    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:  "error string",
+			data:  append(append([]byte{}, revertSelector...), reason...),
+			error: "Error",
+			args:  map[string]interface{}{"reason": "not enough balance"},
+		},
+		{
+			name:  "panic",
+			data:  append(append([]byte{}, panicSelector...), code...),
+			error: "Panic",
+			args:  map[string]interface{}{"code": "17"},
+		},
+		{
+			name:  "custom error",
+			abi:   "token",
+			data:  append(token.Errors["InsufficientBalance"].ID.Bytes()[:4], custom...),
+			error: "InsufficientBalance",
+			args:  map[string]interface{}{"account": account.Hex(), "needed": "100"},
+		},
+		{
+			name:    "custom error without abi",
+			data:    append(token.Errors["InsufficientBalance"].ID.Bytes()[:4], custom...),
+			wantErr: ErrUnknownSelector,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert, err := registry.DecodeRevert(tt.abi, tt.data)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.error, revert.Error)
+			require.Equal(t, tt.args, JSONArgs(revert.Args))
+		})
+	}
+}
+
+func TestLoadABIConfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "token"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token", "token.abi"), []byte(testABI), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extra.json"), []byte(testABI), 0o644))
+	contract := common.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+
+	registry := NewABIRegistry()
+	require.NoError(t, registry.Load(&config.ABIConfig{
+		Dir:       dir,
+		Files:     map[string]string{"extra": filepath.Join(dir, "extra.json")},
+		Contracts: map[string]string{contract.Hex(): "token"},
+	}))
+	for _, name := range []string{"token", "extra"} {
+		_, err := registry.Get(name)
+		require.NoError(t, err, name)
+	}
+	name, ok := registry.ByContract(contract)
+	require.True(t, ok)
+	require.Equal(t, "token", name)
+
+	require.Error(t, registry.Load(&config.ABIConfig{Contracts: map[string]string{contract.Hex(): "missing"}}))
+
+	parsed, err := registry.Resolve(filepath.Join(dir, "extra.json"))
+	require.NoError(t, err)
+	require.Contains(t, parsed.Events, "Transfer")
+}
+
+func TestDefaultABIs(t *testing.T) {
+	for _, name := range []string{ABIAspect, ABIERC721, ABIERC1155, ABIScored} {
+		_, err := GetABIRegistry().Get(name)
+		require.NoError(t, err, name)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/artela-network/galxe-integration/config"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -87,6 +88,31 @@ func (c *Client) TransactionReceipt(ctx context.Context, hash common.Hash) (*typ
 	return c.Client.TransactionReceipt(ctx, hash)
 }
 
+// ReplayRevert replays the failed tx as a call on the state before its block, and returns the data it
+// reverted with, the data is empty if the tx did not revert with any.
+func (c *Client) ReplayRevert(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) ([]byte, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	block := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	if _, err := c.CallContract(ctx, msg, block); err != nil {
+		if data, ok := RevertData(err); ok {
+			return data, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (c *Client) Transfer(privateKey *ecdsa.PrivateKey, to common.Address, amount int64, nonce uint64, conf *config.TxConfig) (common.Hash, error) {
 	// publicKey := privateKey.Public()
 	// publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+)
+
+const importUsage = "usage: import <campaign> <file.csv|file.json> [task_id]"
+
+// runImport runs the import command, it registers the addresses of the file to the campaign and prints the
+// report. The format of the file is told by its extension, csv unless it is .json.
+func runImport(conn *sql.DB, args []string) {
+	if len(args) < 2 {
+		log.Fatal(importUsage)
+	}
+	campaign, file := args[0], args[1]
+	taskId := ""
+	if len(args) > 2 {
+		taskId = args[2]
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatalf("failed to open import file: %v", err)
+	}
+	defer f.Close()
+
+	format := biz.ImportFormatCSV
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		format = biz.ImportFormatJSON
+	}
+	rows, err := biz.ParseImport(f, format)
+	if err != nil {
+		log.Fatalf("failed to parse import file: %v", err)
+	}
+
+	report, err := biz.ImportAddresses(conn, campaign, taskId, rows)
+	if err != nil {
+		log.Fatalf("import failed: %v", err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("failed to print import report: %v", err)
+	}
+}
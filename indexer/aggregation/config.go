@@ -0,0 +1,48 @@
+package aggregation
+
+import "github.com/artela-network/galxe-integration/config"
+
+const (
+	KindCount = "count"
+	KindSum   = "sum"
+	KindHold  = "hold"
+)
+
+type Config struct {
+	Rules []*RuleConfig `json:"rules"`
+}
+
+// RuleConfig aggregates the matched events of each address, once the aggregated value reaches
+// the threshold, the task named TaskName (if any) is marked as succeeded for the address.
+//
+//   - count: number of matched events, or of successful txs sent to the contract if no event is given
+//   - sum: sum of the Field argument of the matched events, threshold in the smallest unit
+//   - hold: duration (e.g. "168h") the balance tracked from transfer events stays above MinBalance
+type RuleConfig struct {
+	Name       string `json:"name"`
+	TaskName   string `json:"task_name"`
+	Kind       string `json:"kind"`
+	Field      string `json:"field"`
+	FromField  string `json:"from_field"`
+	ToField    string `json:"to_field"`
+	Threshold  string `json:"threshold"`
+	MinBalance string `json:"min_balance"`
+	config.EventFilter
+}
+
+func (c *RuleConfig) FillDefaults() {
+	if c.Kind == KindHold {
+		if c.Field == "" {
+			c.Field = "value"
+		}
+		if c.FromField == "" {
+			c.FromField = "from"
+		}
+		if c.ToField == "" {
+			c.ToField = "to"
+		}
+		if c.MinBalance == "" {
+			c.MinBalance = "1"
+		}
+	}
+}
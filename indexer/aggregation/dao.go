@@ -0,0 +1,118 @@
+package aggregation
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type DAO interface {
+	Increment(exec common.Executor, rule, address string, amount *big.Int) (*big.Int, *big.Int, error)
+	UpdateBalance(exec common.Executor, rule, address string, delta, minBalance *big.Int, blockTime uint64) error
+	MarkCompleted(exec common.Executor, rule, address string, blockTime uint64) (bool, error)
+	GetHolders(rule string, heldSince uint64) ([]string, error)
+	CompleteHolder(exec common.Executor, rule, address string, heldSince, blockTime uint64) (bool, error)
+	GetCompletionCount(rule string) (uint64, error)
+	// Transact runs fn in a db transaction, nothing fn wrote is kept if it fails.
+	Transact(fn func(exec common.Executor) error) error
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+// Increment adds one to the counter and amount to the sum, returns the aggregated values.
+func (dao *postgresDAO) Increment(exec common.Executor, rule, address string, amount *big.Int) (*big.Int, *big.Int, error) {
+	var count int64
+	var total string
+	err := exec.QueryRow("INSERT INTO aggregation_stats (rule, address, count, total) VALUES ($1, $2, 1, $3::NUMERIC) "+
+		"ON CONFLICT (rule, address) DO UPDATE SET count = aggregation_stats.count + 1, total = aggregation_stats.total + EXCLUDED.total "+
+		"RETURNING count, total::TEXT", rule, address, amount.String()).Scan(&count, &total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sum, ok := new(big.Int).SetString(total, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid total %s", total)
+	}
+	return big.NewInt(count), sum, nil
+}
+
+// UpdateBalance applies the balance change, the hold timer starts when the balance reaches
+// minBalance and is cleared once it drops below.
+func (dao *postgresDAO) UpdateBalance(exec common.Executor, rule, address string, delta, minBalance *big.Int, blockTime uint64) error {
+	_, err := exec.Exec("INSERT INTO aggregation_stats (rule, address, balance, hold_since) "+
+		"VALUES ($1, $2, $3::NUMERIC, CASE WHEN $3::NUMERIC >= $4::NUMERIC THEN $5::BIGINT END) "+
+		"ON CONFLICT (rule, address) DO UPDATE SET balance = aggregation_stats.balance + EXCLUDED.balance, "+
+		"hold_since = CASE WHEN aggregation_stats.balance + EXCLUDED.balance >= $4::NUMERIC THEN COALESCE(aggregation_stats.hold_since, $5::BIGINT) END",
+		rule, address, delta.String(), minBalance.String(), blockTime)
+	return err
+}
+
+func (dao *postgresDAO) MarkCompleted(exec common.Executor, rule, address string, blockTime uint64) (bool, error) {
+	res, err := exec.Exec("UPDATE aggregation_stats SET completed_at = $1 WHERE rule = $2 AND address = $3 AND completed_at IS NULL",
+		blockTime, rule, address)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// GetHolders returns the addresses not completed yet which hold since heldSince or earlier.
+func (dao *postgresDAO) GetHolders(rule string, heldSince uint64) ([]string, error) {
+	rows, err := dao.conn.Query("SELECT address FROM aggregation_stats "+
+		"WHERE rule = $1 AND completed_at IS NULL AND hold_since IS NOT NULL AND hold_since <= $2", rule, heldSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []string
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// CompleteHolder marks the address as completed if it still holds since heldSince or earlier.
+func (dao *postgresDAO) CompleteHolder(exec common.Executor, rule, address string, heldSince, blockTime uint64) (bool, error) {
+	res, err := exec.Exec("UPDATE aggregation_stats SET completed_at = $1 WHERE rule = $2 AND address = $3 "+
+		"AND completed_at IS NULL AND hold_since IS NOT NULL AND hold_since <= $4", blockTime, rule, address, heldSince)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (dao *postgresDAO) GetCompletionCount(rule string) (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM aggregation_stats WHERE rule = $1 AND completed_at IS NOT NULL", rule).Scan(&count)
+	return count, err
+}
+
+func (dao *postgresDAO) Transact(fn func(exec common.Executor) error) error {
+	tx, err := dao.conn.Begin()
+	if err != nil {
+		return err
+	}
+	// Defer a rollback in case anything fails.
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,436 @@
+package aggregation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer"
+	"github.com/artela-network/galxe-integration/indexer/ledger"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	IndexerName = "Aggregation"
+
+	holdCheckInterval = time.Minute
+)
+
+type rule struct {
+	name       string
+	taskName   string
+	kind       string
+	field      string
+	fromField  string
+	toField    string
+	threshold  *big.Int
+	hold       time.Duration
+	minBalance *big.Int
+
+	// matcher is nil for rules counting txs sent to contract
+	matcher  *indexer.EventMatcher
+	contract eth.Address
+}
+
+func newAggregationIndexer(ctx context.Context, conf *config.IndexerConfig, driver string, db *sql.DB) (common.Indexer, error) {
+	if err := indexer.RequirePostgres(IndexerName, driver); err != nil {
+		return nil, err
+	}
+	aggIndexer, err := newIndexer(ctx, conf, db, newPostgresDAO(db), ledger.NewLedger(db))
+	if err != nil {
+		return nil, err
+	}
+	return aggIndexer, nil
+}
+
+func newIndexer(ctx context.Context, conf *config.IndexerConfig, db *sql.DB, dao DAO, processed ledger.Ledger) (*aggregationIndexer, error) {
+	aggConf := &Config{}
+	if err := json.Unmarshal(conf.Options, aggConf); err != nil {
+		return nil, fmt.Errorf("failed to parse aggregation indexer options: %w", err)
+	}
+
+	rules := make([]*rule, 0, len(aggConf.Rules))
+	for _, rc := range aggConf.Rules {
+		r, err := newRule(rc)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+
+	if conf.Thread == 0 {
+		conf.Thread = uint64(runtime.NumCPU())*2 + 1
+	}
+
+	aggIndexer := &aggregationIndexer{
+		db:     db,
+		dao:    dao,
+		ledger: processed,
+		rules:  rules,
+	}
+	aggIndexer.Runner = indexer.NewRunner(ctx, IndexerName, conf.Thread, aggIndexer.process)
+	aggIndexer.Go(aggIndexer.checkHolders)
+
+	return aggIndexer, nil
+}
+
+func newRule(conf *RuleConfig) (*rule, error) {
+	conf.FillDefaults()
+	if conf.Name == "" {
+		return nil, errors.New("aggregation rule must have a name")
+	}
+
+	r := &rule{
+		name:      conf.Name,
+		taskName:  conf.TaskName,
+		kind:      conf.Kind,
+		field:     conf.Field,
+		fromField: conf.FromField,
+		toField:   conf.ToField,
+	}
+
+	switch conf.Kind {
+	case KindCount, KindSum:
+		threshold, ok := new(big.Int).SetString(conf.Threshold, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid threshold %q of rule %s", conf.Threshold, conf.Name)
+		}
+		r.threshold = threshold
+	case KindHold:
+		hold, err := time.ParseDuration(conf.Threshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hold duration %q of rule %s: %w", conf.Threshold, conf.Name, err)
+		}
+		minBalance, ok := new(big.Int).SetString(conf.MinBalance, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid min balance %q of rule %s", conf.MinBalance, conf.Name)
+		}
+		r.hold = hold
+		r.minBalance = minBalance
+	default:
+		return nil, fmt.Errorf("unknown kind %q of rule %s", conf.Kind, conf.Name)
+	}
+
+	if conf.Event == "" {
+		if conf.Kind != KindCount {
+			return nil, fmt.Errorf("rule %s must have an event", conf.Name)
+		}
+		if conf.Contract != "" {
+			r.contract = eth.HexToAddress(conf.Contract)
+		}
+		return r, nil
+	}
+
+	matcher, err := indexer.NewEventMatcher(&conf.EventFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event of rule %s: %w", conf.Name, err)
+	}
+	r.matcher = matcher
+	return r, nil
+}
+
+type aggregationIndexer struct {
+	*indexer.Runner
+	db     *sql.DB
+	dao    DAO
+	ledger ledger.Ledger
+	rules  []*rule
+
+	// time of the latest block seen, hold durations are measured in chain time
+	latestBlockTime atomic.Uint64
+}
+
+func (a *aggregationIndexer) process(eventCtx *common.EventContext) error {
+	blockTime := eventCtx.BlockHeader.Time
+	for {
+		latest := a.latestBlockTime.Load()
+		if blockTime <= latest || a.latestBlockTime.CompareAndSwap(latest, blockTime) {
+			break
+		}
+	}
+
+	tx := eventCtx.Transaction
+	var txRules []*rule
+	for _, r := range a.rules {
+		if r.matcher == nil && a.countable(r, eventCtx) {
+			txRules = append(txRules, r)
+		}
+	}
+	if len(txRules) > 0 {
+		sender, err := indexer.TxSender(tx)
+		if err != nil {
+			log.Error("[aggregation indexer] failed to recover tx sender", err)
+			return err
+		}
+
+		key := ledger.NewKey(eventCtx.ChainID, tx.Hash(), ledger.TxLevel, IndexerName)
+		err = a.apply(key, eventCtx, func(exec common.Executor) ([]*completion, error) {
+			var completions []*completion
+			for _, r := range txRules {
+				c, err := a.accumulate(exec, r, sender.Hex(), new(big.Int), blockTime)
+				if err != nil {
+					return nil, err
+				}
+				if c != nil {
+					completions = append(completions, c)
+				}
+			}
+			return completions, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ethLog := range eventCtx.Receipt.Logs {
+		var logRules []*rule
+		for _, r := range a.rules {
+			if r.matcher != nil && r.matcher.Match(ethLog) {
+				logRules = append(logRules, r)
+			}
+		}
+		if len(logRules) == 0 {
+			continue
+		}
+
+		key := ledger.NewKey(eventCtx.ChainID, ethLog.TxHash, int64(ethLog.Index), IndexerName)
+		err := a.apply(key, eventCtx, func(exec common.Executor) ([]*completion, error) {
+			var completions []*completion
+			for _, r := range logRules {
+				args, err := r.matcher.Decode(ethLog)
+				if err != nil {
+					log.Errorf("[aggregation indexer] failed to decode %s event: %v", r.matcher.EventName(), err)
+					return nil, err
+				}
+
+				if r.kind == KindHold {
+					if err := a.transfer(exec, r, args, blockTime); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
+				c, err := a.aggregate(exec, r, tx, args, blockTime)
+				if err != nil {
+					return nil, err
+				}
+				if c != nil {
+					completions = append(completions, c)
+				}
+			}
+			return completions, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type completion struct {
+	rule    *rule
+	address string
+}
+
+// apply runs fn exactly once for the key, the aggregated values and the completed tasks are
+// written in the same db transaction as the ledger record.
+func (a *aggregationIndexer) apply(key ledger.Key, eventCtx *common.EventContext, fn func(exec common.Executor) ([]*completion, error)) error {
+	_, err := a.ledger.Apply(key, eventCtx.BlockHeader.Number.Uint64(), func(exec common.Executor) error {
+		completions, err := fn(exec)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range completions {
+			log.Infof("[aggregation indexer] %s completed rule %s", c.address, c.rule.name)
+			if c.rule.taskName == "" {
+				continue
+			}
+			if _, err := biz.CompleteTaskByName(exec, c.address, c.rule.taskName, eventCtx.Transaction.Hash().Hex()); err != nil {
+				log.Error("[aggregation indexer] failed to complete task", err)
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (a *aggregationIndexer) countable(r *rule, eventCtx *common.EventContext) bool {
+	tx := eventCtx.Transaction
+	if eventCtx.Receipt.Status != types.ReceiptStatusSuccessful {
+		return false
+	}
+	return (r.contract == eth.Address{}) || (tx.To() != nil && *tx.To() == r.contract)
+}
+
+func (a *aggregationIndexer) aggregate(exec common.Executor, r *rule, tx *types.Transaction, args map[string]interface{}, blockTime uint64) (*completion, error) {
+	address, err := r.matcher.Address(tx, args)
+	if err != nil {
+		log.Errorf("[aggregation indexer] failed to get address of %s event: %v", r.matcher.EventName(), err)
+		return nil, err
+	}
+
+	amount := new(big.Int)
+	if r.kind == KindSum {
+		value, ok := args[r.field].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("field %s of %s event is not an integer", r.field, r.matcher.EventName())
+		}
+		amount = value
+	}
+
+	return a.accumulate(exec, r, address.Hex(), amount, blockTime)
+}
+
+func (a *aggregationIndexer) accumulate(exec common.Executor, r *rule, address string, amount *big.Int, blockTime uint64) (*completion, error) {
+	count, total, err := a.dao.Increment(exec, r.name, address, amount)
+	if err != nil {
+		log.Error("[aggregation indexer] failed to increment stats", err)
+		return nil, err
+	}
+
+	value := count
+	if r.kind == KindSum {
+		value = total
+	}
+	if value.Cmp(r.threshold) < 0 {
+		return nil, nil
+	}
+
+	completed, err := a.dao.MarkCompleted(exec, r.name, address, blockTime)
+	if err != nil {
+		log.Error("[aggregation indexer] failed to mark completed", err)
+		return nil, err
+	}
+	if !completed {
+		return nil, nil
+	}
+	return &completion{rule: r, address: address}, nil
+}
+
+func (a *aggregationIndexer) transfer(exec common.Executor, r *rule, args map[string]interface{}, blockTime uint64) error {
+	from, okFrom := args[r.fromField].(eth.Address)
+	to, okTo := args[r.toField].(eth.Address)
+	value, okValue := args[r.field].(*big.Int)
+	if !okFrom || !okTo || !okValue {
+		return fmt.Errorf("transfer event of rule %s does not have %s, %s and %s", r.name, r.fromField, r.toField, r.field)
+	}
+
+	// mints and burns only change the balance of the other side
+	if (from != eth.Address{}) {
+		if err := a.dao.UpdateBalance(exec, r.name, from.Hex(), new(big.Int).Neg(value), r.minBalance, blockTime); err != nil {
+			log.Error("[aggregation indexer] failed to update balance", err)
+			return err
+		}
+	}
+	if (to != eth.Address{}) {
+		if err := a.dao.UpdateBalance(exec, r.name, to.Hex(), value, r.minBalance, blockTime); err != nil {
+			log.Error("[aggregation indexer] failed to update balance", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// checkHolders periodically completes the hold rules, since holding does not emit any event
+func (a *aggregationIndexer) checkHolders(ctx context.Context) {
+	ticker := time.NewTicker(holdCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("[aggregation indexer] hold checker stopped")
+			return
+		case <-ticker.C:
+			blockTime := a.latestBlockTime.Load()
+			if blockTime == 0 {
+				continue
+			}
+
+			for _, r := range a.rules {
+				if r.kind != KindHold {
+					continue
+				}
+
+				if err := a.completeHolders(r, blockTime); err != nil {
+					// the holders left are completed on the next check
+					log.Errorf("[aggregation indexer] failed to complete holders of rule %s: %v", r.name, err)
+				}
+			}
+		}
+	}
+}
+
+// completeHolders completes the addresses holding for the hold of the rule at the block time. Each address
+// is marked completed and its task completed in the same db transaction, so a failed address is not marked
+// and is completed again on the next check.
+func (a *aggregationIndexer) completeHolders(r *rule, blockTime uint64) error {
+	holdSeconds := uint64(r.hold.Seconds())
+	if blockTime < holdSeconds {
+		return nil
+	}
+	heldSince := blockTime - holdSeconds
+	addresses, err := a.dao.GetHolders(r.name, heldSince)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range addresses {
+		err := a.dao.Transact(func(exec common.Executor) error {
+			completed, err := a.dao.CompleteHolder(exec, r.name, address, heldSince, blockTime)
+			if err != nil || !completed {
+				return err
+			}
+			log.Infof("[aggregation indexer] %s completed rule %s", address, r.name)
+			if r.taskName == "" {
+				return nil
+			}
+			_, err = biz.CompleteTaskByName(exec, address, r.taskName, "")
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("complete holder %s: %w", address, err)
+		}
+	}
+	return nil
+}
+
+func (a *aggregationIndexer) Metrics() interface{} {
+	completions := make(map[string]uint64, len(a.rules))
+	for _, r := range a.rules {
+		count, err := a.dao.GetCompletionCount(r.name)
+		if err != nil {
+			log.Error("[aggregation indexer] failed to get completion count", err)
+			continue
+		}
+		completions[r.name] = count
+	}
+
+	return struct {
+		WaitingTx       int               `json:"waiting_tx"`
+		LatestBlockTime uint64            `json:"latest_block_time"`
+		Completions     map[string]uint64 `json:"completions"`
+	}{
+		WaitingTx:       a.Health().WaitingTx,
+		LatestBlockTime: a.latestBlockTime.Load(),
+		Completions:     completions,
+	}
+}
+
+func (a *aggregationIndexer) Name() string {
+	return IndexerName
+}
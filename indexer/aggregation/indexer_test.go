@@ -0,0 +1,202 @@
+package aggregation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *RuleConfig
+		wantErr bool
+	}{
+		{
+			name: "tx count",
+			conf: &RuleConfig{Name: "txs", Kind: KindCount, Threshold: "10"},
+		},
+		{
+			name:    "missing name",
+			conf:    &RuleConfig{Kind: KindCount, Threshold: "10"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid threshold",
+			conf:    &RuleConfig{Name: "txs", Kind: KindCount, Threshold: "ten"},
+			wantErr: true,
+		},
+		{
+			name:    "sum without event",
+			conf:    &RuleConfig{Name: "volume", Kind: KindSum, Threshold: "100"},
+			wantErr: true,
+		},
+		{
+			name:    "hold with invalid duration",
+			conf:    &RuleConfig{Name: "lp", Kind: KindHold, Threshold: "7 days"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			conf:    &RuleConfig{Name: "avg", Kind: "avg", Threshold: "1"},
+			wantErr: true,
+		},
+		{
+			name: "missing abi",
+			conf: &RuleConfig{Name: "volume", Kind: KindSum, Threshold: "100", EventFilter: config.EventFilter{
+				Contract: "0xa646F6607af459917EFc14957bADC0Eb87f6dA7c", ABI: "not-exist.abi", Event: "Swap",
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newRule(tt.conf)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Nil(t, r.matcher)
+		})
+	}
+}
+
+func TestHoldRuleDefaults(t *testing.T) {
+	r, err := newRule(&RuleConfig{Name: "lp", Kind: KindHold, Threshold: "168h", EventFilter: config.EventFilter{
+		Contract: "0xa646F6607af459917EFc14957bADC0Eb87f6dA7c", ABI: "../../contracts/rug/rug.abi", Event: "Transfer",
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 7*24*time.Hour, r.hold)
+	require.Equal(t, "value", r.field)
+	require.Equal(t, "from", r.fromField)
+	require.Equal(t, "to", r.toField)
+	require.Equal(t, int64(1), r.minBalance.Int64())
+}
+
+func TestAggregationIndexer(t *testing.T) {
+	token := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+	pool := eth.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	transfer := indexertest.LoadABI("../../contracts/rug/rug.abi").Events["Transfer"]
+	transferFilter := config.EventFilter{Contract: token.Hex(), ABI: "../../contracts/rug/rug.abi", Event: "Transfer", AddressField: "from"}
+
+	tests := []struct {
+		name     string
+		rule     *RuleConfig
+		txs      func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext
+		complete bool
+	}{
+		{
+			name: "tx count reached",
+			rule: &RuleConfig{Name: "txs", Kind: KindCount, Threshold: "2", EventFilter: config.EventFilter{Contract: token.Hex()}},
+			txs: func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				return []*common.EventContext{block.Tx(user, token, nil), block.Tx(user, token, nil)}
+			},
+			complete: true,
+		},
+		{
+			name: "failed txs not counted",
+			rule: &RuleConfig{Name: "txs", Kind: KindCount, Threshold: "2", EventFilter: config.EventFilter{Contract: token.Hex()}},
+			txs: func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				return []*common.EventContext{block.Tx(user, token, nil), block.FailedTx(user, token, nil)}
+			},
+		},
+		{
+			name: "txs to other contracts not counted",
+			rule: &RuleConfig{Name: "txs", Kind: KindCount, Threshold: "2", EventFilter: config.EventFilter{Contract: token.Hex()}},
+			txs: func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				return []*common.EventContext{block.Tx(user, token, nil), block.Tx(user, pool, nil)}
+			},
+		},
+		{
+			name: "replayed tx counted once",
+			rule: &RuleConfig{Name: "txs", Kind: KindCount, Threshold: "2", EventFilter: config.EventFilter{Contract: token.Hex()}},
+			txs: func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext {
+				tx := chain.NextBlock(1).Tx(user, token, nil)
+				return []*common.EventContext{tx, tx}
+			},
+		},
+		{
+			name: "sum reached",
+			rule: &RuleConfig{Name: "volume", Kind: KindSum, Field: "value", Threshold: "100", EventFilter: transferFilter},
+			txs: func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				return []*common.EventContext{
+					block.Tx(user, token, nil, indexertest.Log(token, transfer, user.Address, pool, big.NewInt(60))),
+					block.Tx(user, token, nil, indexertest.Log(token, transfer, user.Address, pool, big.NewInt(40))),
+				}
+			},
+			complete: true,
+		},
+		{
+			name: "sum not reached",
+			rule: &RuleConfig{Name: "volume", Kind: KindSum, Field: "value", Threshold: "100", EventFilter: transferFilter},
+			txs: func(chain *indexertest.Chain, user *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				return []*common.EventContext{
+					block.Tx(user, token, nil, indexertest.Log(token, transfer, user.Address, pool, big.NewInt(60))),
+					block.Tx(user, token, nil, indexertest.Log(token, transfer, pool, user.Address, big.NewInt(40))),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := json.Marshal(&Config{Rules: []*RuleConfig{tt.rule}})
+			require.NoError(t, err)
+
+			dao := newMemoryDAO()
+			aggIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1, Options: options}, nil, dao, indexertest.NewLedger())
+			require.NoError(t, err)
+			indexertest.Start(t, aggIndexer)
+
+			user := indexertest.NewAccount()
+			indexertest.MustFeed(t, aggIndexer, tt.txs(indexertest.NewChain(), user)...)
+			require.Equal(t, tt.complete, dao.completed(tt.rule.Name, user.Address.Hex()))
+		})
+	}
+}
+
+func TestAggregationIndexerHoldBalance(t *testing.T) {
+	token := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+	transfer := indexertest.LoadABI("../../contracts/rug/rug.abi").Events["Transfer"]
+	options, err := json.Marshal(&Config{Rules: []*RuleConfig{{Name: "hodl", Kind: KindHold, Threshold: "1h", MinBalance: "10",
+		EventFilter: config.EventFilter{Contract: token.Hex(), ABI: "../../contracts/rug/rug.abi", Event: "Transfer"}}}})
+	require.NoError(t, err)
+
+	dao := newMemoryDAO()
+	aggIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1, Options: options}, nil, dao, indexertest.NewLedger())
+	require.NoError(t, err)
+	indexertest.Start(t, aggIndexer)
+
+	chain := indexertest.NewChain()
+	alice, bob := indexertest.NewAccount(), indexertest.NewAccount()
+	mint := chain.NextBlock(1).Tx(alice, token, nil, indexertest.Log(token, transfer, eth.Address{}, alice.Address, big.NewInt(15)))
+	send := chain.NextBlock(60).Tx(alice, token, nil, indexertest.Log(token, transfer, alice.Address, bob.Address, big.NewInt(10)))
+	indexertest.MustFeed(t, aggIndexer, mint, send, send)
+
+	// alice dropped below the min balance, bob holds since the transfer
+	holders, err := dao.GetHolders("hodl", send.BlockHeader.Time)
+	require.NoError(t, err)
+	require.Equal(t, []string{bob.Address.Hex()}, holders)
+
+	// bob is completed once it held for the hold of the rule
+	require.NoError(t, aggIndexer.completeHolders(aggIndexer.rules[0], send.BlockHeader.Time+3599))
+	require.False(t, dao.completed("hodl", bob.Address.Hex()))
+	require.NoError(t, aggIndexer.completeHolders(aggIndexer.rules[0], send.BlockHeader.Time+3600))
+	require.True(t, dao.completed("hodl", bob.Address.Hex()))
+	require.False(t, dao.completed("hodl", alice.Address.Hex()))
+}
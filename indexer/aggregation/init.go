@@ -0,0 +1,7 @@
+package aggregation
+
+import "github.com/artela-network/galxe-integration/indexer"
+
+func init() {
+	indexer.GetRegistry().Register(IndexerName, newAggregationIndexer)
+}
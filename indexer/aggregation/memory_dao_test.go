@@ -0,0 +1,140 @@
+package aggregation
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type memoryStat struct {
+	count       int64
+	total       *big.Int
+	balance     *big.Int
+	holdSince   *uint64
+	completedAt *uint64
+}
+
+type memoryDAO struct {
+	mu    sync.Mutex
+	stats map[string]*memoryStat
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{stats: make(map[string]*memoryStat)}
+}
+
+func (dao *memoryDAO) stat(rule, address string) *memoryStat {
+	key := rule + "/" + address
+	s, ok := dao.stats[key]
+	if !ok {
+		s = &memoryStat{total: new(big.Int), balance: new(big.Int)}
+		dao.stats[key] = s
+	}
+	return s
+}
+
+func (dao *memoryDAO) Increment(_ common.Executor, rule, address string, amount *big.Int) (*big.Int, *big.Int, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	s := dao.stat(rule, address)
+	s.count++
+	s.total.Add(s.total, amount)
+	return big.NewInt(s.count), new(big.Int).Set(s.total), nil
+}
+
+func (dao *memoryDAO) UpdateBalance(_ common.Executor, rule, address string, delta, minBalance *big.Int, blockTime uint64) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	s := dao.stat(rule, address)
+	s.balance.Add(s.balance, delta)
+	if s.balance.Cmp(minBalance) < 0 {
+		s.holdSince = nil
+	} else if s.holdSince == nil {
+		s.holdSince = &blockTime
+	}
+	return nil
+}
+
+func (dao *memoryDAO) MarkCompleted(_ common.Executor, rule, address string, blockTime uint64) (bool, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	s := dao.stat(rule, address)
+	if s.completedAt != nil {
+		return false, nil
+	}
+	s.completedAt = &blockTime
+	return true, nil
+}
+
+func (dao *memoryDAO) GetHolders(rule string, heldSince uint64) ([]string, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var addresses []string
+	for key, s := range dao.stats {
+		address, ok := strings.CutPrefix(key, rule+"/")
+		if !ok || s.completedAt != nil || s.holdSince == nil || *s.holdSince > heldSince {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+func (dao *memoryDAO) CompleteHolder(_ common.Executor, rule, address string, heldSince, blockTime uint64) (bool, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	s, ok := dao.stats[rule+"/"+address]
+	if !ok || s.completedAt != nil || s.holdSince == nil || *s.holdSince > heldSince {
+		return false, nil
+	}
+	s.completedAt = &blockTime
+	return true, nil
+}
+
+func (dao *memoryDAO) GetCompletionCount(rule string) (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var count uint64
+	for key, s := range dao.stats {
+		if strings.HasPrefix(key, rule+"/") && s.completedAt != nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Transact calls fn with a nil executor, the completions are restored if it fails.
+func (dao *memoryDAO) Transact(fn func(exec common.Executor) error) error {
+	dao.mu.Lock()
+	completedAt := make(map[string]*uint64, len(dao.stats))
+	for key, s := range dao.stats {
+		completedAt[key] = s.completedAt
+	}
+	dao.mu.Unlock()
+
+	if err := fn(nil); err != nil {
+		dao.mu.Lock()
+		for key, s := range dao.stats {
+			s.completedAt = completedAt[key]
+		}
+		dao.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (dao *memoryDAO) completed(rule, address string) bool {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	s, ok := dao.stats[rule+"/"+address]
+	return ok && s.completedAt != nil
+}
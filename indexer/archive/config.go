@@ -0,0 +1,12 @@
+package archive
+
+type Config struct {
+	Contracts []*ContractConfig `json:"contracts"`
+}
+
+// ContractConfig is a watched contract, every event of it defined in the ABI file is archived.
+type ContractConfig struct {
+	Name     string `json:"name"`
+	Contract string `json:"contract"`
+	ABI      string `json:"abi"`
+}
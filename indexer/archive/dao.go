@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// Event is a decoded log of a watched contract. Addresses holds the tx sender and every address
+// argument of the event, so the events related to an address can be looked up.
+type Event struct {
+	Contract    string
+	Name        string
+	Args        []byte
+	Addresses   []string
+	TxHash      string
+	LogIndex    uint
+	BlockNumber uint64
+	BlockHash   string
+	BlockTime   uint64
+}
+
+type DAO interface {
+	AddEvent(event *Event) error
+	GetEventCount() (uint64, error)
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+func (dao *postgresDAO) AddEvent(event *Event) error {
+	// we may receive duplicate logs when blocks are retried, need to ignore the conflicts
+	_, err := dao.conn.Exec("INSERT INTO archived_events (contract, event, args, addresses, tx_hash, log_index, block_number, block_hash, block_time) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (tx_hash, log_index) DO NOTHING",
+		event.Contract, event.Name, string(event.Args), pq.Array(event.Addresses), event.TxHash, event.LogIndex,
+		event.BlockNumber, event.BlockHash, event.BlockTime)
+	return err
+}
+
+func (dao *postgresDAO) GetEventCount() (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM archived_events").Scan(&count)
+	return count, err
+}
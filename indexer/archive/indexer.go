@@ -0,0 +1,163 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/goclient"
+	"github.com/artela-network/galxe-integration/indexer"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const IndexerName = "Archive"
+
+type contract struct {
+	name string
+	abi  string
+}
+
+func newArchiveIndexer(ctx context.Context, conf *config.IndexerConfig, driver string, db *sql.DB) (common.Indexer, error) {
+	if err := indexer.RequirePostgres(IndexerName, driver); err != nil {
+		return nil, err
+	}
+	archiveIndexer, err := newIndexer(ctx, conf, newPostgresDAO(db))
+	if err != nil {
+		return nil, err
+	}
+	return archiveIndexer, nil
+}
+
+func newIndexer(ctx context.Context, conf *config.IndexerConfig, dao DAO) (*archiveIndexer, error) {
+	archiveConf := &Config{}
+	if err := json.Unmarshal(conf.Options, archiveConf); err != nil {
+		return nil, fmt.Errorf("failed to parse archive indexer options: %w", err)
+	}
+
+	contracts := make(map[eth.Address]*contract, len(archiveConf.Contracts))
+	for _, cc := range archiveConf.Contracts {
+		if !eth.IsHexAddress(cc.Contract) {
+			return nil, fmt.Errorf("invalid contract address %q of %s", cc.Contract, cc.Name)
+		}
+		if _, err := goclient.GetABIRegistry().Resolve(cc.ABI); err != nil {
+			return nil, err
+		}
+		contracts[eth.HexToAddress(cc.Contract)] = &contract{name: cc.Name, abi: cc.ABI}
+	}
+	if len(contracts) == 0 {
+		return nil, errors.New("archive indexer must watch at least one contract")
+	}
+
+	if conf.Thread == 0 {
+		conf.Thread = uint64(runtime.NumCPU())*2 + 1
+	}
+
+	archiveIndexer := &archiveIndexer{
+		dao:       dao,
+		contracts: contracts,
+	}
+	archiveIndexer.Runner = indexer.NewRunner(ctx, IndexerName, conf.Thread, archiveIndexer.process)
+
+	return archiveIndexer, nil
+}
+
+type archiveIndexer struct {
+	*indexer.Runner
+	dao       DAO
+	contracts map[eth.Address]*contract
+}
+
+func (a *archiveIndexer) process(eventCtx *common.EventContext) error {
+	var sender *eth.Address
+	for _, ethLog := range eventCtx.Receipt.Logs {
+		c, ok := a.contracts[ethLog.Address]
+		if !ok || len(ethLog.Topics) == 0 {
+			continue
+		}
+		event, err := goclient.GetABIRegistry().DecodeLog(c.abi, ethLog)
+		if errors.Is(err, goclient.ErrUnknownEvent) {
+			log.Debugf("[archive indexer] unknown event %s of %s", ethLog.Topics[0].Hex(), c.name)
+			continue
+		}
+		if err != nil {
+			log.Errorf("[archive indexer] failed to decode event of %s in tx %s: %v", c.name, ethLog.TxHash.Hex(), err)
+			return err
+		}
+
+		if sender == nil {
+			from, err := indexer.TxSender(eventCtx.Transaction)
+			if err != nil {
+				log.Error("[archive indexer] failed to recover tx sender", err)
+				return err
+			}
+			sender = &from
+		}
+
+		archived, err := archive(event, ethLog, *sender)
+		if err != nil {
+			log.Errorf("[archive indexer] failed to archive %s event of %s in tx %s: %v", event.Event, c.name, ethLog.TxHash.Hex(), err)
+			return err
+		}
+		archived.BlockNumber = eventCtx.BlockHeader.Number.Uint64()
+		archived.BlockHash = eventCtx.BlockHeader.Hash().Hex()
+		archived.BlockTime = eventCtx.BlockHeader.Time
+
+		if err := a.dao.AddEvent(archived); err != nil {
+			log.Error("[archive indexer] failed to add event", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// archive builds the archived event, the event is related to the tx sender and every address in its args
+func archive(event *goclient.DecodedEvent, ethLog *types.Log, sender eth.Address) (*Event, error) {
+	addresses := []string{sender.Hex()}
+	for _, address := range goclient.Addresses(event.Args) {
+		if address != sender {
+			addresses = append(addresses, address.Hex())
+		}
+	}
+	sort.Strings(addresses)
+
+	raw, err := goclient.MarshalArgs(event.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Contract:  ethLog.Address.Hex(),
+		Name:      event.Event,
+		Args:      raw,
+		Addresses: addresses,
+		TxHash:    ethLog.TxHash.Hex(),
+		LogIndex:  ethLog.Index,
+	}, nil
+}
+
+func (a *archiveIndexer) Metrics() interface{} {
+	count, err := a.dao.GetEventCount()
+	if err != nil {
+		log.Error("[archive indexer] failed to get event count", err)
+	}
+
+	return struct {
+		WaitingTx      int    `json:"waiting_tx"`
+		ArchivedEvents uint64 `json:"archived_events"`
+	}{
+		WaitingTx:      a.Health().WaitingTx,
+		ArchivedEvents: count,
+	}
+}
+
+func (a *archiveIndexer) Name() string {
+	return IndexerName
+}
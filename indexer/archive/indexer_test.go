@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = "../../contracts/rug/rug.abi"
+
+func TestArchiveIndexer(t *testing.T) {
+	token := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+	other := eth.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	receiver := eth.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	tokenABI := indexertest.LoadABI(testABI)
+
+	tests := []struct {
+		name      string
+		logs      func(sender eth.Address) []*types.Log
+		event     string
+		args      map[string]interface{}
+		addresses func(sender eth.Address) []string
+	}{
+		{
+			name: "transfer",
+			logs: func(sender eth.Address) []*types.Log {
+				return []*types.Log{indexertest.Log(token, tokenABI.Events["Transfer"], sender, receiver, big.NewInt(1000))}
+			},
+			event: "Transfer",
+			args:  map[string]interface{}{"from": "", "to": receiver.Hex(), "value": "1000"},
+			addresses: func(sender eth.Address) []string {
+				return []string{sender.Hex(), receiver.Hex()}
+			},
+		},
+		{
+			name: "minter added by others",
+			logs: func(sender eth.Address) []*types.Log {
+				return []*types.Log{indexertest.Log(token, tokenABI.Events["MinterAdded"], receiver)}
+			},
+			event: "MinterAdded",
+			args:  map[string]interface{}{"account": receiver.Hex()},
+			addresses: func(sender eth.Address) []string {
+				return []string{sender.Hex(), receiver.Hex()}
+			},
+		},
+		{
+			name: "not watched contract",
+			logs: func(sender eth.Address) []*types.Log {
+				return []*types.Log{indexertest.Log(other, tokenABI.Events["Transfer"], sender, receiver, big.NewInt(1000))}
+			},
+		},
+		{
+			name: "unknown event",
+			logs: func(sender eth.Address) []*types.Log {
+				return []*types.Log{{Address: token, Topics: []eth.Hash{eth.HexToHash("0x01")}}}
+			},
+		},
+	}
+
+	options, err := json.Marshal(&Config{Contracts: []*ContractConfig{{Name: "rug", Contract: token.Hex(), ABI: testABI}}})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := newMemoryDAO()
+			archiveIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1, Options: options}, dao)
+			require.NoError(t, err)
+			indexertest.Start(t, archiveIndexer)
+
+			user := indexertest.NewAccount()
+			tx := indexertest.NewChain().NextBlock(1).Tx(user, token, nil, tt.logs(user.Address)...)
+			indexertest.MustFeed(t, archiveIndexer, tx, tx)
+
+			events := dao.list()
+			if tt.event == "" {
+				require.Empty(t, events)
+				return
+			}
+			require.Len(t, events, 1)
+			require.Equal(t, tt.event, events[0].Name)
+			require.Equal(t, token.Hex(), events[0].Contract)
+			require.Equal(t, tx.BlockHeader.Number.Uint64(), events[0].BlockNumber)
+
+			if _, ok := tt.args["from"]; ok {
+				tt.args["from"] = user.Address.Hex()
+			}
+			args := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(events[0].Args, &args))
+			require.Equal(t, tt.args, args)
+
+			addresses := tt.addresses(user.Address)
+			sort.Strings(addresses)
+			require.Equal(t, addresses, events[0].Addresses)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package archive
+
+import (
+	"fmt"
+	"sync"
+)
+
+type memoryDAO struct {
+	mu     sync.Mutex
+	events map[string]*Event
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{events: make(map[string]*Event)}
+}
+
+func (dao *memoryDAO) AddEvent(event *Event) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	dao.events[fmt.Sprintf("%s/%d", event.TxHash, event.LogIndex)] = event
+	return nil
+}
+
+func (dao *memoryDAO) GetEventCount() (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	return uint64(len(dao.events)), nil
+}
+
+func (dao *memoryDAO) list() []*Event {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	events := make([]*Event, 0, len(dao.events))
+	for _, event := range dao.events {
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,6 @@
+package aspect
+
+type Config struct {
+	// TaskName is marked as succeeded once an address has bound an Aspect deployed by itself
+	TaskName string `json:"task_name"`
+}
@@ -0,0 +1,86 @@
+package aspect
+
+import (
+	"database/sql"
+)
+
+type Deployment struct {
+	AspectID    string `json:"aspectId"`
+	Deployer    string `json:"deployer"`
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+	BlockTime   uint64 `json:"blockTime"`
+}
+
+type Binding struct {
+	AspectID    string `json:"aspectId"`
+	Contract    string `json:"contract"`
+	Binder      string `json:"binder"`
+	Version     uint64 `json:"version"`
+	Priority    int8   `json:"priority"`
+	Bound       bool   `json:"bound"`
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+	BlockTime   uint64 `json:"blockTime"`
+}
+
+type DAO interface {
+	AddDeployment(deployment *Deployment) error
+	SaveBinding(binding *Binding) error
+	Unbind(aspectID, contract, txHash string, blockNumber uint64) error
+	HasBoundOwnAspect(address string) (bool, error)
+	GetDeploymentCount() (uint64, error)
+	GetBindingCount() (uint64, error)
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+func (dao *postgresDAO) AddDeployment(deployment *Deployment) error {
+	// we may receive duplicate txs here, need to ignore the conflicts
+	_, err := dao.conn.Exec("INSERT INTO aspect_deployments (aspect_id, deployer, tx_hash, block_number, block_time) "+
+		"VALUES ($1, $2, $3, $4, $5) ON CONFLICT (aspect_id) DO NOTHING",
+		deployment.AspectID, deployment.Deployer, deployment.TxHash, deployment.BlockNumber, deployment.BlockTime)
+	return err
+}
+
+// SaveBinding records the latest bind of an aspect to a contract, older txs never overwrite newer ones.
+func (dao *postgresDAO) SaveBinding(binding *Binding) error {
+	_, err := dao.conn.Exec("INSERT INTO aspect_bindings (aspect_id, contract, binder, version, priority, bound, tx_hash, block_number, block_time) "+
+		"VALUES ($1, $2, $3, $4, $5, TRUE, $6, $7, $8) ON CONFLICT (aspect_id, contract) DO UPDATE SET "+
+		"binder = EXCLUDED.binder, version = EXCLUDED.version, priority = EXCLUDED.priority, bound = TRUE, "+
+		"tx_hash = EXCLUDED.tx_hash, block_number = EXCLUDED.block_number, block_time = EXCLUDED.block_time "+
+		"WHERE aspect_bindings.block_number <= EXCLUDED.block_number",
+		binding.AspectID, binding.Contract, binding.Binder, binding.Version, binding.Priority, binding.TxHash, binding.BlockNumber, binding.BlockTime)
+	return err
+}
+
+func (dao *postgresDAO) Unbind(aspectID, contract, txHash string, blockNumber uint64) error {
+	_, err := dao.conn.Exec("UPDATE aspect_bindings SET bound = FALSE, tx_hash = $1, block_number = $2 "+
+		"WHERE aspect_id = $3 AND contract = $4 AND block_number <= $2", txHash, blockNumber, aspectID, contract)
+	return err
+}
+
+func (dao *postgresDAO) HasBoundOwnAspect(address string) (bool, error) {
+	var exists bool
+	err := dao.conn.QueryRow("SELECT EXISTS(SELECT 1 FROM aspect_bindings b JOIN aspect_deployments d ON b.aspect_id = d.aspect_id "+
+		"WHERE b.binder = $1 AND d.deployer = $1 AND b.bound)", address).Scan(&exists)
+	return exists, err
+}
+
+func (dao *postgresDAO) GetDeploymentCount() (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM aspect_deployments").Scan(&count)
+	return count, err
+}
+
+func (dao *postgresDAO) GetBindingCount() (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM aspect_bindings WHERE bound").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,215 @@
+package aspect
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	aspectcontract "github.com/artela-network/galxe-integration/contracts/aspect"
+	"github.com/artela-network/galxe-integration/goclient"
+	"github.com/artela-network/galxe-integration/indexer"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+const IndexerName = "Aspect"
+
+const (
+	methodDeploy = "deploy"
+	methodBind   = "bind"
+	methodUnbind = "unbind"
+)
+
+func newAspectIndexer(ctx context.Context, conf *config.IndexerConfig, driver string, db *sql.DB) (common.Indexer, error) {
+	if err := indexer.RequirePostgres(IndexerName, driver); err != nil {
+		return nil, err
+	}
+	aspectIndexer, err := newIndexer(ctx, conf, db, newPostgresDAO(db))
+	if err != nil {
+		return nil, err
+	}
+	return aspectIndexer, nil
+}
+
+func newIndexer(ctx context.Context, conf *config.IndexerConfig, db *sql.DB, dao DAO) (*aspectIndexer, error) {
+	aspectConf := &Config{}
+	if len(conf.Options) > 0 {
+		if err := json.Unmarshal(conf.Options, aspectConf); err != nil {
+			return nil, fmt.Errorf("failed to parse aspect indexer options: %w", err)
+		}
+	}
+
+	contract := aspectcontract.SystemContractAddress
+	if conf.Contract != "" {
+		contract = eth.HexToAddress(conf.Contract)
+	}
+
+	if conf.Thread == 0 {
+		conf.Thread = uint64(runtime.NumCPU())*2 + 1
+	}
+
+	aspectIndexer := &aspectIndexer{
+		db:       db,
+		dao:      dao,
+		contract: contract,
+		taskName: aspectConf.TaskName,
+	}
+	aspectIndexer.Runner = indexer.NewRunner(ctx, IndexerName, conf.Thread, aspectIndexer.process)
+
+	return aspectIndexer, nil
+}
+
+type aspectIndexer struct {
+	*indexer.Runner
+	db       *sql.DB
+	dao      DAO
+	contract eth.Address
+	taskName string
+}
+
+func (a *aspectIndexer) process(eventCtx *common.EventContext) error {
+	tx := eventCtx.Transaction
+	if tx.To() == nil || *tx.To() != a.contract {
+		return nil
+	}
+	if eventCtx.Receipt.Status != types.ReceiptStatusSuccessful {
+		log.Debugf("[aspect indexer] ignore failed aspect tx %s", tx.Hash().Hex())
+		return nil
+	}
+
+	call, err := goclient.GetABIRegistry().DecodeCalldata(goclient.ABIAspect, tx.Data())
+	if errors.Is(err, goclient.ErrUnknownSelector) {
+		log.Debugf("[aspect indexer] ignore unknown aspect method in tx %s", tx.Hash().Hex())
+		return nil
+	}
+	if err != nil {
+		log.Errorf("[aspect indexer] failed to decode call in tx %s: %v", tx.Hash().Hex(), err)
+		return err
+	}
+
+	sender, err := indexer.TxSender(tx)
+	if err != nil {
+		log.Error("[aspect indexer] failed to recover tx sender", err)
+		return err
+	}
+
+	switch call.Method {
+	case methodDeploy:
+		return a.deploy(eventCtx, sender)
+	case methodBind:
+		return a.bind(eventCtx, sender, call.Args)
+	case methodUnbind:
+		return a.unbind(eventCtx, call.Args)
+	default:
+		return nil
+	}
+}
+
+func (a *aspectIndexer) deploy(eventCtx *common.EventContext, sender eth.Address) error {
+	// aspect ids are derived from the deployer and its nonce, the same way as contract addresses
+	aspectID := crypto.CreateAddress(sender, eventCtx.Transaction.Nonce())
+	log.Debugf("[aspect indexer] %s deployed aspect %s", sender.Hex(), aspectID.Hex())
+
+	err := a.dao.AddDeployment(&Deployment{
+		AspectID:    aspectID.Hex(),
+		Deployer:    sender.Hex(),
+		TxHash:      eventCtx.Transaction.Hash().Hex(),
+		BlockNumber: eventCtx.BlockHeader.Number.Uint64(),
+		BlockTime:   eventCtx.BlockHeader.Time,
+	})
+	if err != nil {
+		log.Error("[aspect indexer] failed to add deployment", err)
+	}
+	return err
+}
+
+func (a *aspectIndexer) bind(eventCtx *common.EventContext, sender eth.Address, args map[string]interface{}) error {
+	aspectID, okID := args["aspectId"].(eth.Address)
+	contract, okContract := args["contractAddress"].(eth.Address)
+	version, okVersion := args["aspectVersion"].(*big.Int)
+	priority, okPriority := args["priority"].(int8)
+	if !okID || !okContract || !okVersion || !okPriority {
+		return fmt.Errorf("invalid bind call in tx %s", eventCtx.Transaction.Hash().Hex())
+	}
+	log.Debugf("[aspect indexer] %s bound aspect %s to %s", sender.Hex(), aspectID.Hex(), contract.Hex())
+
+	err := a.dao.SaveBinding(&Binding{
+		AspectID:    aspectID.Hex(),
+		Contract:    contract.Hex(),
+		Binder:      sender.Hex(),
+		Version:     version.Uint64(),
+		Priority:    priority,
+		TxHash:      eventCtx.Transaction.Hash().Hex(),
+		BlockNumber: eventCtx.BlockHeader.Number.Uint64(),
+		BlockTime:   eventCtx.BlockHeader.Time,
+	})
+	if err != nil {
+		log.Error("[aspect indexer] failed to save binding", err)
+		return err
+	}
+
+	if a.taskName == "" {
+		return nil
+	}
+	completed, err := a.dao.HasBoundOwnAspect(sender.Hex())
+	if err != nil {
+		log.Error("[aspect indexer] failed to check own aspect binding", err)
+		return err
+	}
+	if !completed {
+		return nil
+	}
+	if _, err := biz.CompleteTaskByName(a.db, sender.Hex(), a.taskName, eventCtx.Transaction.Hash().Hex()); err != nil {
+		log.Error("[aspect indexer] failed to complete task", err)
+		return err
+	}
+	return nil
+}
+
+func (a *aspectIndexer) unbind(eventCtx *common.EventContext, args map[string]interface{}) error {
+	aspectID, okID := args["aspectId"].(eth.Address)
+	contract, okContract := args["contractAddress"].(eth.Address)
+	if !okID || !okContract {
+		return fmt.Errorf("invalid unbind call in tx %s", eventCtx.Transaction.Hash().Hex())
+	}
+
+	err := a.dao.Unbind(aspectID.Hex(), contract.Hex(), eventCtx.Transaction.Hash().Hex(), eventCtx.BlockHeader.Number.Uint64())
+	if err != nil {
+		log.Error("[aspect indexer] failed to unbind", err)
+	}
+	return err
+}
+
+func (a *aspectIndexer) Metrics() interface{} {
+	deployments, err := a.dao.GetDeploymentCount()
+	if err != nil {
+		log.Error("[aspect indexer] failed to get deployment count", err)
+	}
+	bindings, err := a.dao.GetBindingCount()
+	if err != nil {
+		log.Error("[aspect indexer] failed to get binding count", err)
+	}
+
+	return struct {
+		WaitingTx   int    `json:"waiting_tx"`
+		Deployments uint64 `json:"deployments"`
+		Bindings    uint64 `json:"bindings"`
+	}{
+		WaitingTx:   a.Health().WaitingTx,
+		Deployments: deployments,
+		Bindings:    bindings,
+	}
+}
+
+func (a *aspectIndexer) Name() string {
+	return IndexerName
+}
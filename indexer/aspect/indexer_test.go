@@ -0,0 +1,139 @@
+package aspect
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	aspectcontract "github.com/artela-network/galxe-integration/contracts/aspect"
+	"github.com/artela-network/galxe-integration/goclient"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var aspectABI = indexertest.LoadABI("../../contracts/aspect/aspect.abi")
+
+func TestDecodeBindCall(t *testing.T) {
+	aspectID := eth.HexToAddress("0x58C1B539B469fd15A02Da47b52A3B82bc2ed2b1a")
+	contract := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+
+	input := indexertest.Calldata(aspectABI, methodBind, aspectID, big.NewInt(1), contract, int8(-1))
+
+	call, err := goclient.GetABIRegistry().DecodeCalldata(goclient.ABIAspect, input)
+	require.NoError(t, err)
+	require.Equal(t, methodBind, call.Method)
+	require.Equal(t, aspectID, call.Args["aspectId"])
+	require.Equal(t, contract, call.Args["contractAddress"])
+	require.Equal(t, int64(1), call.Args["aspectVersion"].(*big.Int).Int64())
+	require.Equal(t, int8(-1), call.Args["priority"])
+}
+
+func TestAspectMethods(t *testing.T) {
+	for _, name := range []string{methodDeploy, methodBind, methodUnbind} {
+		_, ok := aspectABI.Methods[name]
+		require.True(t, ok, name)
+	}
+}
+
+type kvPair struct {
+	Key   string
+	Value []byte
+}
+
+func TestAspectIndexer(t *testing.T) {
+	system := aspectcontract.SystemContractAddress
+	contract := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+
+	deploy := func(block *indexertest.Block, from *indexertest.Account) (*common.EventContext, eth.Address) {
+		aspectID := crypto.CreateAddress(from.Address, from.Nonce())
+		data := indexertest.Calldata(aspectABI, methodDeploy, []byte{0x01}, []kvPair{}, from.Address, []byte{}, big.NewInt(0))
+		return block.Tx(from, system, data), aspectID
+	}
+	bind := func(aspectID eth.Address) []byte {
+		return indexertest.Calldata(aspectABI, methodBind, aspectID, big.NewInt(1), contract, int8(1))
+	}
+	unbind := func(aspectID eth.Address) []byte {
+		return indexertest.Calldata(aspectABI, methodUnbind, aspectID, contract)
+	}
+
+	tests := []struct {
+		name        string
+		txs         func(chain *indexertest.Chain, user, other *indexertest.Account) []*common.EventContext
+		deployments uint64
+		bindings    uint64
+		boundOwn    bool
+	}{
+		{
+			name: "deploy and bind own aspect",
+			txs: func(chain *indexertest.Chain, user, _ *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				deployTx, aspectID := deploy(block, user)
+				return []*common.EventContext{deployTx, block.Tx(user, system, bind(aspectID))}
+			},
+			deployments: 1,
+			bindings:    1,
+			boundOwn:    true,
+		},
+		{
+			name: "bind aspect of others",
+			txs: func(chain *indexertest.Chain, user, other *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				deployTx, aspectID := deploy(block, other)
+				return []*common.EventContext{deployTx, block.Tx(user, system, bind(aspectID))}
+			},
+			deployments: 1,
+			bindings:    1,
+		},
+		{
+			name: "failed bind",
+			txs: func(chain *indexertest.Chain, user, _ *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				deployTx, aspectID := deploy(block, user)
+				return []*common.EventContext{deployTx, block.FailedTx(user, system, bind(aspectID))}
+			},
+			deployments: 1,
+		},
+		{
+			name: "unbound later",
+			txs: func(chain *indexertest.Chain, user, _ *indexertest.Account) []*common.EventContext {
+				block := chain.NextBlock(1)
+				deployTx, aspectID := deploy(block, user)
+				bindTx := block.Tx(user, system, bind(aspectID))
+				return []*common.EventContext{deployTx, bindTx, chain.NextBlock(1).Tx(user, system, unbind(aspectID))}
+			},
+			deployments: 1,
+		},
+		{
+			name: "other contract",
+			txs: func(chain *indexertest.Chain, user, _ *indexertest.Account) []*common.EventContext {
+				return []*common.EventContext{chain.NextBlock(1).Tx(user, contract, bind(contract))}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := newMemoryDAO()
+			aspectIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1}, nil, dao)
+			require.NoError(t, err)
+			indexertest.Start(t, aspectIndexer)
+
+			user, other := indexertest.NewAccount(), indexertest.NewAccount()
+			indexertest.MustFeed(t, aspectIndexer, tt.txs(indexertest.NewChain(), user, other)...)
+
+			deployments, err := dao.GetDeploymentCount()
+			require.NoError(t, err)
+			require.Equal(t, tt.deployments, deployments)
+			bindings, err := dao.GetBindingCount()
+			require.NoError(t, err)
+			require.Equal(t, tt.bindings, bindings)
+			boundOwn, err := dao.HasBoundOwnAspect(user.Address.Hex())
+			require.NoError(t, err)
+			require.Equal(t, tt.boundOwn, boundOwn)
+		})
+	}
+}
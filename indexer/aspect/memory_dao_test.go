@@ -0,0 +1,87 @@
+package aspect
+
+import "sync"
+
+type memoryDAO struct {
+	mu          sync.Mutex
+	deployments map[string]*Deployment
+	bindings    map[string]*Binding
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{
+		deployments: make(map[string]*Deployment),
+		bindings:    make(map[string]*Binding),
+	}
+}
+
+func (dao *memoryDAO) AddDeployment(deployment *Deployment) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	if _, ok := dao.deployments[deployment.AspectID]; !ok {
+		dao.deployments[deployment.AspectID] = deployment
+	}
+	return nil
+}
+
+func (dao *memoryDAO) SaveBinding(binding *Binding) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	key := binding.AspectID + "/" + binding.Contract
+	if existing, ok := dao.bindings[key]; ok && existing.BlockNumber > binding.BlockNumber {
+		return nil
+	}
+	saved := *binding
+	saved.Bound = true
+	dao.bindings[key] = &saved
+	return nil
+}
+
+func (dao *memoryDAO) Unbind(aspectID, contract, txHash string, blockNumber uint64) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	binding, ok := dao.bindings[aspectID+"/"+contract]
+	if !ok || binding.BlockNumber > blockNumber {
+		return nil
+	}
+	binding.Bound = false
+	binding.TxHash = txHash
+	binding.BlockNumber = blockNumber
+	return nil
+}
+
+func (dao *memoryDAO) HasBoundOwnAspect(address string) (bool, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	for _, binding := range dao.bindings {
+		deployment, ok := dao.deployments[binding.AspectID]
+		if ok && binding.Bound && binding.Binder == address && deployment.Deployer == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (dao *memoryDAO) GetDeploymentCount() (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	return uint64(len(dao.deployments)), nil
+}
+
+func (dao *memoryDAO) GetBindingCount() (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var count uint64
+	for _, binding := range dao.bindings {
+		if binding.Bound {
+			count++
+		}
+	}
+	return count, nil
+}
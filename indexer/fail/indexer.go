@@ -4,56 +4,30 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+
 	"github.com/artela-network/galxe-integration/common"
 	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer"
 	log "github.com/sirupsen/logrus"
 )
 
 const IndexerName = "Fail"
 
 func newFailIndexer(ctx context.Context, _ *config.IndexerConfig, _ string, _ *sql.DB) (common.Indexer, error) {
-	indexer := &failIndexer{
-		inputCh: make(chan *common.EventContext, 100),
-		ctx:     ctx,
-	}
-	indexer.Run()
+	fail := &failIndexer{}
+	fail.Runner = indexer.NewRunner(ctx, IndexerName, 1, fail.process)
 
-	return indexer, nil
+	return fail, nil
 }
 
 type failIndexer struct {
-	inputCh chan *common.EventContext
-	ctx     context.Context
-}
-
-func (n *failIndexer) Input() chan<- *common.EventContext {
-	return n.inputCh
+	*indexer.Runner
 }
 
-func (n *failIndexer) Run() {
-	go func() {
-		for {
-			select {
-			case eventCtx := <-n.inputCh:
-				log.Infof("[fail indexer] received new tx[%s] @ block[%d] ",
-					eventCtx.Transaction.Hash().Hex(), eventCtx.BlockHeader.Number.Uint64())
-				select {
-				case <-n.ctx.Done():
-					log.Info("[fail indexer] stopped")
-					return
-				case eventCtx.ResultChan <- errors.New("error"):
-					log.Infof("[fail indexer] processed tx[%s] @ block[%d] ",
-						eventCtx.Transaction.Hash().Hex(), eventCtx.BlockHeader.Number.Uint64())
-				default:
-					close(eventCtx.ResultChan)
-					log.Info("[fail indexer] result chan full")
-				}
-			case <-n.ctx.Done():
-				log.Info("[fail indexer] stopped")
-				return
-			}
-		}
-	}()
+func (n *failIndexer) process(eventCtx *common.EventContext) error {
+	log.Infof("[fail indexer] received new tx[%s] @ block[%d] ",
+		eventCtx.Transaction.Hash().Hex(), eventCtx.BlockHeader.Number.Uint64())
+	return errors.New("error")
 }
 
 func (n *failIndexer) Metrics() interface{} {
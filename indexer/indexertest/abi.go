@@ -0,0 +1,71 @@
+package indexertest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LoadABI loads the abi json file, it panics if the file is invalid.
+func LoadABI(path string) abi.ABI {
+	f, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	contractABI, err := abi.JSON(f)
+	if err != nil {
+		panic(fmt.Errorf("invalid abi %s: %w", path, err))
+	}
+	return contractABI
+}
+
+// Log encodes the event emitted by contract, args are given in the order of the event inputs.
+// The indexed args are encoded into topics and the others into data, it panics if the args
+// do not match the event.
+func Log(contract eth.Address, event abi.Event, args ...interface{}) *types.Log {
+	if len(args) != len(event.Inputs) {
+		panic(fmt.Errorf("event %s has %d inputs, got %d args", event.Name, len(event.Inputs), len(args)))
+	}
+
+	var topics []eth.Hash
+	if !event.Anonymous {
+		topics = append(topics, event.ID)
+	}
+	var data []interface{}
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			data = append(data, args[i])
+			continue
+		}
+		topic, err := abi.MakeTopics([]interface{}{args[i]})
+		if err != nil {
+			panic(fmt.Errorf("invalid indexed arg %s of event %s: %w", input.Name, event.Name, err))
+		}
+		topics = append(topics, topic[0][0])
+	}
+
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		panic(fmt.Errorf("invalid args of event %s: %w", event.Name, err))
+	}
+
+	return &types.Log{
+		Address: contract,
+		Topics:  topics,
+		Data:    packed,
+	}
+}
+
+// Calldata encodes a call to the method, it panics if the args do not match the method.
+func Calldata(contractABI abi.ABI, method string, args ...interface{}) []byte {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		panic(fmt.Errorf("invalid args of method %s: %w", method, err))
+	}
+	return data
+}
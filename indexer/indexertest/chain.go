@@ -0,0 +1,157 @@
+// Package indexertest builds synthetic blocks, transactions and receipts, and feeds them through
+// indexers, so indexers can be tested without a live chain or db.
+package indexertest
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/artela-network/galxe-integration/common"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ChainID is the chain id of the synthetic chains.
+var ChainID = big.NewInt(1337)
+
+// GenesisTime is the block time of the first block of the synthetic chains.
+const GenesisTime = uint64(1700000000)
+
+type Account struct {
+	Key     *ecdsa.PrivateKey
+	Address eth.Address
+	nonce   uint64
+}
+
+func NewAccount() *Account {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	return &Account{
+		Key:     key,
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// Nonce returns the nonce of the next tx sent by the account.
+func (a *Account) Nonce() uint64 {
+	return a.nonce
+}
+
+// Chain produces consecutive synthetic blocks.
+type Chain struct {
+	signer  types.Signer
+	headers []*types.Header
+	forks   int
+}
+
+func NewChain() *Chain {
+	return &Chain{
+		signer: types.LatestSignerForChainID(ChainID),
+	}
+}
+
+// NextBlock starts a new block mined interval seconds after the previous one.
+func (c *Chain) NextBlock(interval uint64) *Block {
+	header := &types.Header{
+		Number:     big.NewInt(0),
+		Time:       GenesisTime,
+		Difficulty: big.NewInt(0),
+		GasLimit:   30_000_000,
+		// blocks of different forks at the same height have different hashes
+		Extra: []byte{byte(c.forks)},
+	}
+	if len(c.headers) > 0 {
+		parent := c.headers[len(c.headers)-1]
+		header.ParentHash = parent.Hash()
+		header.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+		header.Time = parent.Time + interval
+	}
+	c.headers = append(c.headers, header)
+
+	return &Block{chain: c, Header: header}
+}
+
+// Fork returns a chain sharing the blocks below number, the next block of the fork replaces
+// the block at number, e.g. to simulate a reorg.
+func (c *Chain) Fork(number uint64) *Chain {
+	if number > uint64(len(c.headers)) {
+		number = uint64(len(c.headers))
+	}
+	return &Chain{
+		signer:  c.signer,
+		headers: append([]*types.Header(nil), c.headers[:number]...),
+		forks:   c.forks + 1,
+	}
+}
+
+// Block collects the txs of a synthetic block, the logs get their positions in the block assigned
+// in the order the txs are added.
+type Block struct {
+	chain    *Chain
+	Header   *types.Header
+	events   []*common.EventContext
+	logIndex uint
+}
+
+// Tx adds a successful tx sent by from, with the given logs emitted.
+func (b *Block) Tx(from *Account, to eth.Address, data []byte, logs ...*types.Log) *common.EventContext {
+	return b.add(from, to, data, types.ReceiptStatusSuccessful, logs)
+}
+
+// FailedTx adds a reverted tx sent by from.
+func (b *Block) FailedTx(from *Account, to eth.Address, data []byte) *common.EventContext {
+	return b.add(from, to, data, types.ReceiptStatusFailed, nil)
+}
+
+func (b *Block) add(from *Account, to eth.Address, data []byte, status uint64, logs []*types.Log) *common.EventContext {
+	tx, err := types.SignNewTx(from.Key, b.chain.signer, &types.DynamicFeeTx{
+		ChainID:   ChainID,
+		Nonce:     from.nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       1_000_000,
+		To:        &to,
+		Value:     new(big.Int),
+		Data:      data,
+	})
+	if err != nil {
+		panic(err)
+	}
+	from.nonce++
+
+	receipt := &types.Receipt{
+		Type:             tx.Type(),
+		Status:           status,
+		TxHash:           tx.Hash(),
+		GasUsed:          tx.Gas(),
+		BlockHash:        b.Header.Hash(),
+		BlockNumber:      b.Header.Number,
+		TransactionIndex: uint(len(b.events)),
+	}
+	for _, l := range logs {
+		l.TxHash = tx.Hash()
+		l.TxIndex = receipt.TransactionIndex
+		l.BlockHash = receipt.BlockHash
+		l.BlockNumber = b.Header.Number.Uint64()
+		l.Index = b.logIndex
+		b.logIndex++
+		receipt.Logs = append(receipt.Logs, l)
+	}
+
+	eventCtx := &common.EventContext{
+		ChainID:     ChainID,
+		BlockHeader: b.Header,
+		Transaction: tx,
+		Receipt:     receipt,
+	}
+	b.events = append(b.events, eventCtx)
+	return eventCtx
+}
+
+// Events returns the event contexts of the txs in the block.
+func (b *Block) Events() []*common.EventContext {
+	return b.events
+}
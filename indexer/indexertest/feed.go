@@ -0,0 +1,65 @@
+package indexertest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// Timeout is how long Feed waits for the result of a tx.
+var Timeout = 5 * time.Second
+
+var errResultChanClosed = errors.New("result chan closed")
+
+// Start starts the indexer and stops it when the test finishes.
+func Start(t testing.TB, indexer common.Indexer) {
+	t.Helper()
+
+	if err := indexer.Start(); err != nil {
+		t.Fatalf("failed to start %s indexer: %v", indexer.Name(), err)
+	}
+	t.Cleanup(indexer.Stop)
+}
+
+// Feed submits the events to the indexer one by one like the fetcher does, and returns the result of each.
+// An event can be fed multiple times, e.g. to simulate a retried block.
+func Feed(t testing.TB, indexer common.Indexer, events ...*common.EventContext) []error {
+	t.Helper()
+
+	results := make([]error, len(events))
+	for i, event := range events {
+		resCh := make(chan error, 1)
+		eventCtx := *event
+		eventCtx.ResultChan = resCh
+
+		select {
+		case indexer.Input() <- &eventCtx:
+		case <-time.After(Timeout):
+			t.Fatalf("timed out submitting tx %s to %s indexer", event.TxHash(), indexer.Name())
+		}
+
+		select {
+		case err, ok := <-resCh:
+			if !ok {
+				err = errResultChanClosed
+			}
+			results[i] = err
+		case <-time.After(Timeout):
+			t.Fatalf("timed out waiting %s indexer to process tx %s", indexer.Name(), event.TxHash())
+		}
+	}
+	return results
+}
+
+// MustFeed feeds the events and fails the test if any of them fails.
+func MustFeed(t testing.TB, indexer common.Indexer, events ...*common.EventContext) {
+	t.Helper()
+
+	for i, err := range Feed(t, indexer, events...) {
+		if err != nil {
+			t.Fatalf("%s indexer failed to process tx %s: %v", indexer.Name(), events[i].TxHash(), err)
+		}
+	}
+}
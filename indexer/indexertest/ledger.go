@@ -0,0 +1,41 @@
+package indexertest
+
+import (
+	"sync"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/indexer/ledger"
+)
+
+type memoryLedger struct {
+	mu        sync.Mutex
+	processed map[ledger.Key]uint64
+}
+
+// NewLedger returns an in-memory ledger, fn is called with a nil executor so it can only be
+// used with in-memory DAOs.
+func NewLedger() ledger.Ledger {
+	return &memoryLedger{processed: make(map[ledger.Key]uint64)}
+}
+
+func (l *memoryLedger) Processed(key ledger.Key) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.processed[key]
+	return ok, nil
+}
+
+func (l *memoryLedger) Apply(key ledger.Key, blockNumber uint64, fn func(tx common.Executor) error) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.processed[key]; ok {
+		return false, nil
+	}
+	if err := fn(nil); err != nil {
+		return false, err
+	}
+	l.processed[key] = blockNumber
+	return true, nil
+}
@@ -0,0 +1,99 @@
+package ledger
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	"github.com/artela-network/galxe-integration/common"
+	eth "github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// TxLevel is the log index used for side effects derived from a whole transaction rather than one of its logs.
+const TxLevel = -1
+
+// Key identifies a log processed by an indexer.
+type Key struct {
+	Chain    string
+	TxHash   string
+	LogIndex int64
+	Indexer  string
+}
+
+func NewKey(chainID *big.Int, txHash eth.Hash, logIndex int64, indexer string) Key {
+	chain := "0"
+	if chainID != nil {
+		chain = chainID.String()
+	}
+	return Key{
+		Chain:    chain,
+		TxHash:   txHash.Hex(),
+		LogIndex: logIndex,
+		Indexer:  indexer,
+	}
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s/%s#%d", k.Indexer, k.Chain, k.TxHash, k.LogIndex)
+}
+
+// Ledger records the logs already applied by each indexer, it lets the indexers which aggregate
+// values (counters, balances) apply every log exactly once, even if blocks are retried or backfilled.
+type Ledger interface {
+	// Processed reports whether the key has already been applied.
+	Processed(key Key) (bool, error)
+	// Apply records the key and runs fn atomically. If the key has already been recorded,
+	// fn is skipped and false is returned. If fn fails nothing is recorded.
+	Apply(key Key, blockNumber uint64, fn func(tx common.Executor) error) (bool, error)
+}
+
+type postgresLedger struct {
+	conn *sql.DB
+}
+
+func NewLedger(db *sql.DB) Ledger {
+	return &postgresLedger{conn: db}
+}
+
+func (l *postgresLedger) Processed(key Key) (bool, error) {
+	var exists bool
+	err := l.conn.QueryRow("SELECT EXISTS(SELECT 1 FROM processed_logs WHERE chain = $1 AND tx_hash = $2 AND log_index = $3 AND indexer = $4)",
+		key.Chain, key.TxHash, key.LogIndex, key.Indexer).Scan(&exists)
+	return exists, err
+}
+
+// Apply records the key and runs fn in the same db transaction, so the log will be applied
+// again when the block is retried if fn fails.
+func (l *postgresLedger) Apply(key Key, blockNumber uint64, fn func(tx common.Executor) error) (bool, error) {
+	tx, err := l.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+	// Defer a rollback in case anything fails.
+	defer tx.Rollback()
+
+	res, err := tx.Exec("INSERT INTO processed_logs (chain, tx_hash, log_index, indexer, block_number) VALUES ($1, $2, $3, $4, $5) "+
+		"ON CONFLICT (chain, tx_hash, log_index, indexer) DO NOTHING",
+		key.Chain, key.TxHash, key.LogIndex, key.Indexer, blockNumber)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if claimed == 0 {
+		log.Debugf("[ledger] %s has already been processed, skipping", key)
+		return false, nil
+	}
+
+	if err := fn(tx); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"database/sql"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/db/migrate"
+	eth "github.com/ethereum/go-ethereum/common"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLedger(t *testing.T) (Ledger, *sql.DB) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "ledger.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := migrate.NewMigrator(db, "sqlite3")
+	require.NoError(t, err)
+	_, err = migrator.Up(0)
+	require.NoError(t, err)
+	return NewLedger(db), db
+}
+
+// addPlayer is a side effect of a log, written in the transaction of the ledger
+func addPlayer(player string) func(tx common.Executor) error {
+	return func(tx common.Executor) error {
+		_, err := tx.Exec("INSERT INTO scored_players (player) VALUES ($1)", player)
+		return err
+	}
+}
+
+func playerCount(t *testing.T, db *sql.DB) int {
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM scored_players").Scan(&count))
+	return count
+}
+
+func TestLedgerApply(t *testing.T) {
+	l, db := newTestLedger(t)
+	key := NewKey(big.NewInt(11822), eth.HexToHash("0x01"), 0, "test")
+
+	processed, err := l.Processed(key)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	applied, err := l.Apply(key, 100, addPlayer("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	require.NoError(t, err)
+	require.True(t, applied)
+	require.Equal(t, 1, playerCount(t, db))
+
+	processed, err = l.Processed(key)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	// a duplicate key is skipped without calling fn
+	called := false
+	applied, err = l.Apply(key, 100, func(common.Executor) error {
+		called = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.False(t, applied)
+	require.False(t, called)
+
+	// the same log seen by another indexer, or at another index, is a different key
+	for _, other := range []Key{
+		NewKey(big.NewInt(11822), eth.HexToHash("0x01"), 0, "other"),
+		NewKey(big.NewInt(11822), eth.HexToHash("0x01"), TxLevel, "test"),
+	} {
+		processed, err = l.Processed(other)
+		require.NoError(t, err)
+		require.False(t, processed, other.String())
+	}
+}
+
+func TestLedgerApplyRollback(t *testing.T) {
+	l, db := newTestLedger(t)
+	key := NewKey(big.NewInt(11822), eth.HexToHash("0x02"), 3, "test")
+
+	// the writes of fn and the key are rolled back together when fn fails
+	failure := errors.New("side effect failed")
+	applied, err := l.Apply(key, 100, func(tx common.Executor) error {
+		if err := addPlayer("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")(tx); err != nil {
+			return err
+		}
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+	require.False(t, applied)
+	require.Equal(t, 0, playerCount(t, db))
+
+	processed, err := l.Processed(key)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	// so the log is applied when the block is retried
+	applied, err = l.Apply(key, 100, addPlayer("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	require.NoError(t, err)
+	require.True(t, applied)
+	require.Equal(t, 1, playerCount(t, db))
+}
@@ -0,0 +1,91 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/goclient"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	AddressFieldTxFrom = "tx.from"
+	AddressFieldTxTo   = "tx.to"
+)
+
+var ErrAddressFieldNotFound = errors.New("address field not found in event")
+
+// EventMatcher matches receipt logs against a single configured contract event.
+type EventMatcher struct {
+	contract     eth.Address
+	event        abi.Event
+	addressField string
+}
+
+func NewEventMatcher(filter *config.EventFilter) (*EventMatcher, error) {
+	filter.FillDefaults()
+
+	if !eth.IsHexAddress(filter.Contract) {
+		return nil, fmt.Errorf("invalid contract address %q", filter.Contract)
+	}
+
+	contractABI, err := goclient.GetABIRegistry().Resolve(filter.ABI)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := contractABI.Events[filter.Event]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in abi %s", filter.Event, filter.ABI)
+	}
+
+	return &EventMatcher{
+		contract:     eth.HexToAddress(filter.Contract),
+		event:        event,
+		addressField: filter.AddressField,
+	}, nil
+}
+
+func (m *EventMatcher) EventName() string {
+	return m.event.Name
+}
+
+// Match reports whether the log was emitted by the configured event.
+func (m *EventMatcher) Match(ethLog *types.Log) bool {
+	return ethLog.Address == m.contract && len(ethLog.Topics) > 0 && ethLog.Topics[0] == m.event.ID
+}
+
+// Decode unpacks both indexed and non-indexed event arguments into a map keyed by argument name.
+func (m *EventMatcher) Decode(ethLog *types.Log) (map[string]interface{}, error) {
+	return goclient.DecodeEventLog(&m.event, ethLog)
+}
+
+// Address returns the address the event is attributed to, according to the configured address field.
+func (m *EventMatcher) Address(tx *types.Transaction, args map[string]interface{}) (eth.Address, error) {
+	switch m.addressField {
+	case AddressFieldTxFrom:
+		return TxSender(tx)
+	case AddressFieldTxTo:
+		if tx.To() == nil {
+			return eth.Address{}, ErrAddressFieldNotFound
+		}
+		return *tx.To(), nil
+	default:
+		address, ok := args[m.addressField].(eth.Address)
+		if !ok {
+			return eth.Address{}, ErrAddressFieldNotFound
+		}
+		return address, nil
+	}
+}
+
+// TxSender recovers the sender of the transaction.
+func TxSender(tx *types.Transaction) (eth.Address, error) {
+	if !tx.Protected() {
+		return types.Sender(types.HomesteadSigner{}, tx)
+	}
+	return types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+}
@@ -0,0 +1,27 @@
+package native
+
+type Config struct {
+	Tasks []*TaskConfig `json:"tasks"`
+}
+
+// TaskConfig marks the task named TaskName as succeeded for the signer of a successful cosmos message
+// of Action (bank_send, delegate or vote). MinAmount of Denom must be sent or delegated, and the message
+// must be sent to Recipient, delegated to Validator or vote on ProposalID, whichever is given.
+type TaskConfig struct {
+	TaskName   string `json:"task_name"`
+	Action     string `json:"action"`
+	Denom      string `json:"denom"`
+	MinAmount  string `json:"min_amount"`
+	Recipient  string `json:"recipient"`
+	Validator  string `json:"validator"`
+	ProposalID string `json:"proposal_id"`
+}
+
+func (c *TaskConfig) FillDefaults() {
+	if c.Denom == "" {
+		c.Denom = "uart"
+	}
+	if c.MinAmount == "" {
+		c.MinAmount = "0"
+	}
+}
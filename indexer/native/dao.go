@@ -0,0 +1,52 @@
+package native
+
+import (
+	"database/sql"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// Action is a cosmos message matching a task, Address is the evm address of the signer.
+type Action struct {
+	TaskName    string `json:"taskName"`
+	Action      string `json:"action"`
+	Signer      string `json:"signer"`
+	Address     string `json:"address"`
+	TxHash      string `json:"txHash"`
+	MsgIndex    int    `json:"msgIndex"`
+	BlockNumber uint64 `json:"blockNumber"`
+	BlockTime   uint64 `json:"blockTime"`
+}
+
+type DAO interface {
+	AddAction(exec common.Executor, action *Action) error
+	CompleteTask(exec common.Executor, address, taskName, txHash string) error
+	GetActionCount() (uint64, error)
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+func (dao *postgresDAO) AddAction(exec common.Executor, action *Action) error {
+	_, err := exec.Exec("INSERT INTO native_actions (task_name, action, signer, address, tx_hash, msg_index, block_number, block_time) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (task_name, tx_hash, msg_index) DO NOTHING",
+		action.TaskName, action.Action, action.Signer, action.Address, action.TxHash, action.MsgIndex, action.BlockNumber, action.BlockTime)
+	return err
+}
+
+func (dao *postgresDAO) CompleteTask(exec common.Executor, address, taskName, txHash string) error {
+	_, err := biz.CompleteTaskByName(exec, address, taskName, txHash)
+	return err
+}
+
+func (dao *postgresDAO) GetActionCount() (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM native_actions").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,231 @@
+package native
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	eth "github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer"
+	"github.com/artela-network/galxe-integration/indexer/ledger"
+)
+
+const IndexerName = "Native"
+
+type task struct {
+	name       string
+	action     string
+	denom      string
+	minAmount  sdkmath.Int
+	recipient  string
+	validator  string
+	proposalID string
+}
+
+func newNativeIndexer(ctx context.Context, conf *config.IndexerConfig, driver string, db *sql.DB) (common.Indexer, error) {
+	if err := indexer.RequirePostgres(IndexerName, driver); err != nil {
+		return nil, err
+	}
+	nativeIndexer, err := newIndexer(ctx, conf, newPostgresDAO(db), ledger.NewLedger(db))
+	if err != nil {
+		return nil, err
+	}
+	return nativeIndexer, nil
+}
+
+func newIndexer(ctx context.Context, conf *config.IndexerConfig, dao DAO, processed ledger.Ledger) (*nativeIndexer, error) {
+	nativeConf := &Config{}
+	if err := json.Unmarshal(conf.Options, nativeConf); err != nil {
+		return nil, fmt.Errorf("failed to parse native indexer options: %w", err)
+	}
+
+	tasks := make([]*task, 0, len(nativeConf.Tasks))
+	for _, tc := range nativeConf.Tasks {
+		t, err := newTask(tc)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	if len(tasks) == 0 {
+		return nil, errors.New("native indexer must have at least one task")
+	}
+
+	if conf.Thread == 0 {
+		conf.Thread = uint64(runtime.NumCPU())*2 + 1
+	}
+
+	nativeIndexer := &nativeIndexer{
+		dao:    dao,
+		ledger: processed,
+		tasks:  tasks,
+	}
+	nativeIndexer.Runner = indexer.NewRunner(ctx, IndexerName, conf.Thread, nativeIndexer.process)
+
+	return nativeIndexer, nil
+}
+
+func newTask(conf *TaskConfig) (*task, error) {
+	conf.FillDefaults()
+	switch conf.Action {
+	case common.CosmosActionBankSend, common.CosmosActionDelegate, common.CosmosActionVote:
+	default:
+		return nil, fmt.Errorf("unknown action %q of task %s", conf.Action, conf.TaskName)
+	}
+	if conf.TaskName == "" {
+		return nil, errors.New("native task must have a name")
+	}
+	minAmount, ok := sdkmath.NewIntFromString(conf.MinAmount)
+	if !ok || minAmount.IsNegative() {
+		return nil, fmt.Errorf("invalid min amount %q of task %s", conf.MinAmount, conf.TaskName)
+	}
+
+	return &task{
+		name:       conf.TaskName,
+		action:     conf.Action,
+		denom:      conf.Denom,
+		minAmount:  minAmount,
+		recipient:  strings.ToLower(conf.Recipient),
+		validator:  strings.ToLower(conf.Validator),
+		proposalID: conf.ProposalID,
+	}, nil
+}
+
+type nativeIndexer struct {
+	*indexer.Runner
+	dao    DAO
+	ledger ledger.Ledger
+	tasks  []*task
+}
+
+// CosmosIndexer marks the indexer as an indexer of cosmos txs, so only the cosmos fetcher sends txs to it.
+func (n *nativeIndexer) CosmosIndexer() {}
+
+func (n *nativeIndexer) process(eventCtx *common.EventContext) error {
+	cosmosTx := eventCtx.Cosmos
+	if cosmosTx == nil || !cosmosTx.Succeeded() {
+		return nil
+	}
+
+	for _, msg := range cosmosTx.Messages {
+		var matched []*task
+		for _, t := range n.tasks {
+			ok, err := t.match(msg)
+			if err != nil {
+				log.Errorf("[native indexer] invalid %s message in tx %s: %v", msg.Action, cosmosTx.Hash, err)
+				return err
+			}
+			if ok {
+				matched = append(matched, t)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		address, err := evmAddress(msg.Signer)
+		if err != nil {
+			log.Errorf("[native indexer] invalid signer %s in tx %s: %v", msg.Signer, cosmosTx.Hash, err)
+			return err
+		}
+
+		key := ledger.NewKey(eventCtx.ChainID, eth.HexToHash(cosmosTx.Hash), int64(msg.Index), IndexerName)
+		_, err = n.ledger.Apply(key, cosmosTx.Height, func(exec common.Executor) error {
+			for _, t := range matched {
+				err := n.dao.AddAction(exec, &Action{
+					TaskName:    t.name,
+					Action:      msg.Action,
+					Signer:      msg.Signer,
+					Address:     address.Hex(),
+					TxHash:      cosmosTx.Hash,
+					MsgIndex:    msg.Index,
+					BlockNumber: cosmosTx.Height,
+					BlockTime:   uint64(cosmosTx.Time.Unix()),
+				})
+				if err != nil {
+					return err
+				}
+
+				log.Infof("[native indexer] %s completed %s task %s", address.Hex(), msg.Action, t.name)
+				if err := n.dao.CompleteTask(exec, address.Hex(), t.name, cosmosTx.Hash); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorf("[native indexer] failed to apply %s message in tx %s: %v", msg.Action, cosmosTx.Hash, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// match reports whether the message fulfills the task
+func (t *task) match(msg *common.CosmosMsg) (bool, error) {
+	if msg.Action != t.action {
+		return false, nil
+	}
+
+	switch t.action {
+	case common.CosmosActionBankSend:
+		if t.recipient != "" && strings.ToLower(msg.Attributes["to"]) != t.recipient {
+			return false, nil
+		}
+	case common.CosmosActionDelegate:
+		if t.validator != "" && strings.ToLower(msg.Attributes["validator"]) != t.validator {
+			return false, nil
+		}
+	case common.CosmosActionVote:
+		return t.proposalID == "" || msg.Attributes["proposal_id"] == t.proposalID, nil
+	}
+
+	coins, err := sdk.ParseCoinsNormalized(msg.Attributes["amount"])
+	if err != nil {
+		return false, err
+	}
+	amount := coins.AmountOf(t.denom)
+	return amount.IsPositive() && amount.GTE(t.minAmount), nil
+}
+
+// evmAddress converts the bech32 address of a cosmos account to its evm address, which share the same bytes.
+func evmAddress(address string) (eth.Address, error) {
+	_, bz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return eth.Address{}, err
+	}
+	if len(bz) != eth.AddressLength {
+		return eth.Address{}, fmt.Errorf("address %s is not %d bytes", address, eth.AddressLength)
+	}
+	return eth.BytesToAddress(bz), nil
+}
+
+func (n *nativeIndexer) Metrics() interface{} {
+	count, err := n.dao.GetActionCount()
+	if err != nil {
+		log.Error("[native indexer] failed to get action count", err)
+	}
+
+	return struct {
+		WaitingTx int    `json:"waiting_tx"`
+		Actions   uint64 `json:"actions"`
+	}{
+		WaitingTx: n.Health().WaitingTx,
+		Actions:   count,
+	}
+}
+
+func (n *nativeIndexer) Name() string {
+	return IndexerName
+}
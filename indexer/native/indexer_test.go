@@ -0,0 +1,119 @@
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+)
+
+const validator = "artvaloper1qyqszqgpqyqszqgpqyqszqgpqyqszqgpxlghk3"
+
+func cosmosTx(hash string, code uint32, msgs ...*common.CosmosMsg) *common.EventContext {
+	for i, msg := range msgs {
+		msg.Index = i
+	}
+	return &common.EventContext{
+		ChainID: indexertest.ChainID,
+		Cosmos: &common.CosmosTx{
+			Height:   1,
+			Time:     time.Unix(int64(indexertest.GenesisTime), 0),
+			Hash:     hash,
+			Code:     code,
+			Messages: msgs,
+		},
+	}
+}
+
+func TestNativeIndexer(t *testing.T) {
+	user := indexertest.NewAccount()
+	signer, err := bech32.ConvertAndEncode("art", user.Address.Bytes())
+	require.NoError(t, err)
+	receiver, err := bech32.ConvertAndEncode("art", indexertest.NewAccount().Address.Bytes())
+	require.NoError(t, err)
+
+	options, err := json.Marshal(&Config{Tasks: []*TaskConfig{
+		{TaskName: "send", Action: common.CosmosActionBankSend, MinAmount: "100"},
+		{TaskName: "stake", Action: common.CosmosActionDelegate, Validator: validator},
+		{TaskName: "vote", Action: common.CosmosActionVote, ProposalID: "3"},
+	}})
+	require.NoError(t, err)
+
+	send := func(amount string) *common.CosmosMsg {
+		return &common.CosmosMsg{Action: common.CosmosActionBankSend, Signer: signer, Attributes: map[string]string{"to": receiver, "amount": amount}}
+	}
+	delegate := func(to string) *common.CosmosMsg {
+		return &common.CosmosMsg{Action: common.CosmosActionDelegate, Signer: signer, Attributes: map[string]string{"validator": to, "amount": "1uart"}}
+	}
+	vote := func(proposal string) *common.CosmosMsg {
+		return &common.CosmosMsg{Action: common.CosmosActionVote, Signer: signer, Attributes: map[string]string{"proposal_id": proposal, "option": "VOTE_OPTION_YES"}}
+	}
+
+	tests := []struct {
+		name      string
+		tx        *common.EventContext
+		completed []string
+	}{
+		{
+			name:      "send enough",
+			tx:        cosmosTx("A1", 0, send("150uart,3stake")),
+			completed: []string{"send"},
+		},
+		{
+			name: "send too little",
+			tx:   cosmosTx("A2", 0, send("99uart")),
+		},
+		{
+			name: "send other denom",
+			tx:   cosmosTx("A3", 0, send("1000stake")),
+		},
+		{
+			name: "failed tx",
+			tx:   cosmosTx("A4", 5, send("150uart")),
+		},
+		{
+			name:      "delegate and vote",
+			tx:        cosmosTx("A5", 0, delegate(validator), vote("3")),
+			completed: []string{"stake", "vote"},
+		},
+		{
+			name: "other validator and proposal",
+			tx:   cosmosTx("A6", 0, delegate("artvaloper1other"), vote("4")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := newMemoryDAO()
+			nativeIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1, Options: options}, dao, indexertest.NewLedger())
+			require.NoError(t, err)
+			indexertest.Start(t, nativeIndexer)
+
+			// replayed txs must be applied only once
+			indexertest.MustFeed(t, nativeIndexer, tt.tx, tt.tx)
+
+			require.ElementsMatch(t, tt.completed, dao.completedTasks(user.Address.Hex()))
+			count, err := dao.GetActionCount()
+			require.NoError(t, err)
+			require.Equal(t, uint64(len(tt.completed)), count)
+		})
+	}
+}
+
+func TestNewTask(t *testing.T) {
+	for _, conf := range []*TaskConfig{
+		{TaskName: "send", Action: "swap"},
+		{Action: common.CosmosActionVote},
+		{TaskName: "send", Action: common.CosmosActionBankSend, MinAmount: "-1"},
+	} {
+		_, err := newTask(conf)
+		require.Error(t, err, conf)
+	}
+}
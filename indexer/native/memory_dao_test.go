@@ -0,0 +1,53 @@
+package native
+
+import (
+	"sync"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type memoryDAO struct {
+	mu        sync.Mutex
+	actions   []*Action
+	completed map[[2]string]string
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{completed: make(map[[2]string]string)}
+}
+
+func (dao *memoryDAO) AddAction(_ common.Executor, action *Action) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	dao.actions = append(dao.actions, action)
+	return nil
+}
+
+func (dao *memoryDAO) CompleteTask(_ common.Executor, address, taskName, txHash string) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	dao.completed[[2]string{address, taskName}] = txHash
+	return nil
+}
+
+func (dao *memoryDAO) GetActionCount() (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	return uint64(len(dao.actions)), nil
+}
+
+func (dao *memoryDAO) completedTasks(address string) []string {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var tasks []string
+	for key := range dao.completed {
+		if key[0] == address {
+			tasks = append(tasks, key[1])
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,35 @@
+package nft
+
+const (
+	StandardERC721  = "erc721"
+	StandardERC1155 = "erc1155"
+
+	TaskKindMint = "mint"
+	TaskKindHold = "hold"
+)
+
+type Config struct {
+	Collections []*CollectionConfig `json:"collections"`
+}
+
+type CollectionConfig struct {
+	Name     string        `json:"name"`
+	Contract string        `json:"contract"`
+	Standard string        `json:"standard"`
+	Tasks    []*TaskConfig `json:"tasks"`
+}
+
+// TaskConfig marks the task named TaskName as succeeded for an address which has minted (mint) or
+// holds (hold) at least Count tokens of the collection, only tokens of TokenID are counted if given.
+type TaskConfig struct {
+	TaskName string `json:"task_name"`
+	Kind     string `json:"kind"`
+	TokenID  string `json:"token_id"`
+	Count    string `json:"count"`
+}
+
+func (c *TaskConfig) FillDefaults() {
+	if c.Count == "" {
+		c.Count = "1"
+	}
+}
@@ -0,0 +1,105 @@
+package nft
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type Mint struct {
+	Collection  string `json:"collection"`
+	TokenID     string `json:"tokenId"`
+	Minter      string `json:"minter"`
+	Amount      string `json:"amount"`
+	TxHash      string `json:"txHash"`
+	LogIndex    uint   `json:"logIndex"`
+	BlockNumber uint64 `json:"blockNumber"`
+	BlockTime   uint64 `json:"blockTime"`
+}
+
+// Activity counts the tokens of a collection an address minted and holds
+type Activity struct {
+	Minted *big.Int
+	Held   *big.Int
+}
+
+type DAO interface {
+	AddMint(exec common.Executor, mint *Mint) error
+	UpdateBalance(exec common.Executor, collection, tokenID, owner string, delta *big.Int) error
+	// MintedCount returns the number of tokens minted to the address, tokens of any id are counted if tokenID is empty.
+	MintedCount(exec common.Executor, collection, address, tokenID string) (*big.Int, error)
+	// HeldCount returns the number of tokens held by the address, tokens of any id are counted if tokenID is empty.
+	HeldCount(exec common.Executor, collection, address, tokenID string) (*big.Int, error)
+	GetMintCount(collection string) (uint64, error)
+	// GetActivity returns the tokens minted to and held by the address, tokens of any id are counted if tokenID is empty.
+	GetActivity(collection, address, tokenID string) (*Activity, error)
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+// NewDAO returns the dao of the mints and balances recorded by the indexer, for their readers
+func NewDAO(db *sql.DB) DAO {
+	return newPostgresDAO(db)
+}
+
+func (dao *postgresDAO) AddMint(exec common.Executor, mint *Mint) error {
+	_, err := exec.Exec("INSERT INTO nft_mints (collection, token_id, minter, amount, tx_hash, log_index, block_number, block_time) "+
+		"VALUES ($1, $2, $3, $4::NUMERIC, $5, $6, $7, $8) ON CONFLICT (collection, token_id, tx_hash, log_index) DO NOTHING",
+		mint.Collection, mint.TokenID, mint.Minter, mint.Amount, mint.TxHash, mint.LogIndex, mint.BlockNumber, mint.BlockTime)
+	return err
+}
+
+func (dao *postgresDAO) UpdateBalance(exec common.Executor, collection, tokenID, owner string, delta *big.Int) error {
+	_, err := exec.Exec("INSERT INTO nft_balances (collection, token_id, owner, balance) VALUES ($1, $2, $3, $4::NUMERIC) "+
+		"ON CONFLICT (collection, owner, token_id) DO UPDATE SET balance = nft_balances.balance + EXCLUDED.balance",
+		collection, tokenID, owner, delta.String())
+	return err
+}
+
+func (dao *postgresDAO) MintedCount(exec common.Executor, collection, address, tokenID string) (*big.Int, error) {
+	return queryCount(exec, "SELECT COALESCE(SUM(amount), 0)::TEXT FROM nft_mints "+
+		"WHERE collection = $1 AND minter = $2 AND ($3 = '' OR token_id = $3)", collection, address, tokenID)
+}
+
+func (dao *postgresDAO) HeldCount(exec common.Executor, collection, address, tokenID string) (*big.Int, error) {
+	return queryCount(exec, "SELECT COALESCE(SUM(balance), 0)::TEXT FROM nft_balances "+
+		"WHERE collection = $1 AND owner = $2 AND ($3 = '' OR token_id = $3) AND balance > 0", collection, address, tokenID)
+}
+
+func (dao *postgresDAO) GetMintCount(collection string) (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM nft_mints WHERE collection = $1", collection).Scan(&count)
+	return count, err
+}
+
+func (dao *postgresDAO) GetActivity(collection, address, tokenID string) (*Activity, error) {
+	minted, err := dao.MintedCount(dao.conn, collection, address, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	held, err := dao.HeldCount(dao.conn, collection, address, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return &Activity{Minted: minted, Held: held}, nil
+}
+
+func queryCount(exec common.Executor, query string, args ...interface{}) (*big.Int, error) {
+	var raw string
+	if err := exec.QueryRow(query, args...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	count, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid count %s", raw)
+	}
+	return count, nil
+}
@@ -0,0 +1,305 @@
+package nft
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/goclient"
+	"github.com/artela-network/galxe-integration/indexer"
+	"github.com/artela-network/galxe-integration/indexer/ledger"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const IndexerName = "NFT"
+
+const (
+	eventTransfer       = "Transfer"
+	eventTransferSingle = "TransferSingle"
+	eventTransferBatch  = "TransferBatch"
+)
+
+type task struct {
+	name    string
+	kind    string
+	tokenID string
+	count   *big.Int
+}
+
+type collection struct {
+	name     string
+	address  eth.Address
+	standard string
+	tasks    []*task
+}
+
+// transfer is a movement of amount tokens of tokenID, from is zero for mints and to is zero for burns
+type transfer struct {
+	from    eth.Address
+	to      eth.Address
+	tokenID *big.Int
+	amount  *big.Int
+}
+
+func newNFTIndexer(ctx context.Context, conf *config.IndexerConfig, driver string, db *sql.DB) (common.Indexer, error) {
+	if err := indexer.RequirePostgres(IndexerName, driver); err != nil {
+		return nil, err
+	}
+	nftIndexer, err := newIndexer(ctx, conf, db, newPostgresDAO(db), ledger.NewLedger(db))
+	if err != nil {
+		return nil, err
+	}
+	return nftIndexer, nil
+}
+
+func newIndexer(ctx context.Context, conf *config.IndexerConfig, db *sql.DB, dao DAO, processed ledger.Ledger) (*nftIndexer, error) {
+	nftConf := &Config{}
+	if err := json.Unmarshal(conf.Options, nftConf); err != nil {
+		return nil, fmt.Errorf("failed to parse nft indexer options: %w", err)
+	}
+
+	collections := make(map[eth.Address]*collection, len(nftConf.Collections))
+	for _, cc := range nftConf.Collections {
+		c, err := newCollection(cc)
+		if err != nil {
+			return nil, err
+		}
+		collections[c.address] = c
+	}
+
+	if conf.Thread == 0 {
+		conf.Thread = uint64(runtime.NumCPU())*2 + 1
+	}
+
+	nftIndexer := &nftIndexer{
+		db:          db,
+		dao:         dao,
+		ledger:      processed,
+		collections: collections,
+	}
+	nftIndexer.Runner = indexer.NewRunner(ctx, IndexerName, conf.Thread, nftIndexer.process)
+
+	return nftIndexer, nil
+}
+
+func newCollection(conf *CollectionConfig) (*collection, error) {
+	if conf.Name == "" || !eth.IsHexAddress(conf.Contract) {
+		return nil, errors.New("nft collection must have a name and a valid contract address")
+	}
+	if conf.Standard != StandardERC721 && conf.Standard != StandardERC1155 {
+		return nil, fmt.Errorf("unknown standard %q of collection %s", conf.Standard, conf.Name)
+	}
+
+	c := &collection{
+		name:     conf.Name,
+		address:  eth.HexToAddress(conf.Contract),
+		standard: conf.Standard,
+	}
+	for _, tc := range conf.Tasks {
+		tc.FillDefaults()
+		if tc.TaskName == "" || (tc.Kind != TaskKindMint && tc.Kind != TaskKindHold) {
+			return nil, fmt.Errorf("task of collection %s must have a name and a kind of mint or hold", conf.Name)
+		}
+		count, ok := new(big.Int).SetString(tc.Count, 10)
+		if !ok || count.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid count %q of task %s", tc.Count, tc.TaskName)
+		}
+		tokenID := ""
+		if tc.TokenID != "" {
+			id, ok := new(big.Int).SetString(tc.TokenID, 0)
+			if !ok {
+				return nil, fmt.Errorf("invalid token id %q of task %s", tc.TokenID, tc.TaskName)
+			}
+			tokenID = id.String()
+		}
+		c.tasks = append(c.tasks, &task{name: tc.TaskName, kind: tc.Kind, tokenID: tokenID, count: count})
+	}
+	return c, nil
+}
+
+type nftIndexer struct {
+	*indexer.Runner
+	db          *sql.DB
+	dao         DAO
+	ledger      ledger.Ledger
+	collections map[eth.Address]*collection
+}
+
+func (n *nftIndexer) process(eventCtx *common.EventContext) error {
+	if eventCtx.Receipt.Status != types.ReceiptStatusSuccessful {
+		return nil
+	}
+
+	for _, ethLog := range eventCtx.Receipt.Logs {
+		c, ok := n.collections[ethLog.Address]
+		if !ok {
+			continue
+		}
+
+		transfers, err := c.decode(ethLog)
+		if err != nil {
+			log.Errorf("[nft indexer] failed to decode transfer of %s in tx %s: %v", c.name, ethLog.TxHash.Hex(), err)
+			return err
+		}
+		if len(transfers) == 0 {
+			continue
+		}
+
+		key := ledger.NewKey(eventCtx.ChainID, ethLog.TxHash, int64(ethLog.Index), IndexerName)
+		_, err = n.ledger.Apply(key, eventCtx.BlockHeader.Number.Uint64(), func(exec common.Executor) error {
+			return n.apply(exec, c, eventCtx, ethLog, transfers)
+		})
+		if err != nil {
+			log.Errorf("[nft indexer] failed to apply transfer of %s in tx %s: %v", c.name, ethLog.TxHash.Hex(), err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decode returns the transfers in the log, or nothing if the log is not a transfer of the collection's standard
+func (c *collection) decode(ethLog *types.Log) ([]*transfer, error) {
+	if len(ethLog.Topics) != 4 {
+		// ERC-20 transfers share the same signature with ERC-721 ones, but the value is not indexed
+		return nil, nil
+	}
+	abiName := goclient.ABIERC721
+	if c.standard == StandardERC1155 {
+		abiName = goclient.ABIERC1155
+	}
+	event, err := goclient.GetABIRegistry().DecodeLog(abiName, ethLog)
+	if errors.Is(err, goclient.ErrUnknownEvent) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from, okFrom := event.Args["from"].(eth.Address)
+	to, okTo := event.Args["to"].(eth.Address)
+	if !okFrom || !okTo {
+		return nil, fmt.Errorf("invalid %s event", event.Event)
+	}
+	switch event.Event {
+	case eventTransfer:
+		tokenID, ok := event.Args["tokenId"].(*big.Int)
+		if !ok {
+			return nil, errors.New("invalid Transfer event")
+		}
+		return []*transfer{{from: from, to: to, tokenID: tokenID, amount: big.NewInt(1)}}, nil
+	case eventTransferSingle:
+		id, okID := event.Args["id"].(*big.Int)
+		value, okValue := event.Args["value"].(*big.Int)
+		if !okID || !okValue {
+			return nil, errors.New("invalid TransferSingle event")
+		}
+		return []*transfer{{from: from, to: to, tokenID: id, amount: value}}, nil
+	case eventTransferBatch:
+		ids, okIDs := event.Args["ids"].([]*big.Int)
+		values, okValues := event.Args["values"].([]*big.Int)
+		if !okIDs || !okValues || len(ids) != len(values) {
+			return nil, errors.New("invalid TransferBatch event")
+		}
+		transfers := make([]*transfer, len(ids))
+		for i := range ids {
+			transfers[i] = &transfer{from: from, to: to, tokenID: ids[i], amount: values[i]}
+		}
+		return transfers, nil
+	default:
+		return nil, nil
+	}
+}
+
+// apply records the mints and balance changes of a log and completes the tasks of the receivers,
+// everything is written in the same db transaction as the ledger record.
+func (n *nftIndexer) apply(exec common.Executor, c *collection, eventCtx *common.EventContext, ethLog *types.Log, transfers []*transfer) error {
+	collectionID := c.address.Hex()
+	receivers := make(map[eth.Address]bool)
+	for _, t := range transfers {
+		tokenID := t.tokenID.String()
+		if (t.from == eth.Address{}) {
+			err := n.dao.AddMint(exec, &Mint{
+				Collection:  collectionID,
+				TokenID:     tokenID,
+				Minter:      t.to.Hex(),
+				Amount:      t.amount.String(),
+				TxHash:      ethLog.TxHash.Hex(),
+				LogIndex:    ethLog.Index,
+				BlockNumber: eventCtx.BlockHeader.Number.Uint64(),
+				BlockTime:   eventCtx.BlockHeader.Time,
+			})
+			if err != nil {
+				return err
+			}
+			log.Debugf("[nft indexer] %s minted %s of token %s in %s", t.to.Hex(), t.amount, tokenID, c.name)
+		} else if err := n.dao.UpdateBalance(exec, collectionID, tokenID, t.from.Hex(), new(big.Int).Neg(t.amount)); err != nil {
+			return err
+		}
+
+		if (t.to != eth.Address{}) {
+			if err := n.dao.UpdateBalance(exec, collectionID, tokenID, t.to.Hex(), t.amount); err != nil {
+				return err
+			}
+			receivers[t.to] = true
+		}
+	}
+
+	for receiver := range receivers {
+		for _, tk := range c.tasks {
+			var count *big.Int
+			var err error
+			if tk.kind == TaskKindMint {
+				count, err = n.dao.MintedCount(exec, collectionID, receiver.Hex(), tk.tokenID)
+			} else {
+				count, err = n.dao.HeldCount(exec, collectionID, receiver.Hex(), tk.tokenID)
+			}
+			if err != nil {
+				return err
+			}
+			if count.Cmp(tk.count) < 0 {
+				continue
+			}
+
+			log.Infof("[nft indexer] %s completed %s task %s of %s", receiver.Hex(), tk.kind, tk.name, c.name)
+			if _, err := biz.CompleteTaskByName(exec, receiver.Hex(), tk.name, ethLog.TxHash.Hex()); err != nil {
+				log.Error("[nft indexer] failed to complete task", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (n *nftIndexer) Metrics() interface{} {
+	mints := make(map[string]uint64, len(n.collections))
+	for _, c := range n.collections {
+		count, err := n.dao.GetMintCount(c.address.Hex())
+		if err != nil {
+			log.Error("[nft indexer] failed to get mint count", err)
+			continue
+		}
+		mints[c.name] = count
+	}
+
+	return struct {
+		WaitingTx int               `json:"waiting_tx"`
+		Mints     map[string]uint64 `json:"mints"`
+	}{
+		WaitingTx: n.Health().WaitingTx,
+		Mints:     mints,
+	}
+}
+
+func (n *nftIndexer) Name() string {
+	return IndexerName
+}
@@ -0,0 +1,177 @@
+package nft
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	transferEvent       = indexertest.LoadABI("../../contracts/nft/erc721.abi").Events[eventTransfer]
+	transferSingleEvent = indexertest.LoadABI("../../contracts/nft/erc1155.abi").Events[eventTransferSingle]
+	transferBatchEvent  = indexertest.LoadABI("../../contracts/nft/erc1155.abi").Events[eventTransferBatch]
+)
+
+func TestNewCollection(t *testing.T) {
+	contract := "0xa646F6607af459917EFc14957bADC0Eb87f6dA7c"
+	tests := []struct {
+		name    string
+		conf    *CollectionConfig
+		wantErr bool
+	}{
+		{
+			name: "erc721 with tasks",
+			conf: &CollectionConfig{Name: "badge", Contract: contract, Standard: StandardERC721, Tasks: []*TaskConfig{
+				{TaskName: "mint-badge", Kind: TaskKindMint},
+				{TaskName: "hold-badge", Kind: TaskKindHold, TokenID: "0x2a", Count: "2"},
+			}},
+		},
+		{
+			name:    "invalid contract",
+			conf:    &CollectionConfig{Name: "badge", Contract: "0x1234", Standard: StandardERC721},
+			wantErr: true,
+		},
+		{
+			name:    "unknown standard",
+			conf:    &CollectionConfig{Name: "badge", Contract: contract, Standard: "erc20"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown task kind",
+			conf:    &CollectionConfig{Name: "badge", Contract: contract, Standard: StandardERC1155, Tasks: []*TaskConfig{{TaskName: "t", Kind: "burn"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid count",
+			conf:    &CollectionConfig{Name: "badge", Contract: contract, Standard: StandardERC1155, Tasks: []*TaskConfig{{TaskName: "t", Kind: TaskKindHold, Count: "0"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newCollection(tt.conf)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "42", c.tasks[1].tokenID)
+			require.Equal(t, int64(1), c.tasks[0].count.Int64())
+		})
+	}
+}
+
+func TestNFTIndexer(t *testing.T) {
+	badge := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+	items := eth.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	zero := eth.Address{}
+
+	tests := []struct {
+		name       string
+		logs       func(user, other eth.Address) []*types.Log
+		collection eth.Address
+		tokenID    string
+		minted     int64
+		held       int64
+	}{
+		{
+			name: "erc721 mint",
+			logs: func(user, _ eth.Address) []*types.Log {
+				return []*types.Log{indexertest.Log(badge, transferEvent, zero, user, big.NewInt(1))}
+			},
+			collection: badge,
+			minted:     1,
+			held:       1,
+		},
+		{
+			name: "erc721 minted then transferred",
+			logs: func(user, other eth.Address) []*types.Log {
+				return []*types.Log{
+					indexertest.Log(badge, transferEvent, zero, user, big.NewInt(1)),
+					indexertest.Log(badge, transferEvent, user, other, big.NewInt(1)),
+				}
+			},
+			collection: badge,
+			minted:     1,
+		},
+		{
+			name: "erc721 received",
+			logs: func(user, other eth.Address) []*types.Log {
+				return []*types.Log{
+					indexertest.Log(badge, transferEvent, zero, other, big.NewInt(7)),
+					indexertest.Log(badge, transferEvent, other, user, big.NewInt(7)),
+				}
+			},
+			collection: badge,
+			tokenID:    "7",
+			held:       1,
+		},
+		{
+			name: "erc1155 single and batch mints",
+			logs: func(user, other eth.Address) []*types.Log {
+				return []*types.Log{
+					indexertest.Log(items, transferSingleEvent, other, zero, user, big.NewInt(1), big.NewInt(3)),
+					indexertest.Log(items, transferBatchEvent, other, zero, user, []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(2), big.NewInt(5)}),
+				}
+			},
+			collection: items,
+			tokenID:    "1",
+			minted:     5,
+			held:       5,
+		},
+		{
+			name: "erc1155 burned",
+			logs: func(user, other eth.Address) []*types.Log {
+				return []*types.Log{
+					indexertest.Log(items, transferSingleEvent, other, zero, user, big.NewInt(1), big.NewInt(3)),
+					indexertest.Log(items, transferSingleEvent, user, user, zero, big.NewInt(1), big.NewInt(3)),
+				}
+			},
+			collection: items,
+			minted:     3,
+		},
+		{
+			name: "erc20 transfer ignored",
+			logs: func(user, _ eth.Address) []*types.Log {
+				erc20Transfer := indexertest.LoadABI("../../contracts/rug/rug.abi").Events["Transfer"]
+				return []*types.Log{indexertest.Log(badge, erc20Transfer, zero, user, big.NewInt(1))}
+			},
+			collection: badge,
+		},
+	}
+
+	options, err := json.Marshal(&Config{Collections: []*CollectionConfig{
+		{Name: "badge", Contract: badge.Hex(), Standard: StandardERC721},
+		{Name: "items", Contract: items.Hex(), Standard: StandardERC1155},
+	}})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := newMemoryDAO()
+			nftIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1, Options: options}, nil, dao, indexertest.NewLedger())
+			require.NoError(t, err)
+			indexertest.Start(t, nftIndexer)
+
+			user, other := indexertest.NewAccount(), indexertest.NewAccount()
+			tx := indexertest.NewChain().NextBlock(1).Tx(user, tt.collection, nil, tt.logs(user.Address, other.Address)...)
+			// replayed logs must be applied only once
+			indexertest.MustFeed(t, nftIndexer, tx, tx)
+
+			minted, err := dao.MintedCount(nil, tt.collection.Hex(), user.Address.Hex(), tt.tokenID)
+			require.NoError(t, err)
+			require.Equal(t, tt.minted, minted.Int64())
+			held, err := dao.HeldCount(nil, tt.collection.Hex(), user.Address.Hex(), tt.tokenID)
+			require.NoError(t, err)
+			require.Equal(t, tt.held, held.Int64())
+		})
+	}
+}
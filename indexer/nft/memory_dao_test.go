@@ -0,0 +1,84 @@
+package nft
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type memoryDAO struct {
+	mu       sync.Mutex
+	mints    []*Mint
+	balances map[[3]string]*big.Int
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{balances: make(map[[3]string]*big.Int)}
+}
+
+func (dao *memoryDAO) AddMint(_ common.Executor, mint *Mint) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	dao.mints = append(dao.mints, mint)
+	return nil
+}
+
+func (dao *memoryDAO) UpdateBalance(_ common.Executor, collection, tokenID, owner string, delta *big.Int) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	key := [3]string{collection, owner, tokenID}
+	if _, ok := dao.balances[key]; !ok {
+		dao.balances[key] = new(big.Int)
+	}
+	dao.balances[key].Add(dao.balances[key], delta)
+	return nil
+}
+
+func (dao *memoryDAO) MintedCount(_ common.Executor, collection, address, tokenID string) (*big.Int, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	count := new(big.Int)
+	for _, mint := range dao.mints {
+		if mint.Collection == collection && mint.Minter == address && (tokenID == "" || mint.TokenID == tokenID) {
+			amount, _ := new(big.Int).SetString(mint.Amount, 10)
+			count.Add(count, amount)
+		}
+	}
+	return count, nil
+}
+
+func (dao *memoryDAO) HeldCount(_ common.Executor, collection, address, tokenID string) (*big.Int, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	count := new(big.Int)
+	for key, balance := range dao.balances {
+		if key[0] == collection && key[1] == address && (tokenID == "" || key[2] == tokenID) && balance.Sign() > 0 {
+			count.Add(count, balance)
+		}
+	}
+	return count, nil
+}
+
+func (dao *memoryDAO) GetMintCount(collection string) (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var count uint64
+	for _, mint := range dao.mints {
+		if mint.Collection == collection {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (dao *memoryDAO) GetActivity(collection, address, tokenID string) (*Activity, error) {
+	minted, _ := dao.MintedCount(nil, collection, address, tokenID)
+	held, _ := dao.HeldCount(nil, collection, address, tokenID)
+	return &Activity{Minted: minted, Held: held}, nil
+}
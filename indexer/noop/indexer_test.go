@@ -0,0 +1,46 @@
+package noop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexer(t *testing.T) {
+	contract := eth.HexToAddress("0xa646F6607af459917EFc14957bADC0Eb87f6dA7c")
+
+	tests := []struct {
+		name string
+		txs  func(block *indexertest.Block, user *indexertest.Account) []*common.EventContext
+	}{
+		{
+			name: "successful tx",
+			txs: func(block *indexertest.Block, user *indexertest.Account) []*common.EventContext {
+				return []*common.EventContext{block.Tx(user, contract, nil)}
+			},
+		},
+		{
+			name: "failed tx",
+			txs: func(block *indexertest.Block, user *indexertest.Account) []*common.EventContext {
+				return []*common.EventContext{block.FailedTx(user, contract, nil)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer, err := newNoopIndexer(context.Background(), nil, "", nil)
+			require.NoError(t, err)
+			indexertest.Start(t, indexer)
+
+			events := tt.txs(indexertest.NewChain().NextBlock(1), indexertest.NewAccount())
+			for _, err := range indexertest.Feed(t, indexer, events...) {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package quest
+
+import "github.com/artela-network/galxe-integration/config"
+
+type Config struct {
+	Quests []*QuestConfig `json:"quests"`
+}
+
+// QuestConfig describes an ordered sequence of events an address has to emit within the time window.
+// Once the sequence completes, the task named TaskName (if any) is marked as succeeded for the address.
+type QuestConfig struct {
+	Name     string        `json:"name"`
+	TaskName string        `json:"task_name"`
+	Window   string        `json:"window"`
+	Steps    []*StepConfig `json:"steps"`
+}
+
+type StepConfig struct {
+	Name string `json:"name"`
+	config.EventFilter
+}
@@ -0,0 +1,133 @@
+package quest
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// QuestAddress is an address making progress in a quest
+type QuestAddress struct {
+	Quest   string
+	Address string
+}
+
+type DAO interface {
+	AddEvent(quest, address string, event *StepEvent) error
+	// GetAddressesSince returns the addresses with events from the block number on.
+	GetAddressesSince(blockNumber uint64) ([]*QuestAddress, error)
+	// DropEvents drops the events of the address from the block number on.
+	DropEvents(exec common.Executor, quest, address string, blockNumber uint64) error
+	GetEvents(quest, address string) ([]*StepEvent, error)
+	GetCompletion(quest, address string) (*Completion, error)
+	SaveCompletion(exec common.Executor, quest, address string, completion *Completion) error
+	DeleteCompletion(exec common.Executor, quest, address string) error
+	GetCompletionCount(quest string) (uint64, error)
+	// Transact runs fn in a db transaction, nothing fn wrote is kept if it fails.
+	Transact(fn func(exec common.Executor) error) error
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+func (dao *postgresDAO) AddEvent(quest, address string, event *StepEvent) error {
+	// we may receive duplicate logs when blocks are retried, need to ignore the conflicts
+	_, err := dao.conn.Exec("INSERT INTO quest_events (quest, address, step, block_number, block_hash, tx_hash, log_index, block_time) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (quest, address, step, tx_hash, log_index) DO NOTHING",
+		quest, address, event.Step, event.BlockNumber, event.BlockHash, event.TxHash, event.LogIndex, event.BlockTime)
+	return err
+}
+
+func (dao *postgresDAO) GetAddressesSince(blockNumber uint64) ([]*QuestAddress, error) {
+	rows, err := dao.conn.Query("SELECT DISTINCT quest, address FROM quest_events WHERE block_number >= $1", blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []*QuestAddress
+	for rows.Next() {
+		address := &QuestAddress{}
+		if err := rows.Scan(&address.Quest, &address.Address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+func (dao *postgresDAO) DropEvents(exec common.Executor, quest, address string, blockNumber uint64) error {
+	_, err := exec.Exec("DELETE FROM quest_events WHERE quest = $1 AND address = $2 AND block_number >= $3", quest, address, blockNumber)
+	return err
+}
+
+func (dao *postgresDAO) GetEvents(quest, address string) ([]*StepEvent, error) {
+	rows, err := dao.conn.Query("SELECT step, block_number, block_hash, tx_hash, log_index, block_time FROM quest_events "+
+		"WHERE quest = $1 AND address = $2 ORDER BY block_number, log_index", quest, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*StepEvent
+	for rows.Next() {
+		event := &StepEvent{}
+		if err := rows.Scan(&event.Step, &event.BlockNumber, &event.BlockHash, &event.TxHash, &event.LogIndex, &event.BlockTime); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (dao *postgresDAO) GetCompletion(quest, address string) (*Completion, error) {
+	completion := &Completion{Start: &StepEvent{}, End: &StepEvent{}}
+	err := dao.conn.QueryRow("SELECT start_tx_hash, end_tx_hash, block_number, block_time FROM quest_completions WHERE quest = $1 AND address = $2",
+		quest, address).Scan(&completion.Start.TxHash, &completion.End.TxHash, &completion.End.BlockNumber, &completion.End.BlockTime)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return completion, nil
+}
+
+func (dao *postgresDAO) SaveCompletion(exec common.Executor, quest, address string, completion *Completion) error {
+	_, err := exec.Exec("INSERT INTO quest_completions (quest, address, start_tx_hash, end_tx_hash, block_number, block_time) "+
+		"VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (quest, address) DO NOTHING",
+		quest, address, completion.Start.TxHash, completion.End.TxHash, completion.End.BlockNumber, completion.End.BlockTime)
+	return err
+}
+
+func (dao *postgresDAO) DeleteCompletion(exec common.Executor, quest, address string) error {
+	_, err := exec.Exec("DELETE FROM quest_completions WHERE quest = $1 AND address = $2", quest, address)
+	return err
+}
+
+func (dao *postgresDAO) GetCompletionCount(quest string) (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM quest_completions WHERE quest = $1", quest).Scan(&count)
+	return count, err
+}
+
+func (dao *postgresDAO) Transact(fn func(exec common.Executor) error) error {
+	tx, err := dao.conn.Begin()
+	if err != nil {
+		return err
+	}
+	// Defer a rollback in case anything fails.
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
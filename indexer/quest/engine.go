@@ -0,0 +1,79 @@
+package quest
+
+import (
+	"sort"
+	"time"
+)
+
+// StepEvent is an on-chain event that matched one of the quest steps for an address.
+type StepEvent struct {
+	Step        int
+	BlockNumber uint64
+	BlockHash   string
+	TxHash      string
+	LogIndex    uint
+	BlockTime   uint64
+}
+
+func (e *StepEvent) before(other *StepEvent) bool {
+	if e.BlockNumber != other.BlockNumber {
+		return e.BlockNumber < other.BlockNumber
+	}
+	return e.LogIndex < other.LogIndex
+}
+
+// Completion is the sequence of events that completed a quest.
+type Completion struct {
+	Start *StepEvent
+	End   *StepEvent
+}
+
+// evaluate replays all known step events of an address in chain order and returns the earliest
+// completed sequence, or nil if the quest is not completed. Since progress is always derived from
+// the full event set, events delivered out of order, replayed or dropped by a reorg are handled
+// without keeping any mutable state machine in the db.
+func evaluate(steps int, window time.Duration, events []*StepEvent) *Completion {
+	if steps == 0 || len(events) == 0 {
+		return nil
+	}
+
+	sorted := make([]*StepEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].before(sorted[j])
+	})
+
+	var best *Completion
+	for i, start := range sorted {
+		if start.Step != 0 {
+			continue
+		}
+		if best != nil && !start.before(best.End) {
+			// any sequence starting here would end after the one we already found
+			break
+		}
+
+		next, prev := 1, start
+		if next == steps {
+			return &Completion{Start: start, End: start}
+		}
+		for _, event := range sorted[i+1:] {
+			if window > 0 && time.Duration(event.BlockTime-start.BlockTime)*time.Second > window {
+				break
+			}
+			if event.Step != next || !prev.before(event) {
+				continue
+			}
+			prev = event
+			next++
+			if next == steps {
+				if best == nil || event.before(best.End) {
+					best = &Completion{Start: start, End: event}
+				}
+				break
+			}
+		}
+	}
+
+	return best
+}
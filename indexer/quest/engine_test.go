@@ -0,0 +1,114 @@
+package quest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func stepAt(step int, block uint64, logIndex uint, blockTime uint64) *StepEvent {
+	return &StepEvent{
+		Step:        step,
+		BlockNumber: block,
+		LogIndex:    logIndex,
+		BlockTime:   blockTime,
+		TxHash:      "0x" + string(rune('a'+step)),
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		steps    int
+		window   time.Duration
+		events   []*StepEvent
+		complete bool
+		endBlock uint64
+	}{
+		{
+			name:   "no events",
+			steps:  3,
+			window: day,
+		},
+		{
+			name:     "in order within window",
+			steps:    3,
+			window:   day,
+			events:   []*StepEvent{stepAt(0, 1, 0, 100), stepAt(1, 2, 0, 200), stepAt(2, 3, 0, 300)},
+			complete: true,
+			endBlock: 3,
+		},
+		{
+			name:     "delivered out of order",
+			steps:    3,
+			window:   day,
+			events:   []*StepEvent{stepAt(2, 3, 0, 300), stepAt(0, 1, 0, 100), stepAt(1, 2, 0, 200)},
+			complete: true,
+			endBlock: 3,
+		},
+		{
+			name:   "wrong order on chain",
+			steps:  3,
+			window: day,
+			events: []*StepEvent{stepAt(1, 1, 0, 100), stepAt(0, 2, 0, 200), stepAt(2, 3, 0, 300)},
+		},
+		{
+			name:   "window exceeded",
+			steps:  2,
+			window: time.Hour,
+			events: []*StepEvent{stepAt(0, 1, 0, 0), stepAt(1, 2, 0, 3601)},
+		},
+		{
+			name:     "restart after window exceeded",
+			steps:    2,
+			window:   time.Hour,
+			events:   []*StepEvent{stepAt(0, 1, 0, 0), stepAt(0, 5, 0, 4000), stepAt(1, 6, 0, 4100)},
+			complete: true,
+			endBlock: 6,
+		},
+		{
+			name:     "no window",
+			steps:    2,
+			events:   []*StepEvent{stepAt(0, 1, 0, 0), stepAt(1, 2, 0, 1000000)},
+			complete: true,
+			endBlock: 2,
+		},
+		{
+			name:     "same block ordered by log index",
+			steps:    2,
+			window:   day,
+			events:   []*StepEvent{stepAt(1, 1, 3, 100), stepAt(0, 1, 1, 100)},
+			complete: true,
+			endBlock: 1,
+		},
+		{
+			name:   "same log does not count twice",
+			steps:  2,
+			window: day,
+			events: []*StepEvent{stepAt(0, 1, 1, 100), stepAt(1, 1, 1, 100)},
+		},
+		{
+			name:     "single step",
+			steps:    1,
+			window:   day,
+			events:   []*StepEvent{stepAt(0, 7, 0, 100)},
+			complete: true,
+			endBlock: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completion := evaluate(tt.steps, tt.window, tt.events)
+			if !tt.complete {
+				require.Nil(t, completion)
+				return
+			}
+			require.NotNil(t, completion)
+			require.Equal(t, tt.endBlock, completion.End.BlockNumber)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package quest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	IndexerName = "Quest"
+
+	lockStripes = 64
+)
+
+type quest struct {
+	name     string
+	taskName string
+	window   time.Duration
+	steps    []*indexer.EventMatcher
+}
+
+func newQuestIndexer(ctx context.Context, conf *config.IndexerConfig, driver string, db *sql.DB) (common.Indexer, error) {
+	if err := indexer.RequirePostgres(IndexerName, driver); err != nil {
+		return nil, err
+	}
+	questIndexer, err := newIndexer(ctx, conf, newPostgresDAO(db))
+	if err != nil {
+		return nil, err
+	}
+	return questIndexer, nil
+}
+
+func newIndexer(ctx context.Context, conf *config.IndexerConfig, dao DAO) (*questIndexer, error) {
+	questConf := &Config{}
+	if err := json.Unmarshal(conf.Options, questConf); err != nil {
+		return nil, fmt.Errorf("failed to parse quest indexer options: %w", err)
+	}
+
+	quests := make([]*quest, 0, len(questConf.Quests))
+	for _, qc := range questConf.Quests {
+		q, err := newQuest(qc)
+		if err != nil {
+			return nil, err
+		}
+		quests = append(quests, q)
+	}
+
+	if conf.Thread == 0 {
+		conf.Thread = uint64(runtime.NumCPU())*2 + 1
+	}
+
+	questIndexer := &questIndexer{
+		dao:    dao,
+		quests: quests,
+	}
+	questIndexer.Runner = indexer.NewRunner(ctx, IndexerName, conf.Thread, questIndexer.process)
+
+	return questIndexer, nil
+}
+
+func newQuest(conf *QuestConfig) (*quest, error) {
+	if conf.Name == "" || len(conf.Steps) == 0 {
+		return nil, errors.New("quest must have a name and at least one step")
+	}
+
+	var window time.Duration
+	if conf.Window != "" {
+		var err error
+		if window, err = time.ParseDuration(conf.Window); err != nil {
+			return nil, fmt.Errorf("invalid window of quest %s: %w", conf.Name, err)
+		}
+	}
+
+	steps := make([]*indexer.EventMatcher, len(conf.Steps))
+	for i, step := range conf.Steps {
+		matcher, err := indexer.NewEventMatcher(&step.EventFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step %d of quest %s: %w", i, conf.Name, err)
+		}
+		steps[i] = matcher
+	}
+
+	return &quest{
+		name:     conf.Name,
+		taskName: conf.TaskName,
+		window:   window,
+		steps:    steps,
+	}, nil
+}
+
+type questIndexer struct {
+	*indexer.Runner
+	dao    DAO
+	quests []*quest
+
+	// progress of the same address must be evaluated sequentially
+	locks [lockStripes]sync.Mutex
+}
+
+func (q *questIndexer) process(eventCtx *common.EventContext) error {
+	for _, ethLog := range eventCtx.Receipt.Logs {
+		for _, qst := range q.quests {
+			for i, step := range qst.steps {
+				if !step.Match(ethLog) {
+					continue
+				}
+
+				args, err := step.Decode(ethLog)
+				if err != nil {
+					log.Errorf("[quest indexer] failed to decode %s event: %v", step.EventName(), err)
+					return err
+				}
+				address, err := step.Address(eventCtx.Transaction, args)
+				if err != nil {
+					log.Errorf("[quest indexer] failed to get address of %s event: %v", step.EventName(), err)
+					return err
+				}
+
+				event := &StepEvent{
+					Step:        i,
+					BlockNumber: eventCtx.BlockHeader.Number.Uint64(),
+					BlockHash:   eventCtx.BlockHeader.Hash().Hex(),
+					TxHash:      ethLog.TxHash.Hex(),
+					LogIndex:    ethLog.Index,
+					BlockTime:   eventCtx.BlockHeader.Time,
+				}
+				if err := q.advance(qst, address.Hex(), event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *questIndexer) advance(qst *quest, address string, event *StepEvent) error {
+	lock := q.lock(qst.name, address)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := q.dao.AddEvent(qst.name, address, event); err != nil {
+		log.Error("[quest indexer] failed to add step event", err)
+		return err
+	}
+
+	events, err := q.dao.GetEvents(qst.name, address)
+	if err != nil {
+		log.Error("[quest indexer] failed to load step events", err)
+		return err
+	}
+
+	existing, err := q.dao.GetCompletion(qst.name, address)
+	if err != nil {
+		log.Error("[quest indexer] failed to load completion", err)
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	completion := evaluate(len(qst.steps), qst.window, events)
+	if completion == nil {
+		return nil
+	}
+	return q.dao.Transact(func(exec common.Executor) error {
+		return q.complete(exec, qst, address, completion)
+	})
+}
+
+// Rewind drops the events from the block on, as they may not be canonical anymore, and reverts the completions
+// which do not hold without them. The blocks are processed again by the fetcher, which records the canonical
+// events and completions again.
+func (q *questIndexer) Rewind(blockNumber uint64) error {
+	addresses, err := q.dao.GetAddressesSince(blockNumber)
+	if err != nil {
+		log.Error("[quest indexer] failed to load addresses to rewind", err)
+		return err
+	}
+
+	quests := make(map[string]*quest, len(q.quests))
+	for _, qst := range q.quests {
+		quests[qst.name] = qst
+	}
+	for _, qa := range addresses {
+		qst, ok := quests[qa.Quest]
+		if !ok {
+			continue
+		}
+		if err := q.rewind(qst, qa.Address, blockNumber); err != nil {
+			return err
+		}
+	}
+	log.Warnf("[quest indexer] rewound %d addresses to block %d", len(addresses), blockNumber)
+	return nil
+}
+
+// rewind drops the events of the address from the block on and reverts its completion if it does not hold
+// anymore, in the same db transaction so a failed rewind is found again by the next one
+func (q *questIndexer) rewind(qst *quest, address string, blockNumber uint64) error {
+	lock := q.lock(qst.name, address)
+	lock.Lock()
+	defer lock.Unlock()
+
+	events, err := q.dao.GetEvents(qst.name, address)
+	if err != nil {
+		log.Error("[quest indexer] failed to load step events", err)
+		return err
+	}
+	existing, err := q.dao.GetCompletion(qst.name, address)
+	if err != nil {
+		log.Error("[quest indexer] failed to load completion", err)
+		return err
+	}
+
+	var kept []*StepEvent
+	for _, event := range events {
+		if event.BlockNumber < blockNumber {
+			kept = append(kept, event)
+		}
+	}
+	revert := existing != nil && evaluate(len(qst.steps), qst.window, kept) == nil
+
+	return q.dao.Transact(func(exec common.Executor) error {
+		if err := q.dao.DropEvents(exec, qst.name, address, blockNumber); err != nil {
+			log.Error("[quest indexer] failed to drop step events", err)
+			return err
+		}
+		if !revert {
+			return nil
+		}
+		log.Warnf("[quest indexer] completion of %s in quest %s is no longer canonical", address, qst.name)
+		return q.revert(exec, qst, address, existing)
+	})
+}
+
+// complete saves the completion and completes the task of the quest, it runs in the transaction of the caller
+// so the completion is evaluated again when the block is retried if completing the task fails.
+func (q *questIndexer) complete(exec common.Executor, qst *quest, address string, completion *Completion) error {
+	if err := q.dao.SaveCompletion(exec, qst.name, address, completion); err != nil {
+		log.Error("[quest indexer] failed to save completion", err)
+		return err
+	}
+	if qst.taskName != "" {
+		if _, err := biz.CompleteTaskByName(exec, address, qst.taskName, completion.End.TxHash); err != nil {
+			log.Error("[quest indexer] failed to complete task", err)
+			return err
+		}
+	}
+	log.Infof("[quest indexer] %s completed quest %s @ block[%d]", address, qst.name, completion.End.BlockNumber)
+	return nil
+}
+
+// revert deletes a completion which is no longer canonical, and takes back the task it completed, it runs in
+// the transaction of the caller
+func (q *questIndexer) revert(exec common.Executor, qst *quest, address string, completion *Completion) error {
+	if err := q.dao.DeleteCompletion(exec, qst.name, address); err != nil {
+		log.Error("[quest indexer] failed to delete completion", err)
+		return err
+	}
+	if qst.taskName == "" {
+		return nil
+	}
+	reverted, err := biz.RevertTaskByName(exec, address, qst.taskName, completion.End.TxHash,
+		fmt.Sprintf("completion of quest %s dropped by a reorg", qst.name))
+	if err != nil {
+		log.Error("[quest indexer] failed to revert task", err)
+		return err
+	}
+	if reverted > 0 {
+		log.Warnf("[quest indexer] reverted task %s of %s", qst.taskName, address)
+	}
+	return nil
+}
+
+func (q *questIndexer) lock(quest, address string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(quest))
+	_, _ = h.Write([]byte(address))
+	return &q.locks[h.Sum32()%lockStripes]
+}
+
+func (q *questIndexer) Metrics() interface{} {
+	completions := make(map[string]uint64, len(q.quests))
+	for _, qst := range q.quests {
+		count, err := q.dao.GetCompletionCount(qst.name)
+		if err != nil {
+			log.Error("[quest indexer] failed to get completion count", err)
+			continue
+		}
+		completions[qst.name] = count
+	}
+
+	return struct {
+		WaitingTx   int               `json:"waiting_tx"`
+		Completions map[string]uint64 `json:"completions"`
+	}{
+		WaitingTx:   q.Health().WaitingTx,
+		Completions: completions,
+	}
+}
+
+func (q *questIndexer) Name() string {
+	return IndexerName
+}
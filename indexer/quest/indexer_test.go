@@ -0,0 +1,168 @@
+package quest
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testABI   = "../../contracts/rug/rug.abi"
+	testQuest = "approve-then-transfer"
+)
+
+func newTestIndexer(t *testing.T, token eth.Address) (*questIndexer, *memoryDAO) {
+	options, err := json.Marshal(&Config{Quests: []*QuestConfig{{
+		Name:   testQuest,
+		Window: "1h",
+		Steps: []*StepConfig{
+			{Name: "approve", EventFilter: config.EventFilter{Contract: token.Hex(), ABI: testABI, Event: "Approval", AddressField: "owner"}},
+			{Name: "transfer", EventFilter: config.EventFilter{Contract: token.Hex(), ABI: testABI, Event: "Transfer", AddressField: "from"}},
+		},
+	}}})
+	require.NoError(t, err)
+
+	dao := newMemoryDAO()
+	questIndexer, err := newIndexer(context.Background(), &config.IndexerConfig{Thread: 1, Options: options}, dao)
+	require.NoError(t, err)
+	indexertest.Start(t, questIndexer)
+	return questIndexer, dao
+}
+
+func TestQuestIndexer(t *testing.T) {
+	token := eth.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	spender := eth.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	tokenABI := indexertest.LoadABI(testABI)
+	approval, transfer := tokenABI.Events["Approval"], tokenABI.Events["Transfer"]
+
+	type step struct {
+		event    string
+		interval uint64
+		contract eth.Address
+	}
+	tests := []struct {
+		name     string
+		steps    []step
+		complete bool
+	}{
+		{
+			name:     "in order",
+			steps:    []step{{event: "Approval", interval: 1}, {event: "Transfer", interval: 60}},
+			complete: true,
+		},
+		{
+			name:  "wrong order",
+			steps: []step{{event: "Transfer", interval: 1}, {event: "Approval", interval: 60}},
+		},
+		{
+			name:  "window exceeded",
+			steps: []step{{event: "Approval", interval: 1}, {event: "Transfer", interval: 3601}},
+		},
+		{
+			name:  "other contract",
+			steps: []step{{event: "Approval", interval: 1}, {event: "Transfer", interval: 1, contract: spender}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questIndexer, dao := newTestIndexer(t, token)
+			chain := indexertest.NewChain()
+			user := indexertest.NewAccount()
+
+			var events []*common.EventContext
+			for _, s := range tt.steps {
+				contract := token
+				if s.contract != (eth.Address{}) {
+					contract = s.contract
+				}
+				block := chain.NextBlock(s.interval)
+				if s.event == "Approval" {
+					events = append(events, block.Tx(user, contract, nil, indexertest.Log(contract, approval, user.Address, spender, big.NewInt(1))))
+				} else {
+					events = append(events, block.Tx(user, contract, nil, indexertest.Log(contract, transfer, user.Address, spender, big.NewInt(1))))
+				}
+			}
+			indexertest.MustFeed(t, questIndexer, events...)
+			// replayed blocks must not change the outcome
+			indexertest.MustFeed(t, questIndexer, events...)
+
+			completion, err := dao.GetCompletion(testQuest, user.Address.Hex())
+			require.NoError(t, err)
+			require.Equal(t, tt.complete, completion != nil)
+		})
+	}
+}
+
+func TestQuestIndexerReorg(t *testing.T) {
+	token := eth.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	spender := eth.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	tokenABI := indexertest.LoadABI(testABI)
+	questIndexer, dao := newTestIndexer(t, token)
+
+	user := indexertest.NewAccount()
+	chain := indexertest.NewChain()
+	approve := chain.NextBlock(1).Tx(user, token, nil, indexertest.Log(token, tokenABI.Events["Approval"], user.Address, spender, big.NewInt(1)))
+	transfer := chain.NextBlock(1).Tx(user, token, nil, indexertest.Log(token, tokenABI.Events["Transfer"], user.Address, spender, big.NewInt(1)))
+	indexertest.MustFeed(t, questIndexer, approve, transfer)
+
+	completion, err := dao.GetCompletion(testQuest, user.Address.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, completion)
+
+	// the approval block is replaced by a sibling without the approval
+	fork := chain.Fork(approve.BlockHeader.Number.Uint64())
+	sibling := fork.NextBlock(1).Tx(user, token, nil, indexertest.Log(token, tokenABI.Events["Transfer"], user.Address, spender, big.NewInt(1)))
+	// the fetcher rewinds the indexer from the replaced block before processing the fork
+	require.NoError(t, questIndexer.Rewind(sibling.BlockHeader.Number.Uint64()))
+	indexertest.MustFeed(t, questIndexer, sibling)
+
+	completion, err = dao.GetCompletion(testQuest, user.Address.Hex())
+	require.NoError(t, err)
+	require.Nil(t, completion)
+}
+
+func TestQuestIndexerReorgAtOtherHeight(t *testing.T) {
+	token := eth.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	spender := eth.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	tokenABI := indexertest.LoadABI(testABI)
+	questIndexer, dao := newTestIndexer(t, token)
+
+	user, other := indexertest.NewAccount(), indexertest.NewAccount()
+	chain := indexertest.NewChain()
+	approve := chain.NextBlock(1).Tx(user, token, nil, indexertest.Log(token, tokenABI.Events["Approval"], user.Address, spender, big.NewInt(1)))
+	transfer := chain.NextBlock(1).Tx(user, token, nil, indexertest.Log(token, tokenABI.Events["Transfer"], user.Address, spender, big.NewInt(1)))
+	indexertest.MustFeed(t, questIndexer, approve, transfer)
+
+	// the replacing blocks carry no event of the user at the height of its approval, the rewind
+	// still sweeps the events of the user from the replaced blocks
+	fork := chain.Fork(approve.BlockHeader.Number.Uint64())
+	sibling := fork.NextBlock(1).Tx(other, token, nil, indexertest.Log(token, tokenABI.Events["Approval"], other.Address, spender, big.NewInt(1)))
+	require.NoError(t, questIndexer.Rewind(sibling.BlockHeader.Number.Uint64()))
+	indexertest.MustFeed(t, questIndexer, sibling)
+
+	completion, err := dao.GetCompletion(testQuest, user.Address.Hex())
+	require.NoError(t, err)
+	require.Nil(t, completion)
+	events, err := dao.GetEvents(testQuest, user.Address.Hex())
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	next := fork.NextBlock(1).Tx(user, token, nil, indexertest.Log(token, tokenABI.Events["Transfer"], user.Address, spender, big.NewInt(1)))
+	indexertest.MustFeed(t, questIndexer, next)
+
+	completion, err = dao.GetCompletion(testQuest, user.Address.Hex())
+	require.NoError(t, err)
+	require.Nil(t, completion)
+	events, err = dao.GetEvents(testQuest, user.Address.Hex())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, next.BlockHeader.Hash().Hex(), events[0].BlockHash)
+}
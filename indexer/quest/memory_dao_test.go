@@ -0,0 +1,136 @@
+package quest
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+type memoryDAO struct {
+	mu          sync.Mutex
+	events      map[string][]*StepEvent
+	completions map[string]*Completion
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{
+		events:      make(map[string][]*StepEvent),
+		completions: make(map[string]*Completion),
+	}
+}
+
+func (dao *memoryDAO) AddEvent(quest, address string, event *StepEvent) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	key := quest + "/" + address
+	for _, e := range dao.events[key] {
+		if e.Step == event.Step && e.TxHash == event.TxHash && e.LogIndex == event.LogIndex {
+			return nil
+		}
+	}
+	dao.events[key] = append(dao.events[key], event)
+	return nil
+}
+
+func (dao *memoryDAO) GetAddressesSince(blockNumber uint64) ([]*QuestAddress, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var addresses []*QuestAddress
+	for key, events := range dao.events {
+		for _, e := range events {
+			if e.BlockNumber >= blockNumber {
+				quest, address, _ := strings.Cut(key, "/")
+				addresses = append(addresses, &QuestAddress{Quest: quest, Address: address})
+				break
+			}
+		}
+	}
+	return addresses, nil
+}
+
+func (dao *memoryDAO) DropEvents(_ common.Executor, quest, address string, blockNumber uint64) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	key := quest + "/" + address
+	var kept []*StepEvent
+	for _, e := range dao.events[key] {
+		if e.BlockNumber < blockNumber {
+			kept = append(kept, e)
+		}
+	}
+	dao.events[key] = kept
+	return nil
+}
+
+func (dao *memoryDAO) GetEvents(quest, address string) ([]*StepEvent, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	return append([]*StepEvent(nil), dao.events[quest+"/"+address]...), nil
+}
+
+func (dao *memoryDAO) GetCompletion(quest, address string) (*Completion, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	return dao.completions[quest+"/"+address], nil
+}
+
+func (dao *memoryDAO) SaveCompletion(_ common.Executor, quest, address string, completion *Completion) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	key := quest + "/" + address
+	if _, ok := dao.completions[key]; !ok {
+		dao.completions[key] = completion
+	}
+	return nil
+}
+
+func (dao *memoryDAO) DeleteCompletion(_ common.Executor, quest, address string) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	delete(dao.completions, quest+"/"+address)
+	return nil
+}
+
+func (dao *memoryDAO) GetCompletionCount(quest string) (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	var count uint64
+	for key := range dao.completions {
+		if len(key) > len(quest) && key[:len(quest)+1] == quest+"/" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Transact calls fn with a nil executor, the events and completions are restored if it fails.
+func (dao *memoryDAO) Transact(fn func(exec common.Executor) error) error {
+	dao.mu.Lock()
+	events := make(map[string][]*StepEvent, len(dao.events))
+	for key, e := range dao.events {
+		events[key] = e
+	}
+	completions := make(map[string]*Completion, len(dao.completions))
+	for key, completion := range dao.completions {
+		completions[key] = completion
+	}
+	dao.mu.Unlock()
+
+	if err := fn(nil); err != nil {
+		dao.mu.Lock()
+		dao.events = events
+		dao.completions = completions
+		dao.mu.Unlock()
+		return err
+	}
+	return nil
+}
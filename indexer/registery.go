@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/artela-network/galxe-integration/common"
 	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/db/migrate"
 	"sync"
 )
 
@@ -33,3 +34,12 @@ func (r *Registry) GetIndexer(ctx context.Context, indexerConf *config.IndexerCo
 func GetRegistry() *Registry {
 	return &registry
 }
+
+// RequirePostgres rejects the db drivers other than postgres, for the indexers whose daos and task
+// updates are written in the postgres dialect
+func RequirePostgres(name, driver string) error {
+	if dialect, err := migrate.Dialect(driver); err != nil || dialect != migrate.DialectPostgres {
+		return fmt.Errorf("%s indexer only supports the postgres db driver, not %q", name, driver)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirePostgres(t *testing.T) {
+	require.NoError(t, RequirePostgres("Quest", "postgres"))
+	require.NoError(t, RequirePostgres("Quest", "postgresql"))
+
+	for _, driver := range []string{"sqlite3", "mysql", ""} {
+		require.ErrorContains(t, RequirePostgres("Quest", driver), "Quest indexer only supports the postgres db driver")
+	}
+}
@@ -0,0 +1,200 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artela-network/galxe-integration/common"
+	log "github.com/sirupsen/logrus"
+)
+
+type ProcessFunc func(eventCtx *common.EventContext) error
+
+// Runner implements the lifecycle shared by the indexers: it owns the input queue, runs the
+// workers calling the process function of the indexer, and keeps track of its health.
+// Indexers embed a Runner and only implement the processing logic.
+type Runner struct {
+	name        string
+	inputCh     chan *common.EventContext
+	concurrency uint64
+	process     ProcessFunc
+	loops       []func(ctx context.Context)
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stopCh     chan struct{}
+	workers    sync.WaitGroup
+	background sync.WaitGroup
+
+	mu              sync.Mutex
+	status          common.HealthStatus
+	processed       uint64
+	failed          uint64
+	lastProcessedAt time.Time
+	lastError       error
+	lastErrorAt     time.Time
+}
+
+func NewRunner(ctx context.Context, name string, concurrency uint64, process ProcessFunc) *Runner {
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	runnerCtx, cancel := context.WithCancel(ctx)
+	return &Runner{
+		name:        name,
+		inputCh:     make(chan *common.EventContext, 100),
+		concurrency: concurrency,
+		process:     process,
+		ctx:         runnerCtx,
+		cancel:      cancel,
+		stopCh:      make(chan struct{}),
+		status:      common.HealthStatusCreated,
+	}
+}
+
+// Go registers a background loop which runs from Start until Stop, fn must return once ctx is done.
+func (r *Runner) Go(fn func(ctx context.Context)) {
+	r.loops = append(r.loops, fn)
+}
+
+func (r *Runner) Input() chan<- *common.EventContext {
+	return r.inputCh
+}
+
+func (r *Runner) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status != common.HealthStatusCreated {
+		return fmt.Errorf("%s indexer has already been started", r.name)
+	}
+	r.status = common.HealthStatusRunning
+
+	for i := uint64(0); i < r.concurrency; i++ {
+		r.workers.Add(1)
+		go r.work()
+	}
+	for _, fn := range r.loops {
+		r.background.Add(1)
+		go func(fn func(ctx context.Context)) {
+			defer r.background.Done()
+			fn(r.ctx)
+		}(fn)
+	}
+
+	log.Infof("[%s indexer] started", r.name)
+	return nil
+}
+
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	if r.status != common.HealthStatusRunning {
+		r.mu.Unlock()
+		return
+	}
+	r.status = common.HealthStatusStopping
+	r.mu.Unlock()
+
+	log.Infof("[%s indexer] stopping, %d tx waiting", r.name, len(r.inputCh))
+	close(r.stopCh)
+	r.workers.Wait()
+	r.cancel()
+	r.background.Wait()
+
+	r.mu.Lock()
+	r.status = common.HealthStatusStopped
+	r.mu.Unlock()
+	log.Infof("[%s indexer] stopped", r.name)
+}
+
+func (r *Runner) work() {
+	defer r.workers.Done()
+
+	for {
+		select {
+		case eventCtx := <-r.inputCh:
+			r.handle(eventCtx)
+		case <-r.stopCh:
+			// drain the events which have already been submitted before exiting
+			for {
+				select {
+				case eventCtx := <-r.inputCh:
+					r.handle(eventCtx)
+				default:
+					return
+				}
+			}
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) handle(eventCtx *common.EventContext) {
+	err := r.safeProcess(eventCtx)
+	r.record(err)
+
+	select {
+	case eventCtx.ResultChan <- err:
+		log.Debugf("[%s indexer] processed tx[%s] @ block[%d] ", r.name, eventCtx.TxHash(), eventCtx.BlockNumber())
+	default:
+		close(eventCtx.ResultChan)
+		log.Infof("[%s indexer] result chan full", r.name)
+	}
+}
+
+func (r *Runner) safeProcess(eventCtx *common.EventContext) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Errorf("[%s indexer] panic: %v", r.name, p)
+			err = errors.New("indexer panic")
+		}
+	}()
+
+	return r.process(eventCtx)
+}
+
+func (r *Runner) record(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if err != nil {
+		r.failed++
+		r.lastError = err
+		r.lastErrorAt = now
+		return
+	}
+	r.processed++
+	r.lastProcessedAt = now
+}
+
+// Health reports the indexer as healthy when it is running and the latest tx has been processed successfully.
+func (r *Runner) Health() *common.Health {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health := &common.Health{
+		Status:      r.status,
+		WaitingTx:   len(r.inputCh),
+		ProcessedTx: r.processed,
+		FailedTx:    r.failed,
+	}
+	if !r.lastProcessedAt.IsZero() {
+		lastProcessedAt := r.lastProcessedAt
+		health.LastProcessedAt = &lastProcessedAt
+	}
+	if r.lastError != nil {
+		lastErrorAt := r.lastErrorAt
+		health.LastError = r.lastError.Error()
+		health.LastErrorAt = &lastErrorAt
+	}
+	health.Healthy = r.status == common.HealthStatusRunning &&
+		(r.lastError == nil || r.lastProcessedAt.After(r.lastErrorAt))
+
+	return health
+}
@@ -0,0 +1,67 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventCtx() (*common.EventContext, chan error) {
+	resCh := make(chan error, 1)
+	return &common.EventContext{
+		BlockHeader: &types.Header{Number: big.NewInt(1)},
+		Transaction: types.NewTx(&types.LegacyTx{}),
+		Receipt:     &types.Receipt{},
+		ResultChan:  resCh,
+	}, resCh
+}
+
+func TestRunnerDrainsOnStop(t *testing.T) {
+	runner := NewRunner(context.Background(), "test", 1, func(*common.EventContext) error {
+		return nil
+	})
+	runner.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+
+	results := make([]chan error, 5)
+	for i := range results {
+		var event *common.EventContext
+		event, results[i] = newEventCtx()
+		runner.Input() <- event
+	}
+
+	require.NoError(t, runner.Start())
+	require.Error(t, runner.Start())
+	runner.Stop()
+
+	for _, resCh := range results {
+		require.NoError(t, <-resCh)
+	}
+	health := runner.Health()
+	require.Equal(t, common.HealthStatusStopped, health.Status)
+	require.Equal(t, uint64(5), health.ProcessedTx)
+	require.False(t, health.Healthy)
+}
+
+func TestRunnerHealth(t *testing.T) {
+	runner := NewRunner(context.Background(), "test", 1, func(*common.EventContext) error {
+		panic("boom")
+	})
+	require.NoError(t, runner.Start())
+	defer runner.Stop()
+
+	event, resCh := newEventCtx()
+	runner.Input() <- event
+	require.Error(t, <-resCh)
+
+	health := runner.Health()
+	require.Equal(t, common.HealthStatusRunning, health.Status)
+	require.Equal(t, uint64(1), health.FailedTx)
+	require.Equal(t, "indexer panic", health.LastError)
+	require.False(t, health.Healthy)
+}
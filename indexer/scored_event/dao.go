@@ -0,0 +1,50 @@
+package scored_event
+
+import (
+	"database/sql"
+)
+
+type DAO interface {
+	AddPlayer(player string) error
+	GetPlayers() ([]string, error)
+	GetPlayerCount() (uint64, error)
+}
+
+type postgresDAO struct {
+	conn *sql.DB
+}
+
+func newPostgresDAO(db *sql.DB) DAO {
+	return &postgresDAO{conn: db}
+}
+
+func (dao *postgresDAO) AddPlayer(player string) error {
+	// we may receive duplicate logs here, need to ignore the conflicts
+	_, err := dao.conn.Exec("INSERT INTO scored_players(player) VALUES($1) ON CONFLICT (player) DO NOTHING", player)
+	return err
+}
+
+func (dao *postgresDAO) GetPlayers() ([]string, error) {
+	rows, err := dao.conn.Query("SELECT player FROM scored_players")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []string
+	for rows.Next() {
+		var player string
+		if err := rows.Scan(&player); err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+
+	return players, rows.Err()
+}
+
+func (dao *postgresDAO) GetPlayerCount() (uint64, error) {
+	var count uint64
+	err := dao.conn.QueryRow("SELECT COUNT(*) FROM scored_players").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,71 @@
+package scored_event
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/indexer/indexertest"
+	eth "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoredEventIndexer(t *testing.T) {
+	game := eth.HexToAddress("0xb8D6ad3f2fF5a7f0f3C5D7b2Bf8E44AFb5b3aDfe")
+	other := eth.HexToAddress("0x1a2B3c4D5e6F7a8B9c0D1e2F3a4B5c6D7e8F9a0B")
+	scored := indexertest.LoadABI("../../contracts/scored/scored.abi").Events["Scored"]
+	player := indexertest.NewAccount()
+
+	tests := []struct {
+		name    string
+		logs    []*types.Log
+		players []string
+	}{
+		{
+			name:    "score reaches 5",
+			logs:    []*types.Log{indexertest.Log(game, scored, player.Address, big.NewInt(5))},
+			players: []string{player.Address.Hex()},
+		},
+		{
+			name: "score below 5",
+			logs: []*types.Log{indexertest.Log(game, scored, player.Address, big.NewInt(4))},
+		},
+		{
+			name: "npc scored",
+			logs: []*types.Log{indexertest.Log(game, scored, eth.Address{}, big.NewInt(10))},
+		},
+		{
+			name: "other contract",
+			logs: []*types.Log{indexertest.Log(other, scored, player.Address, big.NewInt(10))},
+		},
+		{
+			name: "anonymous log",
+			logs: []*types.Log{{Address: game}},
+		},
+		{
+			name: "duplicate scores",
+			logs: []*types.Log{
+				indexertest.Log(game, scored, player.Address, big.NewInt(5)),
+				indexertest.Log(game, scored, player.Address, big.NewInt(6)),
+			},
+			players: []string{player.Address.Hex()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := newMemoryDAO()
+			scoredIndexer := newIndexer(context.Background(), &config.IndexerConfig{Contract: game.Hex(), Thread: 1}, dao)
+			indexertest.Start(t, scoredIndexer)
+
+			block := indexertest.NewChain().NextBlock(1)
+			indexertest.MustFeed(t, scoredIndexer, block.Tx(player, game, nil, tt.logs...))
+
+			players, err := dao.GetPlayers()
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.players, players)
+		})
+	}
+}
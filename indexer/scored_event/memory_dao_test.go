@@ -0,0 +1,42 @@
+package scored_event
+
+import (
+	"sort"
+	"sync"
+)
+
+type memoryDAO struct {
+	mu      sync.Mutex
+	players map[string]bool
+}
+
+func newMemoryDAO() *memoryDAO {
+	return &memoryDAO{players: make(map[string]bool)}
+}
+
+func (dao *memoryDAO) AddPlayer(player string) error {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	dao.players[player] = true
+	return nil
+}
+
+func (dao *memoryDAO) GetPlayers() ([]string, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	players := make([]string, 0, len(dao.players))
+	for player := range dao.players {
+		players = append(players, player)
+	}
+	sort.Strings(players)
+	return players, nil
+}
+
+func (dao *memoryDAO) GetPlayerCount() (uint64, error) {
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	return uint64(len(dao.players)), nil
+}
@@ -15,14 +15,28 @@ import (
 
 	"github.com/artela-network/galxe-integration/api"
 	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
 	"github.com/artela-network/galxe-integration/config"
 	"github.com/artela-network/galxe-integration/db"
+	"github.com/artela-network/galxe-integration/db/migrate"
+	"github.com/artela-network/galxe-integration/fetcher"
+	"github.com/artela-network/galxe-integration/fetcher/cosmos"
+	"github.com/artela-network/galxe-integration/goclient"
+	"github.com/artela-network/galxe-integration/indexer"
 	"github.com/artela-network/galxe-integration/logging"
 	_ "github.com/artela-network/galxe-integration/logging"
+	"github.com/artela-network/galxe-integration/notifier"
+	_ "github.com/artela-network/galxe-integration/notifier/slack"
 	cleaner "github.com/artela-network/galxe-integration/onchain/clearner"
+	"github.com/artela-network/galxe-integration/onchain/distributor"
 	"github.com/artela-network/galxe-integration/onchain/faucet"
+	"github.com/artela-network/galxe-integration/onchain/ranker"
 	"github.com/artela-network/galxe-integration/onchain/rug"
+	"github.com/artela-network/galxe-integration/onchain/syncer"
 	"github.com/artela-network/galxe-integration/onchain/updater"
+	"github.com/artela-network/galxe-integration/partner"
+	_ "github.com/artela-network/galxe-integration/partner/galxe"
+	"github.com/artela-network/galxe-integration/partner/goplus"
 )
 
 func main() {
@@ -42,30 +56,96 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	conf := loadConfig(*serviceConf)
-	biz.GoPlus_Config = conf.GoPlus
 	biz.Recaptcha_Config = conf.Recaptcha
 
+	if conf.ABI == nil {
+		conf.ABI = &config.ABIConfig{}
+	}
+	if err := goclient.GetABIRegistry().Load(conf.ABI.FillDefaults()); err != nil {
+		log.Fatalf("failed to load abis: %v", err)
+	}
+	if err := biz.LoadCatalog(conf.Campaigns); err != nil {
+		log.Fatalf("failed to load campaign catalog: %v", err)
+	}
+
 	conn, driver, err := db.GetDB(ctx, conf.DB)
 	if err != nil {
 		log.Fatalf("failed to connect to db: %v", err)
 	}
+	migrator, err := migrate.NewMigrator(conn, driver)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+	if flag.Arg(0) == "migrate" {
+		runMigrate(migrator, flag.Args()[1:])
+		return
+	}
+	if err := migrator.Check(); err != nil {
+		log.Fatalf("refusing to start, run the migrate command first: %v", err)
+	}
+	if err := biz.TagDefaultCampaign(conn); err != nil {
+		log.Fatalf("failed to tag tasks with the default campaign: %v", err)
+	}
+	if flag.Arg(0) == "import" {
+		runImport(conn, flag.Args()[1:])
+		return
+	}
+	if flag.Arg(0) == "export" {
+		runExport(conn, flag.Args()[1:])
+		return
+	}
+
+	notifiers := make([]common.Notifier, 0, len(conf.Notifiers))
+	for _, notifierConf := range conf.Notifiers {
+		notifierInstance := notifier.GetRegistry().GetNotifier(ctx, notifierConf)
+		if notifierInstance == nil {
+			log.Warnf("unknown notifier: %s", string(notifierConf))
+			continue
+		}
+		notifiers = append(notifiers, notifierInstance)
+	}
+
+	biz.SetPartners(loadPartners(ctx, conf))
 
-	// indexers := make([]common.Indexer, len(conf.Indexers))
-	// chainFetcher, err := fetcher.NewFetcher(ctx, conf.Fetcher, driver, conn)
-	// if err != nil {
-	// 	log.Fatalf("failed to create fetcher: %v", err)
-	// }
-	// for i, indexerConf := range conf.Indexers {
-	// 	indexerInstance, err := indexer.GetRegistry().GetIndexer(ctx, indexerConf, driver, conn)
-	// 	if err != nil {
-	// 		log.Fatalf("failed to create indexer: %v", err)
-	// 	}
-	// 	chainFetcher.RegisterIndexer(indexerInstance)
-	// 	indexers[i] = indexerInstance
-	// }
-	// chainFetcher.Start()
-
-	apiServer := api.NewServer(ctx, conf, driver, conn, nil, nil)
+	var chainFetcher, cosmosFetcher common.Fetcher
+	indexers := make([]common.Indexer, 0, len(conf.Indexers))
+	if conf.Fetcher != nil {
+		chainFetcher, err = fetcher.NewFetcher(ctx, conf.Fetcher, driver, conn)
+		if err != nil {
+			log.Fatalf("failed to create fetcher: %v", err)
+		}
+	}
+	if conf.Cosmos != nil {
+		cosmosFetcher, err = cosmos.NewFetcher(ctx, conf.Cosmos, driver, conn)
+		if err != nil {
+			log.Fatalf("failed to create cosmos fetcher: %v", err)
+		}
+	}
+	if chainFetcher != nil || cosmosFetcher != nil {
+		for _, indexerConf := range conf.Indexers {
+			indexerInstance, err := indexer.GetRegistry().GetIndexer(ctx, indexerConf, driver, conn)
+			if err != nil {
+				log.Fatalf("failed to create indexer: %v", err)
+			}
+			if err := indexerInstance.Start(); err != nil {
+				log.Fatalf("failed to start indexer: %v", err)
+			}
+			// each fetcher only keeps the indexers of its own txs
+			for _, f := range []common.Fetcher{chainFetcher, cosmosFetcher} {
+				if f != nil {
+					f.RegisterIndexer(indexerInstance)
+				}
+			}
+			indexers = append(indexers, indexerInstance)
+		}
+	}
+	for _, f := range []common.Fetcher{chainFetcher, cosmosFetcher} {
+		if f != nil {
+			f.Start()
+		}
+	}
+
+	apiServer := api.NewServer(ctx, conf, driver, conn, chainFetcher, indexers)
 	apiServer.Start()
 
 	rugServ, err := rug.NewRug(conn, conf.Rug)
@@ -89,14 +169,40 @@ func main() {
 	}
 	updaterServ.Start()
 
+	if conf.Distributor != nil && conf.Distributor.Enable {
+		distributorServ, err := distributor.NewDistributor(conn, conf.Distributor)
+		if err != nil {
+			log.Error("failed to start distributor service", err)
+			os.Exit(-1)
+		}
+		distributorServ.Start()
+	}
+
 	cleanerServ := cleaner.NewCleaner(conn)
 	cleanerServ.Start()
 
+	syncerServ := syncer.NewSyncer(ctx, conn, notifiers)
+	syncerServ.Start()
+
+	rankerServ := ranker.NewRanker(ctx, conn)
+	rankerServ.Start()
+
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, syscall.SIGHUP, syscall.SIGKILL, syscall.SIGINT)
 
 	<-c
 
+	// stop the fetcher first so the indexers can drain the blocks already dispatched
+	for _, f := range []common.Fetcher{chainFetcher, cosmosFetcher} {
+		if f != nil {
+			f.Stop()
+		}
+	}
+	for _, indexerInstance := range indexers {
+		indexerInstance.Stop()
+	}
+	syncerServ.Stop()
+	rankerServ.Stop()
 	apiServer.Stop()
 
 	cancel()
@@ -104,6 +210,38 @@ func main() {
 	log.Info("service exited")
 }
 
+// loadPartners builds the partners of config, the legacy biz_goplus config is the goplus partner unless
+// a goplus partner is configured
+func loadPartners(ctx context.Context, conf *config.Config) []common.Partner {
+	partners := make([]common.Partner, 0, len(conf.Partners)+1)
+	names := make(map[string]bool, len(conf.Partners))
+	for _, partnerConf := range conf.Partners {
+		partnerInstance, err := partner.GetRegistry().GetPartner(ctx, partnerConf)
+		if err != nil {
+			log.Fatalf("failed to create partner: %v", err)
+		}
+		partners = append(partners, partnerInstance)
+		names[partnerInstance.Name()] = true
+	}
+	if conf.GoPlus != nil && !names[goplus.PartnerName] {
+		goplusPartner, err := goplus.NewPartner(ctx, conf.GoPlus)
+		if err != nil {
+			log.Fatalf("failed to create goplus partner: %v", err)
+		}
+		partners = append(partners, goplusPartner)
+		names[goplus.PartnerName] = true
+	}
+
+	for _, campaign := range biz.GetCatalog().Campaigns() {
+		for _, name := range campaign.Partners {
+			if !names[name] {
+				log.Warnf("partner %s of campaign %s is not configured, its completions will be dead lettered", name, campaign.Name)
+			}
+		}
+	}
+	return partners
+}
+
 func loadConfig(configFile string) *config.Config {
 	// load config file
 	if strings.HasPrefix(configFile, ".") {
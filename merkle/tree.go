@@ -0,0 +1,112 @@
+// Package merkle builds the merkle trees of the airdrops the way the StandardMerkleTree of OpenZeppelin does,
+// so the roots and proofs match the ones of its javascript library and verify with MerkleProof.
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrEmptyTree = errors.New("merkle tree must have at least one leaf")
+
+// Leaf is a claim of an amount by an account
+type Leaf struct {
+	Account common.Address
+	Amount  *big.Int
+}
+
+// Hash returns keccak256(bytes.concat(keccak256(abi.encode(account, amount)))), the double hashed leaf of
+// the StandardMerkleTree of OpenZeppelin
+func (l *Leaf) Hash() common.Hash {
+	encoded := make([]byte, 0, 64)
+	encoded = append(encoded, common.LeftPadBytes(l.Account.Bytes(), 32)...)
+	encoded = append(encoded, math.U256Bytes(new(big.Int).Set(l.Amount))...)
+	return crypto.Keccak256Hash(crypto.Keccak256(encoded))
+}
+
+// Tree is a merkle tree stored as an array, the root first and the leaves last, sorted by hash
+type Tree struct {
+	nodes  []common.Hash
+	leaves []*Leaf
+}
+
+// NewTree builds the tree of the leaves
+func NewTree(leaves []*Leaf) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+	for _, leaf := range leaves {
+		if leaf.Amount == nil || leaf.Amount.Sign() < 0 || leaf.Amount.BitLen() > 256 {
+			return nil, errors.New("leaf amount must be an uint256")
+		}
+	}
+
+	type hashed struct {
+		leaf *Leaf
+		hash common.Hash
+	}
+	sorted := make([]hashed, len(leaves))
+	for i, leaf := range leaves {
+		sorted[i] = hashed{leaf, leaf.Hash()}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].hash[:], sorted[j].hash[:]) < 0
+	})
+
+	t := &Tree{nodes: make([]common.Hash, 2*len(leaves)-1), leaves: make([]*Leaf, len(leaves))}
+	for i, h := range sorted {
+		t.nodes[len(t.nodes)-1-i] = h.hash
+		t.leaves[i] = h.leaf
+	}
+	for i := len(t.nodes) - 1 - len(leaves); i >= 0; i-- {
+		t.nodes[i] = HashPair(t.nodes[2*i+1], t.nodes[2*i+2])
+	}
+	return t, nil
+}
+
+// HashPair hashes the sorted pair of nodes
+func HashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+func (t *Tree) Root() common.Hash {
+	return t.nodes[0]
+}
+
+// Leaves returns the leaves in the order of the tree, the order their proofs are indexed by
+func (t *Tree) Leaves() []*Leaf {
+	return t.leaves
+}
+
+// Proof returns the proof of the i-th leaf of Leaves
+func (t *Tree) Proof(i int) []common.Hash {
+	index := len(t.nodes) - 1 - i
+	var proof []common.Hash
+	for index > 0 {
+		sibling := index + 1
+		if index%2 == 0 {
+			sibling = index - 1
+		}
+		proof = append(proof, t.nodes[sibling])
+		index = (index - 1) / 2
+	}
+	return proof
+}
+
+// Verify checks the proof of the leaf against the root, as MerkleProof.verify does
+func Verify(root common.Hash, leaf *Leaf, proof []common.Hash) bool {
+	computed := leaf.Hash()
+	for _, node := range proof {
+		computed = HashPair(computed, node)
+	}
+	return computed == root
+}
@@ -0,0 +1,50 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// the example of the README of @openzeppelin/merkle-tree
+func TestStandardMerkleTree(t *testing.T) {
+	amount := func(s string) *big.Int {
+		n, _ := new(big.Int).SetString(s, 10)
+		return n
+	}
+	tree, err := NewTree([]*Leaf{
+		{Account: common.HexToAddress("0x1111111111111111111111111111111111111111"), Amount: amount("5000000000000000000")},
+		{Account: common.HexToAddress("0x2222222222222222222222222222222222222222"), Amount: amount("2500000000000000000")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77", tree.Root().Hex())
+	require.Equal(t, []common.Hash{
+		common.HexToHash("0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77"),
+		common.HexToHash("0xeb02c421cfa48976e66dfb29120745909ea3a0f843456c263cf8f1253483e283"),
+		common.HexToHash("0xb92c48e9d7abe27fd8dfd6b5dfdbfb1c9a463f80c712b66f3a5180a090cccafc"),
+	}, tree.nodes)
+}
+
+func TestProofs(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 5, 8, 13} {
+		leaves := make([]*Leaf, size)
+		for i := range leaves {
+			leaves[i] = &Leaf{Account: common.BigToAddress(big.NewInt(int64(i + 1))), Amount: big.NewInt(int64(100 * (i + 1)))}
+		}
+		tree, err := NewTree(leaves)
+		require.NoError(t, err)
+
+		for i, leaf := range tree.Leaves() {
+			require.True(t, Verify(tree.Root(), leaf, tree.Proof(i)), "leaf %d of %d", i, size)
+			forged := &Leaf{Account: leaf.Account, Amount: new(big.Int).Add(leaf.Amount, big.NewInt(1))}
+			require.False(t, Verify(tree.Root(), forged, tree.Proof(i)))
+		}
+	}
+
+	_, err := NewTree(nil)
+	require.ErrorIs(t, err, ErrEmptyTree)
+	_, err = NewTree([]*Leaf{{Account: common.Address{}, Amount: big.NewInt(-1)}})
+	require.Error(t, err)
+}
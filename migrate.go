@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/db/migrate"
+)
+
+const migrateUsage = "usage: migrate up [version] | down [steps] | status"
+
+// runMigrate runs the migrate command: up applies the pending migrations up to the version, or all of them,
+// down reverts the last steps migrations, 1 by default, and status prints the applied and pending versions.
+func runMigrate(migrator *migrate.Migrator, args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+	number := func(def int64) int64 {
+		if len(args) < 2 {
+			return def
+		}
+		n, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || n < 0 {
+			log.Fatalf("invalid number %s, %s", args[1], migrateUsage)
+		}
+		return n
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(number(0))
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate up failed: %v", err)
+		}
+	case "down":
+		reverted, err := migrator.Down(int(number(1)))
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate down failed: %v", err)
+		}
+	case "status":
+		version, err := migrator.Version()
+		if err != nil {
+			log.Fatalf("failed to get schema version: %v", err)
+		}
+		pending, err := migrator.Pending(0)
+		if err != nil {
+			log.Fatalf("failed to get pending migrations: %v", err)
+		}
+		fmt.Printf("version %d, latest %d\n", version, migrator.Latest())
+		for _, migration := range pending {
+			fmt.Printf("pending %d_%s\n", migration.Version, migration.Name)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
@@ -22,9 +22,9 @@ func (c *cleaner) Start() {
 		for {
 			rowsAffected, err := biz.LetTimeoutRecordRetry(c.db)
 			if err != nil {
-				log.Error("cleaner: reset status from 2 to 1 failed")
+				log.Errorf("cleaner: release timed out tasks failed: %v", err)
 			}
-			log.Debugf("cleaner: reset status from 2 to 1, %d rows affected", rowsAffected)
+			log.Debugf("cleaner: released or abandoned timed out tasks, %d rows affected", rowsAffected)
 			time.Sleep(onchain.CleanDBInterval)
 		}
 	}()
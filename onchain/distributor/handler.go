@@ -0,0 +1,120 @@
+package distributor
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/config"
+	"github.com/artela-network/galxe-integration/contracts/distributor"
+	"github.com/artela-network/galxe-integration/onchain"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Distributor publishes the merkle roots of the queued airdrops to the distributor contract. The airdrops
+// go through the base as tasks, with the id of the airdrop as ID and its root as Memo.
+type Distributor struct {
+	*onchain.Base
+	contract       *distributor.Distributor
+	conf           *config.DistributorConfig
+	publishTimeout time.Duration
+}
+
+func NewDistributor(db *sql.DB, conf *config.DistributorConfig) (*Distributor, error) {
+	conf.FillDefaults()
+
+	if !conf.Enable {
+		return &Distributor{}, nil
+	}
+
+	publishTimeout, err := time.ParseDuration(conf.PublishTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publish timeout %s: %w", conf.PublishTimeout, err)
+	}
+
+	base, err := onchain.NewBase(db, &conf.OnChain, false)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Distributor{
+		Base:           base,
+		conf:           conf,
+		publishTimeout: publishTimeout,
+	}
+
+	d.refreshContract()
+
+	base.RegisterGetTasks(d.getTasks)
+	base.RegisterSend(d.send)
+	base.RegisterUpdateTask(d.updateTask)
+	base.RegisterRefreshNetwork(d.refreshContract)
+	return d, nil
+}
+
+func (s *Distributor) refreshContract() bool {
+	contractAddress := common.HexToAddress(s.conf.ContractAddress)
+	instance, err := distributor.NewDistributor(contractAddress, s.Client())
+	if err != nil {
+		log.Error("distributor module: load distributor failed", err)
+		return false
+	}
+	s.contract = instance
+	return true
+}
+
+func (s *Distributor) getTasks(count int) ([]biz.AddressTask, error) {
+	// airdrops left publishing would otherwise never be published nor queued again
+	if failed, err := biz.FailStaleAirdrops(s.DB(), s.publishTimeout); err != nil {
+		log.Error("distributor module: fail stale airdrops failed", err)
+	} else if failed > 0 {
+		log.Warnf("distributor module: failed %d airdrops publishing for longer than %s", failed, s.publishTimeout)
+	}
+
+	airdrops, err := biz.LockAirdropsToPublish(s.DB(), count)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]biz.AddressTask, len(airdrops))
+	for i, airdrop := range airdrops {
+		root := airdrop.MerkleRoot
+		tasks[i] = biz.AddressTask{ID: airdrop.ID, Memo: &root}
+	}
+	return tasks, nil
+}
+
+func (s *Distributor) send(task biz.AddressTask) (hashs []common.Hash, err error) {
+	if task.Memo == nil {
+		log.Error("distributor module: airdrop has no merkle root", task.ID)
+		return nil, onchain.ErrInvalidTask
+	}
+
+	root := common.HexToHash(*task.Memo)
+	log.Infof("distributor module: publishing root %s of airdrop %d", root.Hex(), task.ID)
+	tx, err := s.contract.SetMerkleRoot(s.DefaultOpts(&s.conf.TxConfig), root)
+	if err != nil {
+		log.Debug("distributor module: submit root failed", task.ID, err)
+		return nil, err
+	}
+	return []common.Hash{tx.Hash()}, nil
+}
+
+func (s *Distributor) updateTask(task biz.AddressTask, hashs []common.Hash, status *uint64) error {
+	hexs := make([]string, len(hashs))
+	for i, hash := range hashs {
+		hexs[i] = hash.Hex()
+	}
+
+	var published *bool
+	if status != nil {
+		ok := *status == 1
+		published = &ok
+		log.Infof("distributor module: airdrop %d root published %v, txs %s", task.ID, ok, strings.Join(hexs, ","))
+	}
+	return biz.UpdateAirdropPublication(s.DB(), task.ID, strings.Join(hexs, ","), published)
+}
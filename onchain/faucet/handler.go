@@ -2,6 +2,7 @@ package faucet
 
 import (
 	"database/sql"
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -45,6 +46,7 @@ func NewFaucet(db *sql.DB, conf *config.FaucetConfig) (*Faucet, error) {
 	base.RegisterGetTasks(f.getTasks)
 	base.RegisterSend(f.send)
 	base.RegisterUpdateTask(f.updateTask)
+	base.RegisterFailTask(f.failTask)
 	base.RegisterRefreshNetwork(f.refreshContract)
 	return f, nil
 }
@@ -101,12 +103,19 @@ func (s *Faucet) updateTask(task biz.AddressTask, hashs []common.Hash, status *u
 
 	req := &biz.UpdateTaskQuery{}
 	req.ID = task.ID
+	req.Actor = biz.HandlerFaucet
 	req.Txs = &memo
 	if status != nil {
 		taskStatus := string(types.TaskStatusFail)
 		if *status == 1 {
 			taskStatus = string(types.TaskStatusSuccess)
 		}
+		if taskStatus == string(types.TaskStatusFail) {
+			reason := fmt.Sprintf("receipt status %d", *status)
+			failCode := biz.FailCodeReceipt
+			req.Reason = &reason
+			req.FailCode = &failCode
+		}
 		req.TaskStatus = &taskStatus
 		log.Debugf("faucet module: updating task, %d, hash %s, status %s\n", req.ID, *req.Txs, *req.TaskStatus)
 	} else {
@@ -115,3 +124,7 @@ func (s *Faucet) updateTask(task biz.AddressTask, hashs []common.Hash, status *u
 
 	return biz.UpdateTask(s.DB(), req)
 }
+
+func (s *Faucet) failTask(task biz.AddressTask, err error) error {
+	return biz.FailTask(s.DB(), task.ID, biz.HandlerFaucet, biz.FailCodeSend, err.Error())
+}
@@ -13,6 +13,7 @@ type (
 	GetTasks       func(count int) ([]biz.AddressTask, error)
 	UpdateTask     func(task biz.AddressTask, hashs []common.Hash, status *uint64) error
 	RefreshNetwork func() bool
+	FailTask       func(task biz.AddressTask, err error) error
 )
 
 const (
@@ -29,8 +30,17 @@ const (
 
 	Reconnect = 200 * time.Millisecond
 
-	CleanDBInterval = 10 * time.Minute
-	MaxRetry        = 50
+	CleanDBInterval = time.Minute
+	// MaxRetry is how many times a task is sent before it fails
+	MaxRetry = 50
+
+	SyncInterval    = 10 * time.Second
+	SyncLease       = 2 * time.Minute
+	SyncBackoff     = 30 * time.Second
+	SyncMaxBackoff  = 2 * time.Hour
+	SyncMaxAttempts = 12
+
+	RankInterval = 30 * time.Second
 )
 
 var (
@@ -0,0 +1,63 @@
+package ranker
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/onchain"
+	log "github.com/sirupsen/logrus"
+)
+
+// ranker precomputes the leaderboard ranks every onchain.RankInterval, so reading a rank or a page of
+// the leaderboard never sorts the balances. It backfills the points of the tasks completed before they
+// were given points once it starts.
+type ranker struct {
+	db *sql.DB
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewRanker(ctx context.Context, db *sql.DB) *ranker {
+	ctx, cancel := context.WithCancel(ctx)
+	return &ranker{db: db, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+}
+
+func (r *ranker) Start() {
+	go func() {
+		defer close(r.done)
+		if err := biz.BackfillPoints(r.db); err != nil {
+			log.Errorf("ranker: failed to backfill points: %v", err)
+		}
+		for {
+			r.process()
+			select {
+			case <-r.ctx.Done():
+				log.Info("ranker: stopped")
+				return
+			case <-time.After(onchain.RankInterval):
+			}
+		}
+	}()
+}
+
+// Stop stops the ranker once the ranks being refreshed are written
+func (r *ranker) Stop() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *ranker) process() {
+	start := time.Now()
+	moved, err := biz.RefreshRanks(r.db)
+	if err != nil {
+		log.Errorf("ranker: failed to refresh ranks: %v", err)
+		return
+	}
+	if moved > 0 {
+		log.Debugf("ranker: %d ranks moved in %s", moved, time.Since(start))
+	}
+}
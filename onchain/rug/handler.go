@@ -2,6 +2,7 @@ package rug
 
 import (
 	"database/sql"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
@@ -46,6 +47,7 @@ func NewRug(db *sql.DB, conf *config.RugConfig) (*Rug, error) {
 	base.RegisterGetTasks(f.getTasks)
 	base.RegisterSend(f.send)
 	base.RegisterUpdateTask(f.updateTask)
+	base.RegisterFailTask(f.failTask)
 	base.RegisterRefreshNetwork(f.refreshContract)
 	return f, nil
 }
@@ -107,12 +109,19 @@ func (s *Rug) updateTask(task biz.AddressTask, hashs []common.Hash, status *uint
 
 	req := &biz.UpdateTaskQuery{}
 	req.ID = task.ID
+	req.Actor = biz.HandlerRug
 	req.Txs = &memo
 	if status != nil {
 		taskStatus := string(types.TaskStatusFail)
 		if *status == 0 { // this task is expected to fail
 			taskStatus = string(types.TaskStatusSuccess)
 		}
+		if taskStatus == string(types.TaskStatusFail) {
+			reason := fmt.Sprintf("receipt status %d", *status)
+			failCode := biz.FailCodeReceipt
+			req.Reason = &reason
+			req.FailCode = &failCode
+		}
 		req.TaskStatus = &taskStatus
 		log.Debugf("Rug module: updating task, %d, hash %s, status %s\n", req.ID, *req.Txs, *req.TaskStatus)
 	} else {
@@ -121,3 +130,7 @@ func (s *Rug) updateTask(task biz.AddressTask, hashs []common.Hash, status *uint
 
 	return biz.UpdateTask(s.DB(), req)
 }
+
+func (s *Rug) failTask(task biz.AddressTask, err error) error {
+	return biz.FailTask(s.DB(), task.ID, biz.HandlerRug, biz.FailCodeSend, err.Error())
+}
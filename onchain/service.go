@@ -42,6 +42,10 @@ type Base struct {
 	getTasks       GetTasks
 	updateTask     UpdateTask
 	refreshNetwork RefreshNetwork
+	failTask       FailTask
+
+	// sendErrors counts the failed sends of the queued tasks
+	sendErrors map[int64]int
 
 	query bool // if this service is a query service
 }
@@ -63,6 +67,8 @@ func NewBase(db *sql.DB, conf *config.OnChain, query bool) (*Base, error) {
 		queue:  llq.New(),
 		conf:   conf,
 		query:  query,
+
+		sendErrors: make(map[int64]int),
 	}
 
 	if !query {
@@ -118,6 +124,12 @@ func (s *Base) RegisterRefreshNetwork(fn RefreshNetwork) {
 	s.refreshNetwork = fn
 }
 
+// RegisterFailTask sets how a task which cannot be sent is failed, by default it is updated as if its
+// transaction reverted
+func (s *Base) RegisterFailTask(fn FailTask) {
+	s.failTask = fn
+}
+
 func (s *Base) GetNonce() uint64 {
 	if s.query {
 		return 0
@@ -205,9 +217,14 @@ func (s *Base) handleTasks() {
 				// client is disconnected
 				s.updateNetwork()
 			}
-			if err != ErrInvalidTask {
+			s.sendErrors[task.ID]++
+			if err != ErrInvalidTask && s.sendErrors[task.ID] < MaxRetry {
 				s.queue.Enqueue(task)
+				return
 			}
+			log.Warnf("Base module: giving up sending task %d after %d attempts, %v", task.ID, s.sendErrors[task.ID], err)
+			delete(s.sendErrors, task.ID)
+			s.fail(task, err)
 		}
 
 		ch <- struct{}{}
@@ -220,6 +237,7 @@ func (s *Base) handleTasks() {
 			<-ch
 			continue
 		}
+		delete(s.sendErrors, task.ID)
 
 		err = s.updateTask(task, hashs, nil)
 		if err != nil {
@@ -233,6 +251,19 @@ func (s *Base) handleTasks() {
 	}
 }
 
+// fail fails a task which cannot be sent
+func (s *Base) fail(task biz.AddressTask, err error) {
+	if s.failTask != nil {
+		err = s.failTask(task, err)
+	} else {
+		status := uint64(0)
+		err = s.updateTask(task, nil, &status)
+	}
+	if err != nil {
+		log.Error("Base module: fail task failed", task.ID, err)
+	}
+}
+
 func (s *Base) processReceipt(task biz.AddressTask, hashs []common.Hash) {
 	var networkErr = func(err error) bool {
 		// log.Error("Base module: get receipt err", err)
@@ -270,6 +301,7 @@ func (s *Base) processReceipt(task biz.AddressTask, hashs []common.Hash) {
 			for _, receipt := range receipts {
 				if receipt.Status != 1 {
 					status = 0
+					s.logRevert(task, receipt)
 					break
 				}
 			}
@@ -282,6 +314,31 @@ func (s *Base) processReceipt(task biz.AddressTask, hashs []common.Hash) {
 	s.updateTask(task, hashs, &status)
 }
 
+// logRevert logs why the tx of the task reverted, decoded with the abi bound to the called contract
+func (s *Base) logRevert(task biz.AddressTask, receipt *coretypes.Receipt) {
+	tx, _, err := s.client.QueryTxByHash(context.Background(), receipt.TxHash)
+	if err != nil {
+		log.Debugf("Base module: failed to get reverted tx %s, %v", receipt.TxHash.Hex(), err)
+		return
+	}
+	data, err := s.client.ReplayRevert(context.Background(), tx, receipt)
+	if err != nil || len(data) == 0 {
+		log.Warnf("Base module: task %d tx %s reverted", task.ID, receipt.TxHash.Hex())
+		return
+	}
+
+	abiName := ""
+	if tx.To() != nil {
+		abiName, _ = goclient.GetABIRegistry().ByContract(*tx.To())
+	}
+	revert, err := goclient.GetABIRegistry().DecodeRevert(abiName, data)
+	if err != nil {
+		log.Warnf("Base module: task %d tx %s reverted with %x", task.ID, receipt.TxHash.Hex(), data)
+		return
+	}
+	log.Warnf("Base module: task %d tx %s reverted with %s %v", task.ID, receipt.TxHash.Hex(), revert.Error, goclient.JSONArgs(revert.Args))
+}
+
 func (s *Base) updateNetwork() {
 	if s.uptating.Load() {
 		return
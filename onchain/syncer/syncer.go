@@ -0,0 +1,100 @@
+package syncer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/artela-network/galxe-integration/api/biz"
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/onchain"
+	log "github.com/sirupsen/logrus"
+)
+
+const notifierSource = "partner-syncer"
+
+// syncer drains the partner sync outbox, reporting the completions to the partners. The failed reports are
+// retried with an exponential backoff and dead lettered after onchain.SyncMaxAttempts attempts.
+type syncer struct {
+	db        *sql.DB
+	notifiers []common.Notifier
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewSyncer(ctx context.Context, db *sql.DB, notifiers []common.Notifier) *syncer {
+	ctx, cancel := context.WithCancel(ctx)
+	return &syncer{db: db, notifiers: notifiers, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+}
+
+func (s *syncer) Start() {
+	go func() {
+		defer close(s.done)
+		for {
+			s.process()
+			select {
+			case <-s.ctx.Done():
+				log.Info("syncer: stopped")
+				return
+			case <-time.After(onchain.SyncInterval):
+			}
+		}
+	}()
+}
+
+// Stop stops the syncer once the items being reported are done
+func (s *syncer) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *syncer) process() {
+	items, err := biz.ClaimOutbox(s.db, onchain.PullBatchCount, onchain.SyncLease)
+	if err != nil {
+		log.Errorf("syncer: failed to claim outbox: %v", err)
+		return
+	}
+
+	for _, item := range items {
+		err := biz.ReportCompletion(s.db, item.Partner, item.Campaign, item.AccountAddress, item.TaskId)
+		if err == nil {
+			continue
+		}
+
+		attempts := item.Attempts + 1
+		dead := attempts >= onchain.SyncMaxAttempts
+		log.Warnf("syncer: attempt %d to sync %s of campaign %s failed: %v", attempts, item.AccountAddress, item.Campaign, err)
+		if err := biz.FailOutbox(s.db, item.ID, err.Error(), backoff(attempts), dead); err != nil {
+			log.Errorf("syncer: failed to record the failure of outbox item %d: %v", item.ID, err)
+			continue
+		}
+		if dead {
+			s.alert(item, attempts, err)
+		}
+	}
+}
+
+// alert notifies the dead lettered item, it waits for someone to retry it
+func (s *syncer) alert(item *biz.OutboxItem, attempts int, err error) {
+	msg := fmt.Sprintf("sync of %s for campaign %s to %s dead lettered after %d attempts (outbox id %d): %v",
+		item.AccountAddress, item.Campaign, item.Partner, attempts, item.ID, err)
+	log.Error("syncer: " + msg)
+	for _, notifier := range s.notifiers {
+		notifier.Notify(msg, notifierSource, false)
+	}
+}
+
+// backoff returns the wait before the next attempt, doubling from onchain.SyncBackoff
+func backoff(attempts int) time.Duration {
+	wait := onchain.SyncBackoff
+	for i := 1; i < attempts && wait < onchain.SyncMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > onchain.SyncMaxBackoff {
+		wait = onchain.SyncMaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,21 @@
+package syncer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/onchain"
+)
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, onchain.SyncBackoff, backoff(1))
+	require.Equal(t, 2*onchain.SyncBackoff, backoff(2))
+	require.Equal(t, 8*onchain.SyncBackoff, backoff(4))
+
+	// the wait is capped
+	require.Equal(t, onchain.SyncMaxBackoff, backoff(onchain.SyncMaxAttempts))
+	require.Equal(t, onchain.SyncMaxBackoff, backoff(1000))
+	require.Greater(t, onchain.SyncMaxBackoff, time.Duration(0))
+}
@@ -2,6 +2,7 @@ package updater
 
 import (
 	"database/sql"
+	"fmt"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -41,6 +42,7 @@ func NewUpdater(db *sql.DB, conf *config.UpdaterConfig) (*Updater, error) {
 	base.RegisterGetTasks(f.getTasks)
 	base.RegisterSend(f.send)
 	base.RegisterUpdateTask(f.updateTask)
+	base.RegisterFailTask(f.failTask)
 	base.RegisterRefreshNetwork(f.refreshContract)
 	return f, nil
 }
@@ -70,11 +72,18 @@ func (s *Updater) updateTask(task biz.AddressTask, hashs []common.Hash, status *
 
 	req := &biz.UpdateTaskQuery{}
 	req.ID = task.ID
+	req.Actor = biz.HandlerUpdater
 	if status != nil {
 		taskStatus := string(types.TaskStatusFail)
 		if *status == 1 {
 			taskStatus = string(types.TaskStatusSuccess)
 		}
+		if taskStatus == string(types.TaskStatusFail) {
+			reason := fmt.Sprintf("receipt status %d", *status)
+			failCode := biz.FailCodeReceipt
+			req.Reason = &reason
+			req.FailCode = &failCode
+		}
 		req.TaskStatus = &taskStatus
 		log.Debugf("Updater module: updating task, %d, hash %s, status %s\n", req.ID, memo, *req.TaskStatus)
 	} else {
@@ -83,3 +92,7 @@ func (s *Updater) updateTask(task biz.AddressTask, hashs []common.Hash, status *
 
 	return biz.UpdateTask(s.DB(), req)
 }
+
+func (s *Updater) failTask(task biz.AddressTask, err error) error {
+	return biz.FailTask(s.DB(), task.ID, biz.HandlerUpdater, biz.FailCodeSend, err.Error())
+}
@@ -0,0 +1,9 @@
+package galxe
+
+type Config struct {
+	URL            string            `json:"url"`
+	AccessToken    string            `json:"access_token"`
+	Credentials    map[string]string `json:"credentials"`
+	CallbackSecret string            `json:"callback_secret"`
+	Timeout        string            `json:"timeout"`
+}
@@ -0,0 +1,9 @@
+package galxe
+
+import (
+	"github.com/artela-network/galxe-integration/partner"
+)
+
+func init() {
+	partner.GetRegistry().Register(PartnerName, NewGalxePartner)
+}
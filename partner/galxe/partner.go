@@ -0,0 +1,212 @@
+package galxe
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+const (
+	PartnerName = "galxe"
+
+	defaultURL = "https://graphigo.prd.galaxy.eco/query"
+	// CallbackHeader carries the callback secret on the requests galxe sends us
+	CallbackHeader = "X-Galxe-Key"
+)
+
+var ErrInvalidCallback = errors.New("invalid galxe callback")
+
+const appendItemsMutation = `mutation credentialItems($credId: ID!, $operation: Operation!, $items: [String!]!) {
+  credentialItems(input: {credId: $credId, operation: $operation, items: $items}) {
+    name
+  }
+}`
+
+const eligibleQuery = `query credentialEligible($credId: ID!, $address: String!) {
+  credential(id: $credId) {
+    eligible(address: $address)
+  }
+}`
+
+type graphqlRequest struct {
+	OperationName string                 `json:"operationName"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Partner pushes the addresses completing a campaign to the galxe credential of the campaign, through the
+// graphql api of galxe
+type Partner struct {
+	url            string
+	accessToken    string
+	credentials    map[string]string
+	callbackSecret string
+
+	httpClient *http.Client
+	ctx        context.Context
+}
+
+func NewGalxePartner(ctx context.Context, rawConf json.RawMessage) (common.Partner, error) {
+	conf := &Config{}
+	if err := json.Unmarshal(rawConf, conf); err != nil {
+		return nil, fmt.Errorf("failed to parse galxe partner config: %w", err)
+	}
+	return NewPartner(ctx, conf)
+}
+
+func NewPartner(ctx context.Context, conf *Config) (*Partner, error) {
+	if conf.AccessToken == "" {
+		return nil, fmt.Errorf("galxe partner needs an access_token")
+	}
+	url := conf.URL
+	if url == "" {
+		url = defaultURL
+	}
+	timeout, err := time.ParseDuration(conf.Timeout)
+	if err != nil {
+		timeout = 10 * time.Second
+	}
+
+	return &Partner{
+		url:            url,
+		accessToken:    conf.AccessToken,
+		credentials:    conf.Credentials,
+		callbackSecret: conf.CallbackSecret,
+		httpClient:     &http.Client{Timeout: timeout},
+		ctx:            ctx,
+	}, nil
+}
+
+func (p *Partner) Name() string {
+	return PartnerName
+}
+
+// ReportCompletion appends the address to the credential of the campaign, galxe ignores addresses
+// already in the credential
+func (p *Partner) ReportCompletion(completion *common.Completion) error {
+	credId, err := p.credential(completion.Campaign)
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		CredentialItems struct {
+			Name string `json:"name"`
+		} `json:"credentialItems"`
+	}
+	err = p.call("credentialItems", appendItemsMutation, map[string]interface{}{
+		"credId":    credId,
+		"operation": "APPEND",
+		"items":     []string{strings.ToLower(completion.Address)},
+	}, &data)
+	if err != nil {
+		return err
+	}
+	log.Infof("galxe: appended %s to credential %s (%s)", completion.Address, credId, data.CredentialItems.Name)
+	return nil
+}
+
+// QueryStatus tells whether the address is eligible to the credential of the campaign
+func (p *Partner) QueryStatus(completion *common.Completion) (bool, error) {
+	credId, err := p.credential(completion.Campaign)
+	if err != nil {
+		return false, err
+	}
+
+	var data struct {
+		Credential *struct {
+			Eligible json.RawMessage `json:"eligible"`
+		} `json:"credential"`
+	}
+	err = p.call("credentialEligible", eligibleQuery, map[string]interface{}{
+		"credId":  credId,
+		"address": strings.ToLower(completion.Address),
+	}, &data)
+	if err != nil {
+		return false, err
+	}
+	if data.Credential == nil {
+		return false, fmt.Errorf("galxe credential %s not found", credId)
+	}
+	// galxe answers the eligibility as 0 or 1
+	eligible := strings.TrimSpace(string(data.Credential.Eligible))
+	return eligible == "1" || eligible == "true", nil
+}
+
+// VerifyCallback checks the request carries the callback secret, which is set as a header of the
+// credential on galxe
+func (p *Partner) VerifyCallback(req *http.Request, _ []byte) error {
+	if p.callbackSecret == "" {
+		return fmt.Errorf("%w: no callback secret configured", ErrInvalidCallback)
+	}
+	if subtle.ConstantTimeCompare([]byte(p.callbackSecret), []byte(req.Header.Get(CallbackHeader))) != 1 {
+		return ErrInvalidCallback
+	}
+	return nil
+}
+
+func (p *Partner) credential(campaign string) (string, error) {
+	credId, ok := p.credentials[campaign]
+	if !ok || credId == "" {
+		return "", fmt.Errorf("no galxe credential for campaign %s", campaign)
+	}
+	return credId, nil
+}
+
+// call runs the graphql operation and decodes its data into result
+func (p *Partner) call(operation, query string, variables map[string]interface{}, result interface{}) error {
+	payload, err := json.Marshal(&graphqlRequest{OperationName: operation, Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("access-token", p.accessToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("galxe %s responded %d: %s", operation, resp.StatusCode, string(body))
+	}
+
+	response := &graphqlResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to decode galxe %s response: %w", operation, err)
+	}
+	if len(response.Errors) > 0 {
+		messages := make([]string, 0, len(response.Errors))
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("galxe %s failed: %s", operation, strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(response.Data, result)
+}
@@ -0,0 +1,101 @@
+package galxe
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/common"
+)
+
+// stubGalxe serves the graphql operations of galxe, it records the credential items appended
+func stubGalxe(t *testing.T, items map[string][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("access-token") != "token" {
+			w.Write([]byte(`{"errors":[{"message":"unauthorized"}]}`))
+			return
+		}
+		req := &graphqlRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		credId := req.Variables["credId"].(string)
+
+		switch req.OperationName {
+		case "credentialItems":
+			require.Equal(t, "APPEND", req.Variables["operation"])
+			for _, item := range req.Variables["items"].([]interface{}) {
+				items[credId] = append(items[credId], item.(string))
+			}
+			w.Write([]byte(`{"data":{"credentialItems":{"name":"completed"}}}`))
+		case "credentialEligible":
+			eligible := 0
+			for _, item := range items[credId] {
+				if item == req.Variables["address"] {
+					eligible = 1
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"credential": map[string]interface{}{"eligible": eligible}},
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestReportCompletion(t *testing.T) {
+	items := make(map[string][]string)
+	server := stubGalxe(t, items)
+	defer server.Close()
+
+	p, err := NewPartner(context.Background(), &Config{
+		URL:         server.URL,
+		AccessToken: "token",
+		Credentials: map[string]string{"goplus": "42"},
+	})
+	require.NoError(t, err)
+
+	completion := &common.Completion{Campaign: "goplus", Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}
+	eligible, err := p.QueryStatus(completion)
+	require.NoError(t, err)
+	require.False(t, eligible)
+
+	require.NoError(t, p.ReportCompletion(completion))
+	require.Equal(t, []string{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}, items["42"])
+
+	eligible, err = p.QueryStatus(completion)
+	require.NoError(t, err)
+	require.True(t, eligible)
+
+	// campaigns without a credential are refused
+	require.ErrorContains(t, p.ReportCompletion(&common.Completion{Campaign: "other", Address: completion.Address}), "no galxe credential")
+}
+
+func TestReportCompletionError(t *testing.T) {
+	server := stubGalxe(t, make(map[string][]string))
+	defer server.Close()
+
+	p, err := NewPartner(context.Background(), &Config{
+		URL:         server.URL,
+		AccessToken: "wrong",
+		Credentials: map[string]string{"goplus": "42"},
+	})
+	require.NoError(t, err)
+	require.ErrorContains(t, p.ReportCompletion(&common.Completion{Campaign: "goplus", Address: "0x01"}), "unauthorized")
+
+	_, err = NewPartner(context.Background(), &Config{})
+	require.Error(t, err)
+}
+
+func TestVerifyCallback(t *testing.T) {
+	p, err := NewPartner(context.Background(), &Config{AccessToken: "token", CallbackSecret: "secret"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/credential/goplus/GetFaucet", nil)
+	require.ErrorIs(t, p.VerifyCallback(req, nil), ErrInvalidCallback)
+	req.Header.Set(CallbackHeader, "secret")
+	require.NoError(t, p.VerifyCallback(req, nil))
+}
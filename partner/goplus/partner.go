@@ -0,0 +1,172 @@
+package goplus
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+)
+
+const PartnerName = "goplus"
+
+// callbackMaxAge bounds the age of the timestamp of a signed callback
+const callbackMaxAge = 5 * time.Minute
+
+var ErrInvalidSign = errors.New("invalid goplus sign")
+
+type PostBody struct {
+	ChannelCode   string `json:"channelCode"`
+	ChannelTaskId string `json:"channelTaskId"`
+	CompleteTime  string `json:"completeTime"`
+	UserAddress   string `json:"userAddress"`
+}
+type ResponseData struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Result  struct {
+		Status bool `json:"status"`
+	} `json:"result"`
+}
+
+// Partner reports the completions to the secwarex api of goplus
+type Partner struct {
+	conf       *config.GoPlusConfig
+	httpClient *http.Client
+	ctx        context.Context
+}
+
+func NewGoPlusPartner(ctx context.Context, rawConf json.RawMessage) (common.Partner, error) {
+	conf := &config.GoPlusConfig{}
+	if err := json.Unmarshal(rawConf, conf); err != nil {
+		return nil, fmt.Errorf("failed to parse goplus partner config: %w", err)
+	}
+	return NewPartner(ctx, conf)
+}
+
+func NewPartner(ctx context.Context, conf *config.GoPlusConfig) (*Partner, error) {
+	if conf.SecwarexUrl == "" || conf.ChannelCode == "" {
+		return nil, fmt.Errorf("goplus partner needs secwarexUrl and channelCode")
+	}
+	return &Partner{
+		conf:       conf,
+		httpClient: &http.Client{Timeout: time.Second * 20},
+		ctx:        ctx,
+	}, nil
+}
+
+func (p *Partner) Name() string {
+	return PartnerName
+}
+
+func (p *Partner) ReportCompletion(completion *common.Completion) error {
+	postBody := &PostBody{
+		ChannelCode:   p.conf.ChannelCode,
+		ChannelTaskId: completion.TaskId,
+		CompleteTime:  strconv.FormatInt(completion.CompleteTime.Unix(), 10),
+		UserAddress:   completion.Address,
+	}
+
+	bytesData, _ := json.Marshal(postBody)
+	pstReq, err := http.NewRequestWithContext(p.ctx, http.MethodPost, p.conf.SecwarexUrl, bytes.NewReader(bytesData))
+	if err != nil {
+		return err
+	}
+
+	// header
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	sign, plaintext := p.sign(postBody, timestamp)
+	pstReq.Header.Add("Content-Type", "application/json")
+	pstReq.Header.Add("manageId", p.conf.ManageId)
+	pstReq.Header.Add("timestamp", timestamp)
+	pstReq.Header.Add("sign", sign)
+	log.Info("goplus|request|", p.conf.SecwarexUrl, "|body|", string(bytesData), "|sign|", sign, "|plaintext|", plaintext)
+
+	resp, err := p.httpClient.Do(pstReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	log.Info("goplus|response|", string(body), "|address|", completion.Address)
+
+	responseData := &ResponseData{}
+	if err := json.Unmarshal(body, responseData); err != nil {
+		return fmt.Errorf("goplus responded %d: %w", resp.StatusCode, err)
+	}
+	if !responseData.Result.Status {
+		return fmt.Errorf("goplus rejected the sync: %d %s", responseData.Code, responseData.Message)
+	}
+	return nil
+}
+
+// QueryStatus is not offered by the secwarex api
+func (p *Partner) QueryStatus(*common.Completion) (bool, error) {
+	return false, common.ErrUnsupported
+}
+
+// VerifyCallback checks the request is signed by goplus the way we sign our requests to it
+func (p *Partner) VerifyCallback(req *http.Request, body []byte) error {
+	if req.Header.Get("manageId") != p.conf.ManageId {
+		return ErrInvalidSign
+	}
+	timestamp := req.Header.Get("timestamp")
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSign
+	}
+	if age := time.Since(time.UnixMilli(millis)); age > callbackMaxAge || age < -callbackMaxAge {
+		return fmt.Errorf("%w: expired timestamp", ErrInvalidSign)
+	}
+
+	postBody := &PostBody{}
+	if err := json.Unmarshal(body, postBody); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSign, err)
+	}
+	sign, _ := p.sign(postBody, timestamp)
+	if subtle.ConstantTimeCompare([]byte(sign), []byte(req.Header.Get("sign"))) != 1 {
+		return ErrInvalidSign
+	}
+	return nil
+}
+
+// sign returns the md5 of the fields of the body, the manage key and the timestamp sorted by name,
+// along with the signed plaintext
+func (p *Partner) sign(body *PostBody, timestamp string) (string, string) {
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString("channelCode")
+	queryBuilder.WriteString(body.ChannelCode)
+
+	queryBuilder.WriteString("channelTaskId")
+	queryBuilder.WriteString(body.ChannelTaskId)
+
+	queryBuilder.WriteString("completeTime")
+	queryBuilder.WriteString(body.CompleteTime)
+
+	queryBuilder.WriteString("manageKey")
+	queryBuilder.WriteString(p.conf.ManageKey)
+
+	queryBuilder.WriteString("timestamp")
+	queryBuilder.WriteString(timestamp)
+
+	queryBuilder.WriteString("userAddress")
+	queryBuilder.WriteString(body.UserAddress)
+
+	plaintext := queryBuilder.String()
+	return fmt.Sprintf("%x", md5.Sum([]byte(plaintext))), plaintext
+}
@@ -0,0 +1,83 @@
+package goplus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+)
+
+func TestReportCompletion(t *testing.T) {
+	var p *Partner
+	accepted := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := &bytes.Buffer{}
+		_, err := body.ReadFrom(r.Body)
+		require.NoError(t, err)
+		// our requests are signed the way the callbacks are verified
+		require.NoError(t, p.VerifyCallback(r, body.Bytes()))
+
+		response := &ResponseData{Code: 200}
+		response.Result.Status = accepted
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	var err error
+	p, err = NewPartner(context.Background(), &config.GoPlusConfig{
+		ChannelCode: "artela",
+		ManageId:    "id",
+		ManageKey:   "key",
+		SecwarexUrl: server.URL,
+	})
+	require.NoError(t, err)
+
+	completion := &common.Completion{Campaign: "goplus", Address: "0x01", TaskId: "7", CompleteTime: time.Now()}
+	require.NoError(t, p.ReportCompletion(completion))
+
+	accepted = false
+	require.ErrorContains(t, p.ReportCompletion(completion), "rejected")
+
+	_, err = p.QueryStatus(completion)
+	require.ErrorIs(t, err, common.ErrUnsupported)
+}
+
+func TestVerifyCallbackInvalid(t *testing.T) {
+	p, err := NewPartner(context.Background(), &config.GoPlusConfig{ChannelCode: "artela", ManageId: "id", SecwarexUrl: "http://goplus"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/callback", nil)
+	req.Header.Set("manageId", "id")
+	req.Header.Set("timestamp", "0")
+	req.Header.Set("sign", "x")
+	require.ErrorIs(t, p.VerifyCallback(req, []byte(`{}`)), ErrInvalidSign)
+}
+
+func TestSign(t *testing.T) {
+	body := &PostBody{
+		ChannelCode:   "artela",
+		ChannelTaskId: "891b8fbef81c43c7aec3e4bfeea2c752",
+		CompleteTime:  "1697076853",
+		UserAddress:   "0x1dcabfc8807beb9c2314508f561a9ef43c9a2b03",
+	}
+	p, err := NewPartner(context.Background(), &config.GoPlusConfig{
+		ChannelCode: "artela",
+		ManageId:    "100005",
+		ManageKey:   "mqucjot7NBTBPSjEL95tCZ4HL3BtYllV",
+		SecwarexUrl: "http://goplus",
+	})
+	require.NoError(t, err)
+
+	sign, plaintext := p.sign(body, "1697076853000")
+	require.Equal(t, "channelCodeartelachannelTaskId891b8fbef81c43c7aec3e4bfeea2c752completeTime1697076853"+
+		"manageKeymqucjot7NBTBPSjEL95tCZ4HL3BtYllVtimestamp1697076853000userAddress0x1dcabfc8807beb9c2314508f561a9ef43c9a2b03", plaintext)
+	require.Len(t, sign, 32)
+}
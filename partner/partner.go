@@ -0,0 +1,41 @@
+package partner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/artela-network/galxe-integration/common"
+	"github.com/artela-network/galxe-integration/config"
+)
+
+type Builder func(ctx context.Context, rawConf json.RawMessage) (common.Partner, error)
+
+var registry Registry
+
+type Registry struct {
+	partners sync.Map
+}
+
+func (r *Registry) Register(tpy string, builder Builder) {
+	r.partners.Store(tpy, builder)
+}
+
+func (r *Registry) GetPartner(ctx context.Context, rawConf json.RawMessage) (common.Partner, error) {
+	typeConf := &config.TypeConf{}
+	if err := json.Unmarshal(rawConf, typeConf); err != nil {
+		return nil, fmt.Errorf("failed to parse partner config: %w", err)
+	}
+
+	builder, exist := r.partners.Load(typeConf.Type)
+	if !exist {
+		return nil, fmt.Errorf("unknown partner type %q", typeConf.Type)
+	}
+
+	return builder.(Builder)(ctx, rawConf)
+}
+
+func GetRegistry() *Registry {
+	return &registry
+}